@@ -0,0 +1,156 @@
+package rolling
+
+import (
+	"sync"
+	"time"
+)
+
+type anomalyThreshold struct {
+	score     float64
+	onEnter   func(score float64)
+	onExit    func(score float64)
+	triggered bool
+}
+
+func (t *anomalyThreshold) exceeded(score float64) bool {
+	if t.score < 0 {
+		return score <= t.score
+	}
+	return score >= t.score
+}
+
+// AnomalyDetector scores the aggregate of a short, current window against
+// the aggregate of one or more baseline windows that describe what is normal.
+// A baseline may be a long horizon window, such as the last day, or a set of
+// seasonal windows, such as the same hour yesterday and the same hour last
+// week, in which case the expected value is the average of their aggregates.
+//
+// The score is the relative deviation of the current aggregate from the
+// expected value. A score of 1 means the current value is double what was
+// expected and a score of -0.5 means it is half.
+type AnomalyDetector struct {
+	aggregate  func(Window) float64
+	current    Reducer
+	baselines  []Reducer
+	thresholds []*anomalyThreshold
+	lock       *sync.Mutex
+}
+
+// NewAnomalyDetector generates an AnomalyDetector that reduces the current
+// window and every baseline window with the same aggregating function.
+func NewAnomalyDetector(aggregate func(Window) float64, current Reducer, baselines ...Reducer) *AnomalyDetector {
+	return &AnomalyDetector{
+		aggregate: aggregate,
+		current:   current,
+		baselines: baselines,
+		lock:      &sync.Mutex{},
+	}
+}
+
+// Threshold registers functions that are called by Check when the score
+// crosses the given value. A positive threshold is crossed when the score
+// rises to or above it and a negative threshold when the score falls to or
+// below it. The onEnter function is called once when the threshold is
+// crossed and onExit once when the score returns. Either may be nil. The
+// functions are called while the detector is locked and must not call Check
+// or Threshold.
+func (d *AnomalyDetector) Threshold(score float64, onEnter func(score float64), onExit func(score float64)) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	d.thresholds = append(d.thresholds, &anomalyThreshold{score: score, onEnter: onEnter, onExit: onExit})
+}
+
+// Score returns the relative deviation of the current aggregate from the
+// expected value and whether a score could be computed. No score is available
+// when the expected value is zero.
+func (d *AnomalyDetector) Score() (float64, bool) {
+	var expected = 0.0
+	for _, baseline := range d.baselines {
+		expected = expected + baseline.Reduce(d.aggregate)
+	}
+	if len(d.baselines) > 0 {
+		expected = expected / float64(len(d.baselines))
+	}
+	if expected == 0 {
+		return 0, false
+	}
+	var current = d.current.Reduce(d.aggregate)
+	return (current - expected) / expected, true
+}
+
+// Check computes the score and calls the functions of every threshold whose
+// state has changed since the last Check. The score and whether one could be
+// computed are returned. Thresholds do not change state while no score is
+// available.
+func (d *AnomalyDetector) Check() (float64, bool) {
+	var score, ok = d.Score()
+	if !ok {
+		return score, ok
+	}
+
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	for _, threshold := range d.thresholds {
+		var exceeded = threshold.exceeded(score)
+		switch {
+		case exceeded && !threshold.triggered && threshold.onEnter != nil:
+			threshold.onEnter(score)
+		case !exceeded && threshold.triggered && threshold.onExit != nil:
+			threshold.onExit(score)
+		}
+		threshold.triggered = exceeded
+	}
+	return score, ok
+}
+
+// Reduce the score to a single value using a reduction function. The score
+// is given as a window containing a single value, or no values when a score
+// cannot be computed, so that the detector may be used anywhere a Reducer is
+// accepted, such as a Metric.
+func (d *AnomalyDetector) Reduce(f func(Window) float64) float64 {
+	var score, ok = d.Score()
+	if !ok {
+		return f(Window{{}})
+	}
+	return f(Window{{score}})
+}
+
+// laggedPolicy reduces the portion of a TimePolicy that ended some time ago.
+type laggedPolicy struct {
+	policy *TimePolicy
+	lag    time.Duration
+	span   time.Duration
+	window Window
+}
+
+// Lagged returns a Reducer over the buckets of the given window that began
+// within the span of time ending the given lag ago. For example, a lag of one
+// day and a span of one hour selects the same hour yesterday for use as a
+// seasonal baseline. The window must have enough buckets to retain data for
+// the lag plus the span.
+func Lagged(p *TimePolicy, lag time.Duration, span time.Duration) Reducer {
+	return &laggedPolicy{
+		policy: p,
+		lag:    lag,
+		span:   span,
+		window: make(Window, 0, p.BucketCount()),
+	}
+}
+
+func (l *laggedPolicy) Reduce(f func(Window) float64) float64 {
+	l.policy.lock.Lock()
+	defer l.policy.lock.Unlock()
+
+	var now = l.policy.clock.Now()
+	var end = now.Add(-l.lag)
+	var start = end.Add(-l.span)
+	l.window = l.window[:0]
+	l.policy.eachBucket(now, func(bucketStart time.Time, window Window) {
+		if !bucketStart.Before(start) && bucketStart.Before(end) {
+			l.window = append(l.window, window[0])
+		}
+	})
+	return f(l.window)
+}
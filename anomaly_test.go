@@ -0,0 +1,84 @@
+package rolling
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAnomalyDetector(t *testing.T) {
+	var current = NewPointPolicy(NewWindow(2))
+	var baseline = NewPointPolicy(NewWindow(10))
+	for x := 0; x < 10; x = x + 1 {
+		baseline.Append(10)
+	}
+	var d = NewAnomalyDetector(Avg, current, baseline)
+	var entered, exited []float64
+	d.Threshold(1, func(score float64) {
+		entered = append(entered, score)
+	}, func(score float64) {
+		exited = append(exited, score)
+	})
+	var low = 0
+	d.Threshold(-0.5, func(score float64) {
+		low = low + 1
+	}, nil)
+
+	current.Append(10)
+	current.Append(10)
+	if score, ok := d.Check(); !ok || score != 0 {
+		t.Fatalf("expected a score of zero but got %f, %t", score, ok)
+	}
+	current.Append(30)
+	current.Append(30)
+	if score, ok := d.Check(); !ok || !floatEquals(score, 2) {
+		t.Fatalf("expected a score of two but got %f, %t", score, ok)
+	}
+	d.Check()
+	if len(entered) != 1 || len(exited) != 0 {
+		t.Fatalf("expected the threshold to be entered once but got %v, %v", entered, exited)
+	}
+	current.Append(4)
+	current.Append(4)
+	if score, _ := d.Check(); !floatEquals(score, -0.6) {
+		t.Fatalf("expected a score of -0.6 but got %f", score)
+	}
+	if len(exited) != 1 || low != 1 {
+		t.Fatalf("expected the high threshold to exit and the low to enter but got %v, %d", exited, low)
+	}
+	if result := d.Reduce(Max); !floatEquals(result, -0.6) {
+		t.Fatalf("expected the score to be reduced but got %f", result)
+	}
+}
+
+func TestAnomalyDetectorNoBaseline(t *testing.T) {
+	var current = NewPointPolicy(NewWindow(2))
+	current.Append(1)
+	var d = NewAnomalyDetector(Sum, current, NewPointPolicy(NewWindow(2)))
+	var called = false
+	d.Threshold(0.5, func(float64) { called = true }, nil)
+	if _, ok := d.Check(); ok || called {
+		t.Fatal("expected no score when the baseline is empty")
+	}
+	if result := d.Reduce(Count); result != 0 {
+		t.Fatalf("expected an empty window without a score but got %f", result)
+	}
+}
+
+func TestLagged(t *testing.T) {
+	var c = &testClock{now: time.Unix(0, 0)}
+	var p = NewTimePolicy(NewWindow(26), time.Hour, WithClock(c))
+	for x := 0; x < 26; x = x + 1 {
+		p.Append(float64(x))
+		c.now = c.now.Add(time.Hour)
+	}
+	c.now = c.now.Add(-time.Hour + time.Minute)
+	// Hour 25 is current so hour 1 began a day before it.
+	var yesterday = Lagged(p, 24*time.Hour, time.Hour)
+	if result := yesterday.Reduce(Sum); result != 1 {
+		t.Fatalf("expected the same hour yesterday but got %f", result)
+	}
+	var d = NewAnomalyDetector(Sum, NewPointPolicy(NewWindow(1)), yesterday, Lagged(p, 23*time.Hour, time.Hour))
+	if _, ok := d.Score(); !ok {
+		t.Fatal("expected a score from the seasonal baselines")
+	}
+}
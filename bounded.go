@@ -0,0 +1,68 @@
+package rolling
+
+import (
+	"sync"
+	"time"
+)
+
+// BoundedPolicy is a rolling window policy that tracks the last N values
+// inserted so long as they are no older than a maximum age. The window is
+// limited by whichever of the two bounds is tighter at any given moment.
+type BoundedPolicy struct {
+	windowSize int
+	window     Window
+	times      []time.Time
+	offset     int
+	maxAge     time.Duration
+	clock      Clock
+	lock       *sync.Mutex
+}
+
+// NewBoundedPolicy generates a Policy that operates on, at most, a number of
+// points equal to the size of the given window and that discards any point
+// older than the given maximum age. Each bucket will contain, at most, one
+// data point. Buckets that have not yet received data, or whose data have
+// expired, are empty rather than zero.
+func NewBoundedPolicy(window Window, maxAge time.Duration, options ...TimePolicyOption) *BoundedPolicy {
+	var o = newTimeOptions(options)
+	for offset := range window {
+		window[offset] = make([]float64, 0, 1)
+	}
+	return &BoundedPolicy{
+		windowSize: len(window),
+		window:     window,
+		times:      make([]time.Time, len(window)),
+		maxAge:     maxAge,
+		clock:      o.clock,
+		lock:       &sync.Mutex{},
+	}
+}
+
+// Append a value to the window.
+func (w *BoundedPolicy) Append(value float64) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	w.window[w.offset] = append(w.window[w.offset][:0], value)
+	w.times[w.offset] = w.clock.Now()
+	w.offset = (w.offset + 1) % w.windowSize
+}
+
+func (w *BoundedPolicy) expire(now time.Time) {
+	var cutoff = now.Add(-w.maxAge)
+	for offset, bucket := range w.window {
+		if len(bucket) > 0 && w.times[offset].Before(cutoff) {
+			w.window[offset] = bucket[:0]
+		}
+	}
+}
+
+// Reduce the window to a single value using a reduction function. Values
+// older than the maximum age are removed before the reduction.
+func (w *BoundedPolicy) Reduce(f func(Window) float64) float64 {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	w.expire(w.clock.Now())
+	return f(w.window)
+}
@@ -0,0 +1,81 @@
+package rolling
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestBoundedWindowCountLimit(t *testing.T) {
+	var c = &testClock{now: time.Unix(0, 0)}
+	var p = NewBoundedPolicy(NewWindow(3), time.Minute, WithClock(c))
+	if result := p.Reduce(Count); !floatEquals(result, 0) {
+		t.Fatalf("new window should be empty but has %f values", result)
+	}
+	for x := 1; x <= 5; x = x + 1 {
+		p.Append(float64(x))
+	}
+	if result := p.Reduce(Sum); !floatEquals(result, 12) {
+		t.Fatalf("sum calculated incorrectly: %f versus %f", 12.0, result)
+	}
+}
+
+func TestBoundedWindowAgeLimit(t *testing.T) {
+	var c = &testClock{now: time.Unix(0, 0)}
+	var p = NewBoundedPolicy(NewWindow(10), 10*time.Second, WithClock(c))
+	p.Append(1)
+	c.now = c.now.Add(5 * time.Second)
+	p.Append(2)
+	c.now = c.now.Add(5 * time.Second)
+	if result := p.Reduce(Sum); !floatEquals(result, 3) {
+		t.Fatalf("sum calculated incorrectly: %f versus %f", 3.0, result)
+	}
+	c.now = c.now.Add(time.Second)
+	if result := p.Reduce(Sum); !floatEquals(result, 2) {
+		t.Fatalf("sum calculated incorrectly: %f versus %f", 2.0, result)
+	}
+	c.now = c.now.Add(time.Minute)
+	if result := p.Reduce(Count); !floatEquals(result, 0) {
+		t.Fatalf("expired window should be empty but has %f values", result)
+	}
+	p.Append(3)
+	if result := p.Reduce(Sum); !floatEquals(result, 3) {
+		t.Fatalf("sum calculated incorrectly: %f versus %f", 3.0, result)
+	}
+}
+
+func TestBoundedWindowDataRace(t *testing.T) {
+	var p = NewBoundedPolicy(NewWindow(100), time.Millisecond*10)
+	var stop = make(chan bool)
+	go func() {
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+				p.Append(1)
+				time.Sleep(time.Millisecond)
+			}
+		}
+	}()
+	go func() {
+		var v float64
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+				_ = p.Reduce(func(w Window) float64 {
+					for _, bucket := range w {
+						for _, p := range bucket {
+							v = math.Mod(v+p, 100)
+						}
+					}
+					return 0
+				})
+			}
+		}
+	}()
+	time.Sleep(100 * time.Millisecond)
+	close(stop)
+}
@@ -0,0 +1,107 @@
+package rolling
+
+// Builder composes a Metric from a window and a chain of steps that read from
+// top to bottom in the order they are applied:
+//
+//	var m = rolling.From(p).Sum().Percentage(0, 100).Limited(10).Named("x").Build()
+//
+// Steps that choose an aggregate, such as Sum or Percentile, replace any
+// aggregate chosen before them. Steps that transform the aggregate, such as
+// Percentage or Limited, wrap whatever came before them. A Builder is not safe
+// for concurrent use but the Metric it builds is as safe as its window.
+type Builder struct {
+	name      string
+	policy    Policy
+	aggregate func(Window) float64
+}
+
+// From begins a Builder for the given window. The aggregate is Sum unless
+// another is chosen.
+func From(p Policy) *Builder {
+	return &Builder{policy: p, aggregate: Sum}
+}
+
+// Aggregate chooses the given aggregating function.
+func (b *Builder) Aggregate(f func(Window) float64) *Builder {
+	b.aggregate = f
+	return b
+}
+
+// Count chooses the Count aggregate.
+func (b *Builder) Count() *Builder {
+	return b.Aggregate(Count)
+}
+
+// Sum chooses the Sum aggregate.
+func (b *Builder) Sum() *Builder {
+	return b.Aggregate(Sum)
+}
+
+// Avg chooses the Avg aggregate.
+func (b *Builder) Avg() *Builder {
+	return b.Aggregate(Avg)
+}
+
+// Min chooses the Min aggregate.
+func (b *Builder) Min() *Builder {
+	return b.Aggregate(Min)
+}
+
+// Max chooses the Max aggregate.
+func (b *Builder) Max() *Builder {
+	return b.Aggregate(Max)
+}
+
+// Percentile chooses the given Percentile aggregate.
+func (b *Builder) Percentile(perc float64) *Builder {
+	return b.Aggregate(Percentile(perc))
+}
+
+// Percentage scales the aggregate to the fraction of the given range that it
+// covers. See Percentage for the available options.
+func (b *Builder) Percentage(lower float64, upper float64, options ...PercentageOption) *Builder {
+	b.aggregate = Percentage(b.aggregate, lower, upper, options...)
+	return b
+}
+
+// Limited causes the aggregate to be zero until at least the given number of
+// values have been appended to the window so that decisions are not made on
+// too little data. The points of a PointPolicy that have yet to receive a
+// value are not counted.
+func (b *Builder) Limited(minimum int) *Builder {
+	var f = b.aggregate
+	var limit = float64(minimum)
+	var count = b.appended()
+	b.aggregate = func(w Window) float64 {
+		if count(w) < limit {
+			return 0
+		}
+		return f(w)
+	}
+	return b
+}
+
+// appended returns a function that counts the values appended to the window
+// of the Builder. The function is called by the aggregate while the window is
+// locked for a reduction so it must not lock the window itself.
+func (b *Builder) appended() func(Window) float64 {
+	if p, ok := b.policy.(*PointPolicy); ok {
+		return func(Window) float64 {
+			return float64(p.count)
+		}
+	}
+	return Count
+}
+
+// Named sets the name of the Metric.
+func (b *Builder) Named(name string) *Builder {
+	b.name = name
+	return b
+}
+
+// Build returns a Metric of the window and the composed aggregate. The
+// Builder may continue to be used and later steps do not affect Metrics that
+// were already built.
+func (b *Builder) Build() *Metric {
+	return &Metric{Name: b.name, Policy: b.policy, Aggregate: b.aggregate}
+}
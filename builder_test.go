@@ -0,0 +1,50 @@
+package rolling
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBuilder(t *testing.T) {
+	var p = NewTimePolicy(NewWindow(10), time.Second, WithClock(&testClock{now: time.Unix(0, 0)}))
+	for x := 0; x < 5; x = x + 1 {
+		p.Append(10)
+	}
+	var m = From(p).Sum().Percentage(0, 100).Limited(10).Named("utilization").Build()
+	if m.Name != "utilization" {
+		t.Fatalf("unexpected name %q", m.Name)
+	}
+	if result := m.Value(); result != 0 {
+		t.Fatalf("expected zero below the minimum count but got %f", result)
+	}
+	for x := 0; x < 5; x = x + 1 {
+		p.Append(10)
+	}
+	if result := m.Value(); !floatEquals(result, 1) {
+		t.Fatalf("expected the sum as a percentage of 100 but got %f", result)
+	}
+
+	var b = From(p).Percentage(0, 200)
+	var first = b.Build()
+	var second = b.Max().Build()
+	if result := first.Value(); !floatEquals(result, 0.5) {
+		t.Fatalf("expected the default sum as a percentage of 200 but got %f", result)
+	}
+	if result := second.Value(); result != 10 {
+		t.Fatalf("expected a later aggregate to replace the chain but got %f", result)
+	}
+}
+
+func TestBuilderLimitedPointPolicy(t *testing.T) {
+	var p = NewPointPolicy(NewWindow(10))
+	var m = From(p).Sum().Limited(3).Build()
+	p.Append(1)
+	p.Append(1)
+	if result := m.Value(); result != 0 {
+		t.Fatalf("expected zero until three points are appended but got %f", result)
+	}
+	p.Append(1)
+	if result := m.Value(); !floatEquals(result, 3) {
+		t.Fatalf("expected the sum once three points are appended but got %f", result)
+	}
+}
@@ -0,0 +1,138 @@
+package rolling
+
+import "time"
+
+// burnRateBuckets is the number of buckets in each window of a
+// BurnRateEvaluator. Each bucket covers 1/60th of its window.
+const burnRateBuckets = 60
+
+// BurnRateAlert is a single tier of a multiwindow, multi-burn-rate alert on
+// an error budget. The alert fires when the error budget is being consumed at
+// least Burn times faster than is sustainable over both the Long window and
+// the Short window. The short window allows the alert to stop firing soon
+// after the problem is resolved.
+type BurnRateAlert struct {
+	Name  string
+	Long  time.Duration
+	Short time.Duration
+	Burn  float64
+}
+
+// DefaultBurnRateAlerts returns the two paging tiers commonly recommended for
+// a 30 day objective. The first fires when 2% of the budget is consumed within
+// an hour and the second when 5% is consumed within six hours.
+func DefaultBurnRateAlerts() []BurnRateAlert {
+	return []BurnRateAlert{
+		{Name: "fast", Long: time.Hour, Short: 5 * time.Minute, Burn: 14.4},
+		{Name: "slow", Long: 6 * time.Hour, Short: 30 * time.Minute, Burn: 6},
+	}
+}
+
+// BurnRateStatus is the state of a single BurnRateAlert.
+type BurnRateStatus struct {
+	Alert  BurnRateAlert
+	Long   float64
+	Short  float64
+	Firing bool
+}
+
+// BurnRateEvaluator records the outcomes of requests against an availability
+// objective and evaluates a set of burn rate alerts. A single OutcomeTracker
+// is kept for each distinct window duration used by the alerts so that alerts
+// which share a window also share its data.
+type BurnRateEvaluator struct {
+	objective float64
+	alerts    []BurnRateAlert
+	windows   map[time.Duration]*OutcomeTracker
+}
+
+// NewBurnRateEvaluator generates a BurnRateEvaluator for the given objective,
+// such as 0.999 for 99.9% of requests succeeding, and the given alerts. If no
+// alerts are given then DefaultBurnRateAlerts are used.
+func NewBurnRateEvaluator(objective float64, alerts []BurnRateAlert, options ...TimePolicyOption) *BurnRateEvaluator {
+	if len(alerts) == 0 {
+		alerts = DefaultBurnRateAlerts()
+	}
+	var windows = make(map[time.Duration]*OutcomeTracker)
+	for _, alert := range alerts {
+		for _, d := range []time.Duration{alert.Long, alert.Short} {
+			if _, ok := windows[d]; !ok {
+				windows[d] = NewOutcomeTracker(burnRateBuckets, d/burnRateBuckets, 1, options...)
+			}
+		}
+	}
+	return &BurnRateEvaluator{
+		objective: objective,
+		alerts:    alerts,
+		windows:   windows,
+	}
+}
+
+// RecordSuccess records a successful request in every window.
+func (e *BurnRateEvaluator) RecordSuccess() {
+	for _, w := range e.windows {
+		w.RecordSuccess()
+	}
+}
+
+// RecordFailure records a failed request in every window.
+func (e *BurnRateEvaluator) RecordFailure() {
+	for _, w := range e.windows {
+		w.RecordFailure()
+	}
+}
+
+// Record records a successful request if the given error is nil and a failed
+// request otherwise.
+func (e *BurnRateEvaluator) Record(err error) {
+	if err != nil {
+		e.RecordFailure()
+		return
+	}
+	e.RecordSuccess()
+}
+
+// BurnRate returns the rate at which the error budget was consumed over the
+// given window duration. A burn rate of one consumes exactly the budget over
+// the period of the objective. The duration must be one of those used by the
+// alerts or else zero is returned.
+func (e *BurnRateEvaluator) BurnRate(window time.Duration) float64 {
+	var w, ok = e.windows[window]
+	if !ok {
+		return 0
+	}
+	var rate, _ = w.FailureRate()
+	var budget = 1 - e.objective
+	if budget <= 0 {
+		return 0
+	}
+	return rate / budget
+}
+
+// Evaluate returns the status of every alert in the order they were given.
+func (e *BurnRateEvaluator) Evaluate() []BurnRateStatus {
+	var result = make([]BurnRateStatus, 0, len(e.alerts))
+	for _, alert := range e.alerts {
+		var long = e.BurnRate(alert.Long)
+		var short = e.BurnRate(alert.Short)
+		result = append(result, BurnRateStatus{
+			Alert:  alert,
+			Long:   long,
+			Short:  short,
+			Firing: long >= alert.Burn && short >= alert.Burn,
+		})
+	}
+	return result
+}
+
+// Firing returns the names of the alerts that are currently firing in the
+// order they were given.
+func (e *BurnRateEvaluator) Firing() []string {
+	var result = make([]string, 0)
+	for _, status := range e.Evaluate() {
+		if status.Firing {
+			result = append(result, status.Alert.Name)
+		}
+	}
+	return result
+}
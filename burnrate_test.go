@@ -0,0 +1,55 @@
+package rolling
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBurnRateEvaluator(t *testing.T) {
+	var c = &testClock{now: time.Unix(0, 0)}
+	var e = NewBurnRateEvaluator(0.99, nil, WithClock(c))
+	if firing := e.Firing(); len(firing) != 0 {
+		t.Fatalf("expected no alerts without requests but got %v", firing)
+	}
+
+	// Spread a 10% error rate over the long windows.
+	for x := 0; x < 60; x = x + 1 {
+		for y := 0; y < 9; y = y + 1 {
+			e.RecordSuccess()
+		}
+		e.RecordFailure()
+		c.now = c.now.Add(6 * time.Minute)
+	}
+	if result := e.BurnRate(6 * time.Hour); !floatMostlyEquals(result, 10) {
+		t.Fatalf("expected a burn rate of 10 but got %f", result)
+	}
+	var firing = e.Firing()
+	if len(firing) != 1 || firing[0] != "slow" {
+		t.Fatalf("expected only the slow alert to fire but got %v", firing)
+	}
+
+	// A burst of failures fires the fast alert as well.
+	for x := 0; x < 100; x = x + 1 {
+		e.RecordFailure()
+	}
+	firing = e.Firing()
+	if len(firing) != 2 || firing[0] != "fast" || firing[1] != "slow" {
+		t.Fatalf("expected both alerts to fire but got %v", firing)
+	}
+	var status = e.Evaluate()
+	if status[0].Short <= status[0].Long {
+		t.Fatalf("expected the short window to burn faster than the long %v", status[0])
+	}
+
+	// Recovery clears the short windows and stops the alerts.
+	c.now = c.now.Add(time.Hour)
+	for x := 0; x < 1000; x = x + 1 {
+		e.Record(nil)
+	}
+	if firing = e.Firing(); len(firing) != 0 {
+		t.Fatalf("expected the alerts to stop after recovery but got %v", firing)
+	}
+	if result := e.BurnRate(time.Minute); result != 0 {
+		t.Fatalf("expected an unknown window to have no burn rate but got %f", result)
+	}
+}
@@ -0,0 +1,108 @@
+package rolling
+
+import (
+	"sync"
+	"time"
+)
+
+// CalendarPeriod is a unit of civil time used to bucket a CalendarPolicy.
+type CalendarPeriod int
+
+const (
+	// CalendarMinute buckets data by the minute.
+	CalendarMinute CalendarPeriod = iota
+	// CalendarHour buckets data by the hour of the day. The hour that is
+	// repeated when daylight saving time ends is recorded as a single hour and
+	// the hour that is skipped when it begins is always empty.
+	CalendarHour
+	// CalendarDay buckets data by the date. Days on which daylight saving time
+	// begins or ends are shorter or longer than 24 hours.
+	CalendarDay
+)
+
+const hoursPerDay = 24
+
+// index returns the number of whole periods that separate the given time
+// from the Unix epoch as measured by the civil calendar of the location.
+func (p CalendarPeriod) index(t time.Time, location *time.Location) int64 {
+	if p == CalendarMinute {
+		// Minute boundaries are the same in every location so the absolute
+		// time is used. This keeps the index increasing when an hour is
+		// repeated at the end of daylight saving time.
+		return floorDiv(t.Unix(), int64(time.Minute/time.Second))
+	}
+	var local = t.In(location)
+	var year, month, day = local.Date()
+	var days = time.Date(year, month, day, 0, 0, 0, 0, time.UTC).Unix() / int64(24*time.Hour/time.Second)
+	if p == CalendarHour {
+		return days*hoursPerDay + int64(local.Hour())
+	}
+	return days
+}
+
+// CalendarPolicy is a window implementation where each bucket contains the
+// data for one period of the civil calendar, such as a day, in a particular
+// location. Unlike the TimePolicy, bucket boundaries follow local time and
+// account for daylight saving time.
+type CalendarPolicy struct {
+	period      CalendarPeriod
+	location    *time.Location
+	window      Window
+	ring        ring
+	bucketHint  int
+	bucketLimit int
+	clock       Clock
+	lock        *sync.Mutex
+}
+
+// NewCalendarPolicy manages a window where each bucket is a single calendar
+// period in the given location. A window with a single bucket contains only
+// the current period, such as today, and is emptied when the period ends. A
+// window with more buckets also retains that many periods of history.
+func NewCalendarPolicy(window Window, period CalendarPeriod, location *time.Location, options ...TimePolicyOption) *CalendarPolicy {
+	var o = newTimeOptions(options)
+	var hint = bucketHint(window)
+	return &CalendarPolicy{
+		period:      period,
+		location:    location,
+		window:      window,
+		ring:        newRing(len(window)),
+		bucketHint:  hint,
+		bucketLimit: o.limit(hint),
+		clock:       o.clock,
+		lock:        &sync.Mutex{},
+	}
+}
+
+func (w *CalendarPolicy) clear(offset int) {
+	w.window[offset] = resetBucket(w.window[offset], w.bucketHint, w.bucketLimit)
+}
+
+// AppendWithTimestamp same as Append but with timestamp as parameter. Values
+// for periods that are older than the window are discarded.
+func (w *CalendarPolicy) AppendWithTimestamp(value float64, timestamp time.Time) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	var index = w.period.index(timestamp, w.location)
+	w.ring.advance(index, w.clear)
+	if !w.ring.contains(index) {
+		return
+	}
+	var offset = w.ring.offset(index)
+	w.window[offset] = append(w.window[offset], value)
+}
+
+// Append a value to the bucket of the current calendar period.
+func (w *CalendarPolicy) Append(value float64) {
+	w.AppendWithTimestamp(value, w.clock.Now())
+}
+
+// Reduce the window to a single value using a reduction function.
+func (w *CalendarPolicy) Reduce(f func(Window) float64) float64 {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	w.ring.advance(w.period.index(w.clock.Now(), w.location), w.clear)
+	return f(w.window)
+}
@@ -0,0 +1,95 @@
+package rolling
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCalendarWindowDay(t *testing.T) {
+	var location = time.FixedZone("UTC-8", -8*60*60)
+	var c = &testClock{now: time.Date(2020, 3, 1, 23, 0, 0, 0, location)}
+	var p = NewCalendarPolicy(NewWindow(1), CalendarDay, location, WithClock(c))
+	p.Append(1)
+	c.now = c.now.Add(50 * time.Minute)
+	p.Append(1)
+	if result := p.Reduce(Sum); !floatEquals(result, 2) {
+		t.Fatalf("sum calculated incorrectly: %f versus %f", 2.0, result)
+	}
+	// Midnight in the location but not in UTC.
+	c.now = c.now.Add(10 * time.Minute)
+	if result := p.Reduce(Sum); !floatEquals(result, 0) {
+		t.Fatalf("window should be empty at midnight but got %f", result)
+	}
+	p.Append(3)
+	if result := p.Reduce(Sum); !floatEquals(result, 3) {
+		t.Fatalf("sum calculated incorrectly: %f versus %f", 3.0, result)
+	}
+}
+
+func TestCalendarWindowDaylightSaving(t *testing.T) {
+	var location, err = time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("time zone data unavailable")
+	}
+	// Daylight saving time ended at 2:00 on 2020-11-01, making the day 25
+	// hours long.
+	var start = time.Date(2020, 11, 1, 0, 0, 0, 0, location)
+	var c = &testClock{now: start}
+	var days = NewCalendarPolicy(NewWindow(2), CalendarDay, location, WithClock(c))
+	var hours = NewCalendarPolicy(NewWindow(24), CalendarHour, location, WithClock(c))
+	for x := 0; x < 25; x = x + 1 {
+		c.now = start.Add(time.Duration(x) * time.Hour)
+		days.Append(1)
+		hours.Append(1)
+	}
+	if result := days.Reduce(Max); !floatEquals(result, 1) {
+		t.Fatalf("max calculated incorrectly: %f versus %f", 1.0, result)
+	}
+	if result := days.Reduce(Count); !floatEquals(result, 25) {
+		t.Fatalf("day should contain 25 hours but got %f", result)
+	}
+	if result := hours.Reduce(Count); !floatEquals(result, 25) {
+		t.Fatalf("hours should contain 25 values but got %f", result)
+	}
+	var repeated = 0
+	hours.Reduce(func(w Window) float64 {
+		for _, bucket := range w {
+			if len(bucket) == 2 {
+				repeated = repeated + 1
+			}
+		}
+		return 0
+	})
+	if repeated != 1 {
+		t.Fatalf("expected one repeated hour but got %d", repeated)
+	}
+	c.now = start.Add(25 * time.Hour)
+	if result := days.Reduce(Count); !floatEquals(result, 25) {
+		t.Fatalf("previous day should remain in the window but got %f", result)
+	}
+	c.now = start.Add(49 * time.Hour)
+	if result := days.Reduce(Count); !floatEquals(result, 0) {
+		t.Fatalf("window should be empty but got %f", result)
+	}
+}
+
+func TestCalendarWindowDiscardsOldValues(t *testing.T) {
+	var c = &testClock{now: time.Date(2020, 1, 10, 12, 0, 0, 0, time.UTC)}
+	var p = NewCalendarPolicy(NewWindow(2), CalendarDay, time.UTC, WithClock(c))
+	p.Append(1)
+	p.AppendWithTimestamp(2, c.now.Add(-24*time.Hour))
+	p.AppendWithTimestamp(4, c.now.Add(-48*time.Hour))
+	if result := p.Reduce(Sum); !floatEquals(result, 3) {
+		t.Fatalf("sum calculated incorrectly: %f versus %f", 3.0, result)
+	}
+}
+
+func TestCalendarPeriodMinute(t *testing.T) {
+	var location = time.FixedZone("UTC+0545", (5*60+45)*60)
+	var base = time.Date(2020, 1, 1, 10, 30, 59, 0, location)
+	var a = CalendarMinute.index(base, location)
+	var b = CalendarMinute.index(base.Add(time.Second), location)
+	if b != a+1 {
+		t.Fatalf("expected consecutive minutes but got %d and %d", a, b)
+	}
+}
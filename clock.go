@@ -0,0 +1,43 @@
+package rolling
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock is a source of the current time. Windows that depend on time use a
+// Clock so that the passage of time may be controlled in tests.
+type Clock interface {
+	Now() time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// every calls the given function each time the interval elapses, on a new
+// goroutine, until the returned function is called. The returned function
+// may be called more than once.
+func every(interval time.Duration, f func()) func() {
+	var ticker = time.NewTicker(interval)
+	var done = make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				f()
+			case <-done:
+				return
+			}
+		}
+	}()
+	var once = &sync.Once{}
+	return func() {
+		once.Do(func() {
+			ticker.Stop()
+			close(done)
+		})
+	}
+}
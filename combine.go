@@ -0,0 +1,115 @@
+package rolling
+
+import (
+	"reflect"
+	"sort"
+)
+
+type combined struct {
+	reducers []Reducer
+}
+
+// Combine returns a Reducer that presents the windows of all the given
+// policies as a single Window without copying their data. The reduction
+// function is given the buckets of every window, in the order the policies
+// were given, while the lock of every window is held. Combinations may be
+// nested or given to anything that accepts a Reducer. Each underlying window
+// must only be given once, directly or through a combination, and not also
+// through another Reducer that wraps it, because its lock is taken only once
+// per reduction.
+func Combine(reducers ...Reducer) Reducer {
+	return &combined{reducers: reducers}
+}
+
+// Reduce all of the combined windows to a single value using a reduction
+// function.
+func (c *combined) Reduce(f func(Window) float64) float64 {
+	return reduceTogether(c.reducers, func(windows []Window) float64 {
+		var size = 0
+		for _, w := range windows {
+			size = size + len(w)
+		}
+		var all = make(Window, 0, size)
+		for _, w := range windows {
+			all = append(all, w...)
+		}
+		return f(all)
+	})
+}
+
+// reduceTogether calls the given function with the window of each given
+// Reducer, in the order given, while the locks of all of them are held.
+// Combinations are flattened so that only the underlying windows are locked
+// and a window that appears more than once is locked once. The windows are
+// locked in order of their address so that concurrent reductions over the
+// same windows, given in any order, do not deadlock.
+func reduceTogether(reducers []Reducer, f func(windows []Window) float64) float64 {
+	var leaves []Reducer
+	var index = make(map[policyIdentity]int)
+	var parts = make([][]int, len(reducers))
+	var flatten func(part int, r Reducer)
+	flatten = func(part int, r Reducer) {
+		if c, ok := r.(*combined); ok {
+			for _, inner := range c.reducers {
+				flatten(part, inner)
+			}
+			return
+		}
+		var value = reflect.ValueOf(r)
+		if value.Kind() == reflect.Ptr {
+			var id = policyIdentity{kind: value.Type(), address: value.Pointer()}
+			if leaf, ok := index[id]; ok {
+				parts[part] = append(parts[part], leaf)
+				return
+			}
+			index[id] = len(leaves)
+		}
+		parts[part] = append(parts[part], len(leaves))
+		leaves = append(leaves, r)
+	}
+	for part, r := range reducers {
+		flatten(part, r)
+	}
+
+	var order = make([]int, len(leaves))
+	for offset := range order {
+		order[offset] = offset
+	}
+	sort.SliceStable(order, func(i int, j int) bool {
+		return address(leaves[order[i]]) < address(leaves[order[j]])
+	})
+
+	var leafWindows = make([]Window, len(leaves))
+	var result float64
+	var lock func(position int)
+	lock = func(position int) {
+		if position >= len(order) {
+			var windows = make([]Window, len(parts))
+			for part, members := range parts {
+				for _, leaf := range members {
+					windows[part] = append(windows[part], leafWindows[leaf]...)
+				}
+			}
+			result = f(windows)
+			return
+		}
+		var leaf = order[position]
+		leaves[leaf].Reduce(func(w Window) float64 {
+			leafWindows[leaf] = w
+			lock(position + 1)
+			return 0
+		})
+	}
+	lock(0)
+	return result
+}
+
+// address returns the address of a Reducer that is a pointer or zero for
+// any other Reducer.
+func address(r Reducer) uintptr {
+	var value = reflect.ValueOf(r)
+	if value.Kind() != reflect.Ptr {
+		return 0
+	}
+	return value.Pointer()
+}
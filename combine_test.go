@@ -0,0 +1,104 @@
+package rolling
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCombine(t *testing.T) {
+	var a = NewPointPolicy(NewWindow(2))
+	var b = NewTimePolicy(NewWindow(10), time.Second)
+	a.Append(1)
+	a.Append(2)
+	b.Append(3)
+	var c = Combine(a, b)
+	if result := c.Reduce(Sum); !floatEquals(result, 6) {
+		t.Fatalf("sum calculated incorrectly: %f versus %f", 6.0, result)
+	}
+	if result := c.Reduce(Count); !floatEquals(result, 3) {
+		t.Fatalf("count calculated incorrectly: %f versus %f", 3.0, result)
+	}
+	if result := Combine().Reduce(Count); !floatEquals(result, 0) {
+		t.Fatalf("empty combination should have no values but has %f", result)
+	}
+}
+
+func TestCombineDataRace(t *testing.T) {
+	var a = NewPointPolicy(NewWindow(10))
+	var b = NewPointPolicy(NewWindow(10))
+	var ab = Combine(a, b)
+	var ba = Combine(b, a)
+	var wg = &sync.WaitGroup{}
+	for x := 0; x < 4; x = x + 1 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for y := 0; y < 100; y = y + 1 {
+				a.Append(1)
+				b.Append(1)
+				_ = ab.Reduce(Sum)
+				_ = ba.Reduce(Sum)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// withinTimeout fails the test if the given function does not return
+// promptly, such as when it deadlocks.
+func withinTimeout(t *testing.T, f func()) {
+	var done = make(chan struct{})
+	go func() {
+		defer close(done)
+		f()
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out, likely deadlocked")
+	}
+}
+
+func TestCombineNested(t *testing.T) {
+	var a = NewPointPolicy(NewWindow(2))
+	var b = NewPointPolicy(NewWindow(2))
+	var c = NewPointPolicy(NewWindow(2))
+	a.Append(1)
+	b.Append(2)
+	c.Append(3)
+	withinTimeout(t, func() {
+		if result := Combine(Combine(a, b), c).Reduce(Sum); !floatEquals(result, 6) {
+			t.Errorf("nested sum calculated incorrectly: %f versus %f", 6.0, result)
+		}
+	})
+	withinTimeout(t, func() {
+		var rate, ok = NewErrorRate(Combine(a, b), Combine(a, b, c), 0).Rate()
+		if !ok || !floatEquals(rate, 0.5) {
+			t.Errorf("expected a rate of 0.5 from nested combinations but got %f %v", rate, ok)
+		}
+	})
+}
+
+func TestCombineDoesNotCopy(t *testing.T) {
+	var a = NewPointPolicy(NewWindow(2))
+	var b = NewTimePolicy(NewWindow(2), time.Second)
+	a.Append(1)
+	b.Append(2)
+	Combine(a, b).Reduce(func(w Window) float64 {
+		if &w[0][0] != &a.values[0] {
+			t.Error("expected the buckets of the point window rather than a copy")
+		}
+		return 0
+	})
+}
+
+func TestCombineSameWindowTwice(t *testing.T) {
+	var a = NewTimePolicy(NewWindow(2), time.Second)
+	a.Append(1)
+	withinTimeout(t, func() {
+		if result := Combine(a, Combine(a)).Reduce(Sum); !floatEquals(result, 2) {
+			t.Errorf("expected the window to be presented twice but got %f", result)
+		}
+	})
+}
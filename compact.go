@@ -0,0 +1,200 @@
+package rolling
+
+import (
+	"sync"
+	"time"
+)
+
+// compactBucket is a single bucket of a SummaryWindow. The open bucket keeps
+// its raw values and every other bucket keeps only their summary.
+type compactBucket struct {
+	values []float64
+	count  int
+	sum    float64
+	min    float64
+	max    float64
+	sketch *Sketch
+}
+
+func (b *compactBucket) add(value float64, accuracy float64) {
+	if b.count < 1 || value < b.min {
+		b.min = value
+	}
+	if b.count < 1 || value > b.max {
+		b.max = value
+	}
+	b.count = b.count + 1
+	b.sum = b.sum + value
+	if accuracy > 0 {
+		if b.sketch == nil {
+			b.sketch = newSketch(accuracy)
+		}
+		b.sketch.Add(value)
+	}
+}
+
+// merge adds the summary of another bucket, but not its sketch or raw values,
+// to this one.
+func (b *compactBucket) merge(other *compactBucket) {
+	if other.count < 1 {
+		return
+	}
+	if b.count < 1 || other.min < b.min {
+		b.min = other.min
+	}
+	if b.count < 1 || other.max > b.max {
+		b.max = other.max
+	}
+	b.count = b.count + other.count
+	b.sum = b.sum + other.sum
+}
+
+// SummaryWindow is a rolling time window, like TimePolicy, that compacts each
+// bucket into summary statistics once the bucket closes and can no longer be
+// the current bucket. Only the values of the current bucket are kept in full
+// while every older bucket is reduced to a count, sum, minimum, maximum, and,
+// optionally, a Sketch of its distribution. Memory is then bounded by the
+// number of buckets and the traffic of a single bucket rather than by the
+// traffic of the whole window, which allows accurate aggregates over long
+// windows. Because raw values are discarded the window cannot be reduced
+// with arbitrary functions and is instead read with Summarize.
+type SummaryWindow struct {
+	buckets  []compactBucket
+	accuracy float64
+	open     int64
+	ring     ring
+	clock    Clock
+	lock     *sync.Mutex
+}
+
+// NewSummaryWindow generates a SummaryWindow with the given number of
+// buckets, each covering the given duration. Each closed bucket keeps a
+// Sketch with the given relative accuracy, such as 0.01, from which
+// percentiles are computed. An accuracy that is not between zero and one
+// keeps no sketch and percentiles are then reported as zero. Only the
+// WithClock and WithAlignment options apply to a SummaryWindow.
+func NewSummaryWindow(buckets int, bucketDuration time.Duration, accuracy float64, options ...TimePolicyOption) *SummaryWindow {
+	var o = newTimeOptions(options)
+	if !(accuracy > 0 && accuracy < 1) {
+		accuracy = 0
+	}
+	return &SummaryWindow{
+		buckets:  make([]compactBucket, buckets),
+		accuracy: accuracy,
+		ring:     newTimeRing(buckets, bucketDuration, o.originNano),
+		clock:    o.clock,
+		lock:     &sync.Mutex{},
+	}
+}
+
+func (w *SummaryWindow) clear(offset int) {
+	var values = resetBucket(w.buckets[offset].values, 0, minimumBucketLimit)
+	w.buckets[offset] = compactBucket{values: values}
+}
+
+// compact moves the raw values of the open bucket into its summary.
+func (w *SummaryWindow) compact(offset int) {
+	var bucket = &w.buckets[offset]
+	for _, value := range bucket.values {
+		bucket.add(value, w.accuracy)
+	}
+	bucket.values = resetBucket(bucket.values, 0, minimumBucketLimit)
+}
+
+// rotate brings the window forward to the given time. The open bucket is
+// compacted as soon as a later bucket becomes current. The lock must be held
+// by the caller.
+func (w *SummaryWindow) rotate(adjustedTime int64) {
+	if adjustedTime <= w.ring.last {
+		return
+	}
+	if w.ring.contains(w.open) {
+		w.compact(w.ring.offset(w.open))
+	}
+	w.ring.advance(adjustedTime, w.clear)
+	w.open = adjustedTime
+}
+
+// AppendWithTimestamp same as Append but with timestamp as parameter. Values
+// for a closed bucket are added directly to its summary and values older than
+// the window are discarded.
+func (w *SummaryWindow) AppendWithTimestamp(value float64, timestamp time.Time) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	var adjustedTime = w.ring.index(timestamp)
+	w.rotate(adjustedTime)
+	if !w.ring.contains(adjustedTime) {
+		return
+	}
+	var offset = w.ring.offset(adjustedTime)
+	if adjustedTime != w.open {
+		w.buckets[offset].add(value, w.accuracy)
+		return
+	}
+	w.buckets[offset].values = append(w.buckets[offset].values, value)
+}
+
+// Append a value to the window.
+func (w *SummaryWindow) Append(value float64) {
+	w.AppendWithTimestamp(value, w.clock.Now())
+}
+
+// Len returns the number of values within the window.
+func (w *SummaryWindow) Len() int {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	w.rotate(w.ring.index(w.clock.Now()))
+	var count = 0
+	for _, bucket := range w.buckets {
+		count = count + bucket.count + len(bucket.values)
+	}
+	return count
+}
+
+// Summarize computes the count, sum, average, minimum, and maximum of every
+// value within the window along with each of the given percentiles. The
+// count, sum, minimum, and maximum are exact. The percentiles are computed
+// from the merged sketches of the closed buckets and the raw values of the
+// current bucket and are within the accuracy of the window. An empty window
+// has a Summary of zeros.
+func (w *SummaryWindow) Summarize(percentiles ...float64) Summary {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	w.rotate(w.ring.index(w.clock.Now()))
+	var s = Summary{Percentiles: make([]float64, len(percentiles)), requested: append([]float64(nil), percentiles...)}
+	var total = &compactBucket{}
+	var sketch *Sketch
+	if w.accuracy > 0 && len(percentiles) > 0 {
+		sketch = newSketch(w.accuracy)
+	}
+	for offset := range w.buckets {
+		var bucket = &w.buckets[offset]
+		total.merge(bucket)
+		if sketch != nil && bucket.sketch != nil {
+			_ = sketch.Merge(bucket.sketch)
+		}
+		for _, value := range bucket.values {
+			total.add(value, 0)
+			if sketch != nil {
+				sketch.Add(value)
+			}
+		}
+	}
+	if total.count < 1 {
+		return s
+	}
+	s.Count = float64(total.count)
+	s.Sum = total.sum
+	s.Avg = total.sum / float64(total.count)
+	s.Min = total.min
+	s.Max = total.max
+	if sketch != nil {
+		for offset, perc := range percentiles {
+			s.Percentiles[offset] = sketch.Percentile(perc)
+		}
+	}
+	return s
+}
@@ -0,0 +1,62 @@
+package rolling
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestSummaryWindow(t *testing.T) {
+	var c = &testClock{now: time.Unix(0, 0)}
+	var p = NewSummaryWindow(3, time.Second, 0.01, WithClock(c))
+	if s := p.Summarize(50); s.Count != 0 || s.Percentiles[0] != 0 {
+		t.Fatalf("expected an empty summary but got %+v", s)
+	}
+	for x := 1; x <= 100; x = x + 1 {
+		p.Append(float64(x))
+	}
+	if n := len(p.buckets[0].values); n != 100 {
+		t.Fatalf("expected the open bucket to keep its values but got %d", n)
+	}
+	c.now = c.now.Add(time.Second)
+	p.Append(-5)
+	if n := len(p.buckets[0].values); n != 0 || p.buckets[0].count != 100 {
+		t.Fatalf("expected the closed bucket to be compacted but got %d values and a count of %d", n, p.buckets[0].count)
+	}
+	p.AppendWithTimestamp(1000, time.Unix(0, 0))
+
+	var s = p.Summarize(50, 99)
+	if s.Count != 102 || s.Sum != 6045 || s.Min != -5 || s.Max != 1000 || p.Len() != 102 {
+		t.Fatalf("expected exact aggregates across compacted and open buckets but got %+v", s)
+	}
+	if math.Abs(s.Percentiles[0]-50)/50 > 0.02 || math.Abs(s.Percentiles[1]-99)/99 > 0.02 {
+		t.Fatalf("expected percentiles within the accuracy of the sketch but got %v", s.Percentiles)
+	}
+
+	c.now = c.now.Add(2 * time.Second)
+	if s = p.Summarize(); s.Count != 1 || s.Sum != -5 {
+		t.Fatalf("expected the first bucket to expire but got %+v", s)
+	}
+	p.AppendWithTimestamp(7, time.Unix(0, 0))
+	if n := p.Len(); n != 1 {
+		t.Fatalf("expected a value older than the window to be discarded but got %d", n)
+	}
+	c.now = c.now.Add(time.Hour)
+	if n := p.Len(); n != 0 {
+		t.Fatalf("expected an empty window but got %d", n)
+	}
+}
+
+func TestSummaryWindowWithoutSketch(t *testing.T) {
+	var c = &testClock{now: time.Unix(0, 0)}
+	var p = NewSummaryWindow(2, time.Second, 0, WithClock(c))
+	p.Append(4)
+	c.now = c.now.Add(time.Second)
+	p.Append(2)
+	if p.buckets[0].sketch != nil {
+		t.Fatal("expected no sketch to be kept")
+	}
+	if s := p.Summarize(50); s.Avg != 3 || s.Percentiles[0] != 0 {
+		t.Fatalf("expected aggregates without percentiles but got %+v", s)
+	}
+}
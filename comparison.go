@@ -0,0 +1,99 @@
+package rolling
+
+import (
+	"time"
+)
+
+// ComparisonPolicy is a rolling time window that also retains the period of
+// equal length that came immediately before it. This allows the aggregate of
+// the current period to be compared against the previous one, such as to
+// alert when traffic falls to a fraction of what it was five minutes ago.
+type ComparisonPolicy struct {
+	policy   *TimePolicy
+	buckets  int
+	current  Window
+	previous Window
+}
+
+// NewComparisonPolicy generates a Policy whose current period is made of the
+// buckets of the given window, each covering the given duration. An equal
+// number of buckets, with the same preallocated capacity, are added to hold
+// the previous period.
+func NewComparisonPolicy(window Window, bucketDuration time.Duration, options ...TimePolicyOption) *ComparisonPolicy {
+	var buckets = len(window)
+	var combined = make(Window, 2*buckets)
+	for offset, bucket := range window {
+		combined[offset] = bucket
+		combined[offset+buckets] = make([]float64, 0, cap(bucket))
+	}
+	return &ComparisonPolicy{
+		policy:   NewTimePolicy(combined, bucketDuration, options...),
+		buckets:  buckets,
+		current:  make(Window, 0, buckets),
+		previous: make(Window, 0, buckets),
+	}
+}
+
+// Append a value to the current period.
+func (w *ComparisonPolicy) Append(value float64) {
+	w.policy.Append(value)
+}
+
+// AppendWithTimestamp appends a value to the period that contains the given
+// time.
+func (w *ComparisonPolicy) AppendWithTimestamp(value float64, timestamp time.Time) {
+	w.policy.AppendWithTimestamp(value, timestamp)
+}
+
+// Reduce the current period to a single value using a reduction function.
+func (w *ComparisonPolicy) Reduce(f func(Window) float64) float64 {
+	var current, _ = w.Compare(f)
+	return current
+}
+
+// ReducePrevious reduces the previous period to a single value using a
+// reduction function.
+func (w *ComparisonPolicy) ReducePrevious(f func(Window) float64) float64 {
+	var _, previous = w.Compare(f)
+	return previous
+}
+
+// Compare reduces both the current and the previous period using the same
+// reduction function. Both are reduced while the window is locked so they
+// are consistent with each other.
+func (w *ComparisonPolicy) Compare(f func(Window) float64) (current float64, previous float64) {
+	w.policy.lock.Lock()
+	defer w.policy.lock.Unlock()
+
+	w.current = w.current[:0]
+	w.previous = w.previous[:0]
+	var age = 0
+	w.policy.eachBucket(w.policy.clock.Now(), func(_ time.Time, window Window) {
+		if age < w.buckets {
+			w.previous = append(w.previous, window[0])
+		} else {
+			w.current = append(w.current, window[0])
+		}
+		age = age + 1
+	})
+	return f(w.current), f(w.previous)
+}
+
+// Ratio returns the aggregate of the current period divided by that of the
+// previous period. A ratio of 0.4 means the current period is at 40% of the
+// previous one. The boolean result is false if the previous aggregate is zero
+// and no ratio can be computed.
+func (w *ComparisonPolicy) Ratio(f func(Window) float64) (float64, bool) {
+	var current, previous = w.Compare(f)
+	if previous == 0 {
+		return 0, false
+	}
+	return current / previous, true
+}
+
+// Delta returns the aggregate of the current period minus that of the
+// previous period.
+func (w *ComparisonPolicy) Delta(f func(Window) float64) float64 {
+	var current, previous = w.Compare(f)
+	return current - previous
+}
@@ -0,0 +1,45 @@
+package rolling
+
+import (
+	"testing"
+	"time"
+)
+
+func TestComparisonWindow(t *testing.T) {
+	var c = &testClock{now: time.Unix(0, 0)}
+	var p = NewComparisonPolicy(NewWindow(5), time.Second, WithClock(c))
+	for x := 0; x < 5; x = x + 1 {
+		p.Append(10)
+		c.now = c.now.Add(time.Second)
+	}
+	c.now = c.now.Add(-time.Second)
+	if _, ok := p.Ratio(Sum); ok {
+		t.Fatal("expected no ratio without a previous period")
+	}
+	c.now = c.now.Add(time.Second)
+	for x := 0; x < 5; x = x + 1 {
+		p.Append(4)
+		c.now = c.now.Add(time.Second)
+	}
+	// The clock has moved into the next bucket so step back into the last
+	// bucket of the second period.
+	c.now = c.now.Add(-time.Second)
+
+	var current, previous = p.Compare(Sum)
+	if !floatEquals(current, 20) || !floatEquals(previous, 50) {
+		t.Fatalf("comparison calculated incorrectly: %f, %f versus %f, %f", 20.0, 50.0, current, previous)
+	}
+	var ratio, ok = p.Ratio(Sum)
+	if !ok || !floatEquals(ratio, .4) {
+		t.Fatalf("ratio calculated incorrectly: %f versus %f", .4, ratio)
+	}
+	if delta := p.Delta(Avg); !floatEquals(delta, -6) {
+		t.Fatalf("delta calculated incorrectly: %f versus %f", -6.0, delta)
+	}
+	if result := p.Reduce(Count); !floatEquals(result, 5) {
+		t.Fatalf("current period counted incorrectly: %f versus %f", 5.0, result)
+	}
+	if result := p.ReducePrevious(Count); !floatEquals(result, 5) {
+		t.Fatalf("previous period counted incorrectly: %f versus %f", 5.0, result)
+	}
+}
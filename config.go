@@ -0,0 +1,179 @@
+package rolling
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Duration is a time.Duration that is written in configuration as a string
+// such as "5m" or "250ms".
+type Duration time.Duration
+
+// UnmarshalJSON parses a duration from a string understood by
+// time.ParseDuration or from a number of nanoseconds.
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		var n int64
+		if err := json.Unmarshal(b, &n); err != nil {
+			return fmt.Errorf("rolling: duration must be a string or a number: %s", string(b))
+		}
+		*d = Duration(n)
+		return nil
+	}
+	var parsed, err = time.ParseDuration(s)
+	if err != nil {
+		return fmt.Errorf("rolling: invalid duration %q: %v", s, err)
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// MarshalJSON writes the duration as a string.
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// WindowConfig describes a single window and the aggregate that is reported
+// for it.
+type WindowConfig struct {
+	// Name identifies the window among the others in the same Config.
+	Name string `json:"name"`
+	// Type is one of "point", "time", "bounded", or "gauge".
+	Type string `json:"type"`
+	// Size is the number of buckets, or points, in the window.
+	Size int `json:"size"`
+	// BucketDuration is the duration of each bucket of a time window.
+	BucketDuration Duration `json:"bucketDuration,omitempty"`
+	// MaxAge is the duration covered by a bounded or gauge window.
+	MaxAge Duration `json:"maxAge,omitempty"`
+	// Aggregate names the reduction that is reported for the window. See
+	// ParseAggregate for the supported names.
+	Aggregate string `json:"aggregate"`
+}
+
+// Config describes a set of windows so that services with many metrics may
+// define them as data rather than as a series of constructor calls.
+type Config struct {
+	Windows []WindowConfig `json:"windows"`
+}
+
+// LoadConfig decodes a JSON Config from the given reader.
+func LoadConfig(r io.Reader) (*Config, error) {
+	var c = &Config{}
+	var decoder = json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(c); err != nil {
+		return nil, fmt.Errorf("rolling: invalid configuration: %v", err)
+	}
+	return c, nil
+}
+
+// Metric is a window built from a WindowConfig along with its aggregate.
+type Metric struct {
+	Name      string
+	Policy    Policy
+	Aggregate func(Window) float64
+}
+
+// Value reduces the window of the metric using its aggregate.
+func (m *Metric) Value() float64 {
+	return m.Policy.Reduce(m.Aggregate)
+}
+
+// Build creates a Metric for every window in the Config. The given options
+// are applied to every time based window. An error is returned, and no
+// windows are built, if any window is invalid or if two share a name.
+func (c *Config) Build(options ...TimePolicyOption) (map[string]*Metric, error) {
+	var result = make(map[string]*Metric, len(c.Windows))
+	for _, wc := range c.Windows {
+		if _, ok := result[wc.Name]; ok {
+			return nil, fmt.Errorf("rolling: duplicate window name %q", wc.Name)
+		}
+		var m, err = wc.Build(options...)
+		if err != nil {
+			return nil, err
+		}
+		result[wc.Name] = m
+	}
+	return result, nil
+}
+
+// Build creates a Metric from the WindowConfig.
+func (c WindowConfig) Build(options ...TimePolicyOption) (*Metric, error) {
+	if c.Size < 1 {
+		return nil, fmt.Errorf("rolling: window %q must have a size of at least one", c.Name)
+	}
+	var aggregate, err = ParseAggregate(c.Aggregate)
+	if err != nil {
+		return nil, fmt.Errorf("rolling: window %q: %v", c.Name, err)
+	}
+	var policy Policy
+	switch c.Type {
+	case "point":
+		policy = NewPointPolicy(NewWindow(c.Size))
+	case "time":
+		if c.BucketDuration <= 0 {
+			return nil, fmt.Errorf("rolling: time window %q requires a bucketDuration", c.Name)
+		}
+		policy = NewTimePolicy(NewWindow(c.Size), time.Duration(c.BucketDuration), options...)
+	case "bounded":
+		if c.MaxAge <= 0 {
+			return nil, fmt.Errorf("rolling: bounded window %q requires a maxAge", c.Name)
+		}
+		policy = NewBoundedPolicy(NewWindow(c.Size), time.Duration(c.MaxAge), options...)
+	case "gauge":
+		if c.MaxAge <= 0 {
+			return nil, fmt.Errorf("rolling: gauge window %q requires a maxAge", c.Name)
+		}
+		policy = NewGaugePolicy(NewWindow(c.Size), time.Duration(c.MaxAge), options...)
+	default:
+		return nil, fmt.Errorf("rolling: window %q has unknown type %q", c.Name, c.Type)
+	}
+	return &Metric{Name: c.Name, Policy: policy, Aggregate: aggregate}, nil
+}
+
+// ParseAggregate returns the reduction function with the given name. The
+// names "count", "sum", "avg", "min", "max", "first", "last", "product", and
+// "iqr" refer to the reducers of the same name. A "p" followed by a number,
+// such as "p99.9", is a Percentile and an "fp" followed by a number is a
+// FastPercentile.
+func ParseAggregate(name string) (func(Window) float64, error) {
+	switch name {
+	case "count":
+		return Count, nil
+	case "sum":
+		return Sum, nil
+	case "avg":
+		return Avg, nil
+	case "min":
+		return Min, nil
+	case "max":
+		return Max, nil
+	case "first":
+		return First, nil
+	case "last":
+		return Last, nil
+	case "product":
+		return Product, nil
+	case "iqr":
+		return IQR, nil
+	}
+	var fast = strings.HasPrefix(name, "fp")
+	var number = strings.TrimPrefix(strings.TrimPrefix(name, "f"), "p")
+	if !strings.HasPrefix(name, "p") && !fast {
+		return nil, fmt.Errorf("unknown aggregate %q", name)
+	}
+	var perc, err = strconv.ParseFloat(number, 64)
+	if err != nil || perc < 0 || perc > 100 {
+		return nil, fmt.Errorf("invalid percentile aggregate %q", name)
+	}
+	if fast {
+		return FastPercentile(perc), nil
+	}
+	return Percentile(perc), nil
+}
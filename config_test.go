@@ -0,0 +1,83 @@
+package rolling
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoadConfig(t *testing.T) {
+	var c, err = LoadConfig(strings.NewReader(`{
+		"windows": [
+			{"name": "requests", "type": "time", "size": 60, "bucketDuration": "1s", "aggregate": "sum"},
+			{"name": "latency", "type": "point", "size": 100, "aggregate": "p99"},
+			{"name": "queue", "type": "gauge", "size": 100, "maxAge": "5m", "aggregate": "max"}
+		]
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var metrics map[string]*Metric
+	metrics, err = c.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(metrics) != 3 {
+		t.Fatalf("expected three metrics but got %d", len(metrics))
+	}
+	if _, ok := metrics["requests"].Policy.(*TimePolicy); !ok {
+		t.Fatalf("expected a time window but got %T", metrics["requests"].Policy)
+	}
+	if d := metrics["requests"].Policy.(*TimePolicy).BucketSize(); d != time.Second {
+		t.Fatalf("expected one second buckets but got %s", d)
+	}
+	for x := 1; x <= 100; x = x + 1 {
+		metrics["latency"].Policy.Append(float64(x))
+	}
+	if result := metrics["latency"].Value(); !floatEquals(result, 99.5) {
+		t.Fatalf("configured aggregate calculated incorrectly: %f versus %f", 99.5, result)
+	}
+}
+
+func TestConfigInvalid(t *testing.T) {
+	var tc = []WindowConfig{
+		{Name: "size", Type: "point", Aggregate: "sum"},
+		{Name: "type", Type: "unknown", Size: 1, Aggregate: "sum"},
+		{Name: "bucket", Type: "time", Size: 1, Aggregate: "sum"},
+		{Name: "age", Type: "bounded", Size: 1, Aggregate: "sum"},
+		{Name: "aggregate", Type: "point", Size: 1, Aggregate: "median"},
+		{Name: "percentile", Type: "point", Size: 1, Aggregate: "p101"},
+	}
+	for _, c := range tc {
+		if _, err := c.Build(); err == nil {
+			t.Fatalf("expected an error for window %q", c.Name)
+		}
+	}
+	var duplicate = &Config{Windows: []WindowConfig{
+		{Name: "a", Type: "point", Size: 1, Aggregate: "sum"},
+		{Name: "a", Type: "point", Size: 1, Aggregate: "sum"},
+	}}
+	if _, err := duplicate.Build(); err == nil {
+		t.Fatal("expected an error for duplicate names")
+	}
+	if _, err := LoadConfig(strings.NewReader(`{"windows": [{"maxAge": "soon"}]}`)); err == nil {
+		t.Fatal("expected an error for an invalid duration")
+	}
+}
+
+func TestParseAggregate(t *testing.T) {
+	var w = Window{{1, 2, 3, 4, 5}}
+	var tc = map[string]float64{
+		"count": 5, "sum": 15, "avg": 3, "min": 1, "max": 5, "first": 1,
+		"last": 5, "product": 120, "iqr": 2.5, "p50": 3, "fp50": 3,
+	}
+	for name, expected := range tc {
+		var f, err = ParseAggregate(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result := f(w); !floatEquals(result, expected) {
+			t.Fatalf("%s calculated incorrectly: %f versus %f", name, expected, result)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package rolling
+
+// CopyTo copies the values appended to the window into the given slice, from
+// the oldest to the newest, and returns the number of values copied. As with
+// the built in copy, no more than len(buf) values are copied, so a buffer
+// with a length of at least Len receives every value. The values are copied
+// while the window is locked only once and without a call per value.
+func (w *PointPolicy) CopyTo(buf []float64) int {
+	w.lock.RLock()
+	defer w.lock.RUnlock()
+
+	var start = (w.offset - w.count + w.windowSize) % w.windowSize
+	var n = w.count
+	if n > len(buf) {
+		n = len(buf)
+	}
+	var copied = copy(buf[:n], w.values[start:])
+	copy(buf[copied:n], w.values)
+	return n
+}
+
+// CopyTo copies the values within the window into the given slice, from the
+// oldest bucket to the newest, and returns the number of values copied. As
+// with the built in copy, no more than len(buf) values are copied, so a
+// buffer with a length of at least Len receives every value. The values are
+// copied while the window is locked only once and a bucket at a time.
+func (w *TimePolicy) CopyTo(buf []float64) int {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	var adjustedTime = w.ring.index(w.clock.Now())
+	w.keepConsistent(adjustedTime)
+	var n = 0
+	for age := w.ring.numberOfBuckets - 1; age >= 0 && n < len(buf); age = age - 1 {
+		n = n + copy(buf[n:], w.window[w.ring.offset(adjustedTime-age)])
+	}
+	return n
+}
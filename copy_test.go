@@ -0,0 +1,48 @@
+package rolling
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPointPolicyCopyTo(t *testing.T) {
+	var p = NewPointPolicy(NewWindow(4))
+	var buf = make([]float64, 4)
+	if n := p.CopyTo(buf); n != 0 {
+		t.Fatalf("expected nothing to copy from an empty window but got %d", n)
+	}
+	for x := 1; x <= 6; x = x + 1 {
+		p.Append(float64(x))
+	}
+	if n := p.CopyTo(buf); n != 4 || buf[0] != 3 || buf[1] != 4 || buf[2] != 5 || buf[3] != 6 {
+		t.Fatalf("expected the values oldest first but got %d %v", n, buf)
+	}
+	var small = make([]float64, 3)
+	if n := p.CopyTo(small); n != 3 || small[0] != 3 || small[2] != 5 {
+		t.Fatalf("expected the oldest values to fill the buffer but got %d %v", n, small)
+	}
+	p = NewPointPolicy(NewWindow(4))
+	p.Append(1)
+	p.Append(2)
+	if n := p.CopyTo(buf); n != 2 || buf[0] != 1 || buf[1] != 2 {
+		t.Fatalf("expected only the appended values but got %d %v", n, buf)
+	}
+}
+
+func TestTimePolicyCopyTo(t *testing.T) {
+	var c = &testClock{now: time.Unix(0, 0)}
+	var p = NewTimePolicy(NewWindow(3), time.Second, WithClock(c))
+	for x := 1; x <= 4; x = x + 1 {
+		p.AppendBatch([]float64{float64(x), float64(x)})
+		c.now = c.now.Add(time.Second)
+	}
+	c.now = c.now.Add(-time.Second)
+	var buf = make([]float64, p.Len())
+	if n := p.CopyTo(buf); n != 6 || buf[0] != 2 || buf[1] != 2 || buf[5] != 4 {
+		t.Fatalf("expected the values oldest first but got %d %v", n, buf)
+	}
+	var small = make([]float64, 3)
+	if n := p.CopyTo(small); n != 3 || small[2] != 3 {
+		t.Fatalf("expected the oldest values to fill the buffer but got %d %v", n, small)
+	}
+}
@@ -0,0 +1,85 @@
+package rolling
+
+import "math"
+
+// alignedBuckets reduces each bucket of two windows with the given aggregate
+// and returns the pairs of results for the buckets at matching positions. The
+// buckets of two time windows with the same number of buckets, bucket duration,
+// and clock cover the same periods, and the points of two point windows that
+// are appended to in lockstep hold matching values. Positions at which either
+// bucket is empty are skipped. Each window is copied under its own lock so
+// either may be a Combine or any other Reducer.
+func alignedBuckets(w1 Reducer, w2 Reducer, aggregate func(Window) float64) ([]float64, []float64) {
+	var first = copyWindow(w1)
+	var second = copyWindow(w2)
+	var size = len(first)
+	if len(second) < size {
+		size = len(second)
+	}
+	var xs = make([]float64, 0, size)
+	var ys = make([]float64, 0, size)
+	for offset := 0; offset < size; offset = offset + 1 {
+		if len(first[offset]) < 1 || len(second[offset]) < 1 {
+			continue
+		}
+		xs = append(xs, aggregate(first[offset:offset+1]))
+		ys = append(ys, aggregate(second[offset:offset+1]))
+	}
+	return xs, ys
+}
+
+// comoments returns the sums of the products of the deviations of each pair
+// of values from the means: the co-moment of the pairs and the sum of squared
+// deviations of each side.
+func comoments(xs []float64, ys []float64) (float64, float64, float64) {
+	var xMean, yMean = 0.0, 0.0
+	for offset := range xs {
+		xMean = xMean + xs[offset]
+		yMean = yMean + ys[offset]
+	}
+	xMean = xMean / float64(len(xs))
+	yMean = yMean / float64(len(ys))
+	var xy, xx, yy = 0.0, 0.0, 0.0
+	for offset := range xs {
+		var dx = xs[offset] - xMean
+		var dy = ys[offset] - yMean
+		xy = xy + dx*dy
+		xx = xx + dx*dx
+		yy = yy + dy*dy
+	}
+	return xy, xx, yy
+}
+
+// Correlation returns the Pearson correlation coefficient of two windows,
+// between -1 and 1, and whether one could be computed. Each bucket of each
+// window is reduced with the given aggregate, such as Avg, and the results
+// are paired by position so that, for example, the average latency of each
+// second is compared with the average queue depth of the same second. A
+// coefficient near 1 means the two rise and fall together and one near -1
+// means one rises as the other falls. No coefficient is available with fewer
+// than two pairs of buckets or when either side does not vary.
+func Correlation(w1 Reducer, w2 Reducer, aggregate func(Window) float64) (float64, bool) {
+	var xs, ys = alignedBuckets(w1, w2, aggregate)
+	if len(xs) < 2 {
+		return 0, false
+	}
+	var covariance, xVariance, yVariance = comoments(xs, ys)
+	if xVariance == 0 || yVariance == 0 {
+		return 0, false
+	}
+	return covariance / math.Sqrt(xVariance*yVariance), true
+}
+
+// Covariance returns the population covariance of two windows and whether one
+// could be computed. The buckets of the windows are reduced with the given
+// aggregate and paired by position in the same way as Correlation. A positive
+// covariance means the two tend to rise and fall together. No covariance is
+// available when no pair of buckets both contain values.
+func Covariance(w1 Reducer, w2 Reducer, aggregate func(Window) float64) (float64, bool) {
+	var xs, ys = alignedBuckets(w1, w2, aggregate)
+	if len(xs) < 1 {
+		return 0, false
+	}
+	var covariance, _, _ = comoments(xs, ys)
+	return covariance / float64(len(xs)), true
+}
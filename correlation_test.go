@@ -0,0 +1,100 @@
+package rolling
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCorrelation(t *testing.T) {
+	var a = NewPointPolicy(NewWindow(5))
+	var b = NewPointPolicy(NewWindow(5))
+	var c = NewPointPolicy(NewWindow(5))
+	for x := 1; x <= 5; x = x + 1 {
+		a.Append(float64(x))
+		b.Append(float64(2*x + 1))
+		c.Append(float64(-x))
+	}
+	if r, ok := Correlation(a, b, Avg); !ok || !floatMostlyEquals(r, 1) {
+		t.Fatalf("expected a perfect correlation but got %f %v", r, ok)
+	}
+	if r, ok := Correlation(a, c, Avg); !ok || !floatMostlyEquals(r, -1) {
+		t.Fatalf("expected a perfect inverse correlation but got %f %v", r, ok)
+	}
+
+	var flat = NewPointPolicy(NewWindow(5))
+	for x := 1; x <= 5; x = x + 1 {
+		flat.Append(3)
+	}
+	if _, ok := Correlation(a, flat, Avg); ok {
+		t.Fatal("expected no correlation with a window that does not vary")
+	}
+}
+
+func TestCorrelationAlignedByTime(t *testing.T) {
+	var clock = &testClock{now: time.Unix(0, 0)}
+	var latency = NewTimePolicy(NewWindow(4), time.Second, WithClock(clock))
+	var depth = NewTimePolicy(NewWindow(4), time.Second, WithClock(clock))
+	for x := 1; x <= 3; x = x + 1 {
+		latency.AppendBatch([]float64{float64(x), float64(x) + 2})
+		depth.Append(float64(10 * x))
+		clock.now = clock.now.Add(time.Second)
+	}
+	// A period with latency but no queue depth is skipped.
+	latency.Append(100)
+	if r, ok := Correlation(latency, depth, Avg); !ok || !floatMostlyEquals(r, 1) {
+		t.Fatalf("expected the averages of each second to correlate but got %f %v", r, ok)
+	}
+	clock.now = clock.now.Add(time.Hour)
+	if _, ok := Correlation(latency, depth, Avg); ok {
+		t.Fatal("expected no correlation for empty windows")
+	}
+}
+
+func TestCovariance(t *testing.T) {
+	var a = NewPointPolicy(NewWindow(4))
+	var b = NewPointPolicy(NewWindow(4))
+	for x := 1; x <= 4; x = x + 1 {
+		a.Append(float64(x))
+		b.Append(float64(2 * x))
+	}
+	if cov, ok := Covariance(a, b, Avg); !ok || !floatMostlyEquals(cov, 2.5) {
+		t.Fatalf("expected a covariance of 2.5 but got %f %v", cov, ok)
+	}
+	if cov, ok := Covariance(a, a.Clone(), Avg); !ok || !floatMostlyEquals(cov, a.Reduce(Variance)) {
+		t.Fatalf("expected the covariance of a window with itself to be its variance but got %f %v", cov, ok)
+	}
+
+	var clock = &testClock{now: time.Unix(0, 0)}
+	var empty = NewTimePolicy(NewWindow(4), time.Second, WithClock(clock))
+	if _, ok := Covariance(empty, empty.Clone(), Avg); ok {
+		t.Fatal("expected no covariance for empty windows")
+	}
+}
+
+func TestCorrelationOfCombinedWindows(t *testing.T) {
+	var a = NewPointPolicy(NewWindow(2))
+	var b = NewPointPolicy(NewWindow(2))
+	var x = NewPointPolicy(NewWindow(4))
+	for v := 1; v <= 2; v = v + 1 {
+		a.Append(float64(v))
+		b.Append(float64(v + 2))
+	}
+	for v := 1; v <= 4; v = v + 1 {
+		x.Append(float64(10 * v))
+	}
+	var done = make(chan struct{})
+	go func() {
+		defer close(done)
+		if r, ok := Correlation(Combine(a, b), x, Avg); !ok || !floatMostlyEquals(r, 1) {
+			t.Errorf("expected a perfect correlation with a combined window but got %f %v", r, ok)
+		}
+		if _, ok := Covariance(Combine(a, b), Combine(x), Avg); !ok {
+			t.Error("expected a covariance of combined windows")
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out, likely deadlocked")
+	}
+}
@@ -0,0 +1,90 @@
+package rolling
+
+import (
+	"sync"
+	"time"
+)
+
+// CounterPolicy is a rolling time window, like TimePolicy, in which each
+// bucket holds a single running total rather than every value appended to it.
+// Appending is a constant time addition that never allocates and the memory
+// of the window does not grow with the number of values. This suits request
+// counts and rate limiting where only the sum of each bucket matters.
+type CounterPolicy struct {
+	values []float64
+	window Window
+	ring   ring
+	clock  Clock
+	lock   sync.Locker
+}
+
+// NewCounterPolicy generates a CounterPolicy with the given number of
+// buckets, each covering the given duration. Only the WithClock and
+// WithAlignment options apply to a CounterPolicy.
+func NewCounterPolicy(buckets int, bucketDuration time.Duration, options ...TimePolicyOption) *CounterPolicy {
+	var o = newTimeOptions(options)
+	var values = make([]float64, buckets)
+	return &CounterPolicy{
+		values: values,
+		window: flatWindow(NewWindow(buckets), values),
+		ring:   newTimeRing(buckets, bucketDuration, o.originNano),
+		clock:  o.clock,
+		lock:   &sync.Mutex{},
+	}
+}
+
+func (w *CounterPolicy) zero(offset int) {
+	w.values[offset] = 0
+}
+
+// AddWithTimestamp adds a value to the total of the bucket that contains the
+// given time. Values older than the window are discarded.
+func (w *CounterPolicy) AddWithTimestamp(value float64, timestamp time.Time) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	var adjustedTime = w.ring.index(timestamp)
+	w.ring.advance(adjustedTime, w.zero)
+	if !w.ring.contains(adjustedTime) {
+		return
+	}
+	var offset = w.ring.offset(adjustedTime)
+	w.values[offset] = w.values[offset] + value
+}
+
+// Add a value to the total of the current bucket.
+func (w *CounterPolicy) Add(value float64) {
+	w.AddWithTimestamp(value, w.clock.Now())
+}
+
+// Append a value to the window. This is the same as Add and allows the window
+// to be used as a Policy.
+func (w *CounterPolicy) Append(value float64) {
+	w.Add(value)
+}
+
+// Total returns the sum of every bucket within the window.
+func (w *CounterPolicy) Total() float64 {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	w.ring.advance(w.ring.index(w.clock.Now()), w.zero)
+	var total = 0.0
+	for _, value := range w.values {
+		total = total + value
+	}
+	return total
+}
+
+// Reduce the window to a single value using a reduction function. Each bucket
+// of the window given to the function contains exactly one value, the total
+// of that bucket, so reductions such as Sum and Max operate on totals while
+// Count returns the number of buckets rather than the number of values
+// appended.
+func (w *CounterPolicy) Reduce(f func(Window) float64) float64 {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	w.ring.advance(w.ring.index(w.clock.Now()), w.zero)
+	return f(w.window)
+}
@@ -0,0 +1,53 @@
+package rolling
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCounterPolicy(t *testing.T) {
+	var c = &testClock{now: time.Unix(0, 0)}
+	var p = NewCounterPolicy(3, time.Second, WithClock(c))
+	p.Append(1)
+	p.Add(2)
+	c.now = c.now.Add(time.Second)
+	p.Add(4)
+	if total := p.Total(); total != 7 {
+		t.Fatalf("expected a total of 7 but got %f", total)
+	}
+	if max := p.Reduce(Max); max != 4 {
+		t.Fatalf("expected the largest bucket total to be 4 but got %f", max)
+	}
+	if count := p.Reduce(Count); count != 3 {
+		t.Fatalf("expected one value per bucket but got %f", count)
+	}
+
+	c.now = c.now.Add(2 * time.Second)
+	if total := p.Total(); total != 4 {
+		t.Fatalf("expected the first bucket to expire but got %f", total)
+	}
+	p.AddWithTimestamp(8, time.Unix(0, 0))
+	if total := p.Total(); total != 4 {
+		t.Fatalf("expected a value older than the window to be discarded but got %f", total)
+	}
+	p.AddWithTimestamp(8, time.Unix(2, 0))
+	if total := p.Reduce(Sum); total != 12 {
+		t.Fatalf("expected a late value within the window to be counted but got %f", total)
+	}
+	c.now = c.now.Add(time.Hour)
+	if total := p.Total(); total != 0 {
+		t.Fatalf("expected an empty window but got %f", total)
+	}
+}
+
+func TestCounterPolicyAllocations(t *testing.T) {
+	var c = &testClock{now: time.Unix(0, 0)}
+	var p = NewCounterPolicy(10, time.Second, WithClock(c))
+	var allocs = testing.AllocsPerRun(100, func() {
+		c.now = c.now.Add(100 * time.Millisecond)
+		p.Add(1)
+	})
+	if allocs != 0 {
+		t.Fatalf("expected no allocations but got %f", allocs)
+	}
+}
@@ -0,0 +1,97 @@
+package rolling
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+)
+
+// csvRow is a single value of a window along with its timestamp.
+type csvRow struct {
+	timestamp time.Time
+	value     float64
+}
+
+// writeCSV writes a header followed by one row for each of the given rows.
+// Rows are copied out of a window while it is locked and written afterwards
+// so that a slow writer does not block appends to the window.
+func writeCSV(w io.Writer, rows []csvRow) error {
+	var writer = csv.NewWriter(w)
+	_ = writer.Write([]string{"timestamp", "value"})
+	var record = make([]string, 2)
+	for _, row := range rows {
+		record[0] = row.timestamp.Format(time.RFC3339Nano)
+		record[1] = strconv.FormatFloat(row.value, 'g', -1, 64)
+		_ = writer.Write(record)
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// WriteCSV writes every value in the window to the given writer as CSV rows
+// of a timestamp and a value, from the oldest to the newest, following a
+// header row. The timestamp of each value is the time at which its bucket
+// begins. This allows the exact data behind an aggregate to be captured and
+// analyzed elsewhere.
+func (w *TimePolicy) WriteCSV(out io.Writer) error {
+	return writeCSV(out, w.csvRows())
+}
+
+func (w *TimePolicy) csvRows() []csvRow {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	var rows = make([]csvRow, 0, w.size)
+	w.eachBucket(w.clock.Now(), func(start time.Time, window Window) {
+		for _, value := range window[0] {
+			rows = append(rows, csvRow{timestamp: start, value: value})
+		}
+	})
+	return rows
+}
+
+// WriteCSV writes every value in the window to the given writer as CSV rows
+// of the time the value was observed and the value, from the oldest to the
+// newest, following a header row.
+func (w *GaugePolicy) WriteCSV(out io.Writer) error {
+	return writeCSV(out, w.csvRows())
+}
+
+func (w *GaugePolicy) csvRows() []csvRow {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	var cutoff = w.clock.Now().Add(-w.duration)
+	var rows = make([]csvRow, 0, w.count)
+	for x := 0; x < w.count; x = x + 1 {
+		var offset = w.index(x)
+		if !w.times[offset].Before(cutoff) {
+			rows = append(rows, csvRow{timestamp: w.times[offset], value: w.window[offset][0]})
+		}
+	}
+	return rows
+}
+
+// WriteCSV writes every value in the window to the given writer as CSV rows
+// of the time the value was appended and the value, from the oldest to the
+// newest, following a header row. Values older than the maximum age are
+// removed first.
+func (w *BoundedPolicy) WriteCSV(out io.Writer) error {
+	return writeCSV(out, w.csvRows())
+}
+
+func (w *BoundedPolicy) csvRows() []csvRow {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	w.expire(w.clock.Now())
+	var rows = make([]csvRow, 0, w.windowSize)
+	for x := 0; x < w.windowSize; x = x + 1 {
+		var offset = (w.offset + x) % w.windowSize
+		if len(w.window[offset]) > 0 {
+			rows = append(rows, csvRow{timestamp: w.times[offset], value: w.window[offset][0]})
+		}
+	}
+	return rows
+}
@@ -0,0 +1,88 @@
+package rolling
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestTimeWindowWriteCSV(t *testing.T) {
+	var c = &testClock{now: time.Unix(0, 0)}
+	var p = NewTimePolicy(NewWindow(3), time.Second, WithClock(c))
+	p.Append(1)
+	c.now = c.now.Add(2 * time.Second)
+	p.Append(2.5)
+	p.Append(3)
+	var b = &bytes.Buffer{}
+	if err := p.WriteCSV(b); err != nil {
+		t.Fatal(err)
+	}
+	var expected = "timestamp,value\n" +
+		time.Unix(0, 0).Format(time.RFC3339Nano) + ",1\n" +
+		time.Unix(2, 0).Format(time.RFC3339Nano) + ",2.5\n" +
+		time.Unix(2, 0).Format(time.RFC3339Nano) + ",3\n"
+	if b.String() != expected {
+		t.Fatalf("expected\n%s\nbut got\n%s", expected, b.String())
+	}
+}
+
+func TestGaugeWindowWriteCSV(t *testing.T) {
+	var c = &testClock{now: time.Unix(0, 0)}
+	var p = NewGaugePolicy(NewWindow(3), 10*time.Second, WithClock(c))
+	p.AppendWithTimestamp(1, time.Unix(0, 0))
+	p.AppendWithTimestamp(2, time.Unix(5, 0))
+	c.now = time.Unix(12, 0)
+	var b = &bytes.Buffer{}
+	if err := p.WriteCSV(b); err != nil {
+		t.Fatal(err)
+	}
+	var expected = "timestamp,value\n" + time.Unix(5, 0).Format(time.RFC3339Nano) + ",2\n"
+	if b.String() != expected {
+		t.Fatalf("expected\n%s\nbut got\n%s", expected, b.String())
+	}
+}
+
+func TestBoundedWindowWriteCSV(t *testing.T) {
+	var c = &testClock{now: time.Unix(0, 0)}
+	var p = NewBoundedPolicy(NewWindow(2), time.Minute, WithClock(c))
+	for x := 1; x <= 3; x = x + 1 {
+		p.Append(float64(x))
+		c.now = c.now.Add(time.Second)
+	}
+	var b = &bytes.Buffer{}
+	if err := p.WriteCSV(b); err != nil {
+		t.Fatal(err)
+	}
+	var expected = "timestamp,value\n" +
+		time.Unix(1, 0).Format(time.RFC3339Nano) + ",2\n" +
+		time.Unix(2, 0).Format(time.RFC3339Nano) + ",3\n"
+	if b.String() != expected {
+		t.Fatalf("expected\n%s\nbut got\n%s", expected, b.String())
+	}
+}
+
+// appendingWriter appends to a window each time it is written to, which
+// deadlocks if the window is locked during the write.
+type appendingWriter struct {
+	policy Policy
+}
+
+func (w appendingWriter) Write(b []byte) (int, error) {
+	w.policy.Append(1)
+	return len(b), nil
+}
+
+func TestWriteCSVReleasesLock(t *testing.T) {
+	var c = &testClock{now: time.Unix(0, 0)}
+	var timed = NewTimePolicy(NewWindow(3), time.Second, WithClock(c))
+	var gauge = NewGaugePolicy(NewWindow(3), time.Second, WithClock(c))
+	var bounded = NewBoundedPolicy(NewWindow(3), time.Second, WithClock(c))
+	withinTimeout(t, func() {
+		_ = timed.WriteCSV(appendingWriter{policy: timed})
+		_ = gauge.WriteCSV(appendingWriter{policy: gauge})
+		_ = bounded.WriteCSV(appendingWriter{policy: bounded})
+	})
+	if timed.Len() != 1 {
+		t.Fatalf("expected the writer to append to the window but got %d values", timed.Len())
+	}
+}
@@ -0,0 +1,68 @@
+package rolling
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"text/tabwriter"
+)
+
+// windowStats describes the storage of a window at a point in time.
+type windowStats struct {
+	values  int
+	buckets int
+	used    int
+	bytes   int
+}
+
+// statsOf computes the windowStats of a window. The memory estimate includes
+// the capacity of every bucket and the slice headers of the window itself.
+func statsOf(w Window) windowStats {
+	var s = windowStats{buckets: len(w), bytes: 24 * cap(w)}
+	for _, bucket := range w {
+		s.values = s.values + len(bucket)
+		s.bytes = s.bytes + 8*cap(bucket)
+		if len(bucket) > 0 {
+			s.used = s.used + 1
+		}
+	}
+	return s
+}
+
+// DebugHandler returns an http.Handler that lists every given Metric along
+// with its current value, the number of values and buckets in its window,
+// the fraction of buckets that contain values, and an estimate of the memory
+// used by the window. The page is plain text and is intended for a person
+// investigating the state of a service, in the manner of the pprof pages:
+//
+//	http.Handle("/debug/rolling", rolling.DebugHandler(metrics))
+func DebugHandler(metrics map[string]*Metric) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var names = make([]string, 0, len(metrics))
+		for name := range metrics {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		var tw = tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+		_, _ = fmt.Fprintln(tw, "NAME\tTYPE\tVALUE\tVALUES\tBUCKETS\tUSED\tBYTES")
+		for _, name := range names {
+			var m = metrics[name]
+			var stats windowStats
+			var value = m.Policy.Reduce(func(window Window) float64 {
+				stats = statsOf(window)
+				return m.Aggregate(window)
+			})
+			var used = 0.0
+			if stats.buckets > 0 {
+				used = float64(stats.used) / float64(stats.buckets)
+			}
+			_, _ = fmt.Fprintf(tw, "%s\t%T\t%s\t%d\t%d\t%.0f%%\t%d\n",
+				name, m.Policy, strconv.FormatFloat(value, 'g', -1, 64),
+				stats.values, stats.buckets, 100*used, stats.bytes)
+		}
+		_ = tw.Flush()
+	})
+}
@@ -0,0 +1,32 @@
+package rolling
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDebugHandler(t *testing.T) {
+	var c = &testClock{now: time.Unix(0, 0)}
+	var p = NewTimePolicy(NewPreallocatedWindow(4, 2), time.Second, WithClock(c))
+	p.Append(1)
+	p.Append(2)
+	var metrics = map[string]*Metric{
+		"requests": {Name: "requests", Policy: p, Aggregate: Sum},
+	}
+	var recorder = httptest.NewRecorder()
+	DebugHandler(metrics).ServeHTTP(recorder, httptest.NewRequest("GET", "/debug/rolling", nil))
+	if ct := recorder.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain") {
+		t.Fatalf("unexpected content type %q", ct)
+	}
+	var lines = strings.Split(strings.TrimSpace(recorder.Body.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected a header and one metric but got:\n%s", recorder.Body.String())
+	}
+	var fields = strings.Fields(lines[1])
+	var expected = []string{"requests", "*rolling.TimePolicy", "3", "2", "4", "25%", "160"}
+	if strings.Join(fields, " ") != strings.Join(expected, " ") {
+		t.Fatalf("expected %v but got %v", expected, fields)
+	}
+}
@@ -0,0 +1,120 @@
+package rolling
+
+import (
+	"math"
+	"sort"
+)
+
+// Decay computes the weight of a bucket from its age. The age is measured in
+// buckets where zero is the bucket currently receiving data and buckets - 1
+// is the oldest bucket in the window.
+type Decay func(age int, buckets int) float64
+
+// LinearDecay weights the current bucket at one and each older bucket
+// proportionally less such that the oldest bucket is weighted at 1/buckets.
+func LinearDecay() Decay {
+	return func(age int, buckets int) float64 {
+		return float64(buckets-age) / float64(buckets)
+	}
+}
+
+// ExponentialDecay weights the current bucket at one and each older bucket
+// by the given factor, between 0 and 1, times the weight of the bucket that
+// came after it.
+func ExponentialDecay(factor float64) Decay {
+	return func(age int, buckets int) float64 {
+		return math.Pow(factor, float64(age))
+	}
+}
+
+// ReduceWeighted reduces the window to a single value using a reduction
+// function that is also given the weight of each bucket as computed by the
+// given Decay. The weights are in the same order as the buckets of the
+// window. This allows recent data to have more influence than older data so
+// that the aggregate changes gradually as a bucket ages out rather than all
+// at once when it expires.
+func (w *TimePolicy) ReduceWeighted(decay Decay, f func(Window, []float64) float64) float64 {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	var adjustedTime, windowOffset = w.selectBucket(w.clock.Now())
+	w.keepConsistent(adjustedTime)
+	var weights = make([]float64, w.numberOfBuckets)
+	for offset := range weights {
+		var age = windowOffset - offset
+		if age < 0 {
+			age = age + w.numberOfBuckets
+		}
+		weights[offset] = decay(age, w.numberOfBuckets)
+	}
+	return f(w.window, weights)
+}
+
+// WeightedCount returns the sum of the weights of every value in the window.
+func WeightedCount(w Window, weights []float64) float64 {
+	var result = 0.0
+	for offset, bucket := range w {
+		result = result + float64(len(bucket))*weights[offset]
+	}
+	return result
+}
+
+// WeightedSum returns the sum of every value in the window multiplied by the
+// weight of its bucket.
+func WeightedSum(w Window, weights []float64) float64 {
+	var result = 0.0
+	for offset, bucket := range w {
+		for _, p := range bucket {
+			result = result + p*weights[offset]
+		}
+	}
+	return result
+}
+
+// WeightedAvg returns the weighted average of the values in the window. An
+// empty window, or one where every weight is zero, has an average of zero.
+func WeightedAvg(w Window, weights []float64) float64 {
+	var count = WeightedCount(w, weights)
+	if count == 0 {
+		return 0
+	}
+	return WeightedSum(w, weights) / count
+}
+
+type weightedValue struct {
+	value  float64
+	weight float64
+}
+
+// WeightedPercentile returns a weighted aggregating function that computes
+// the given percentile of the values in the window where each value counts in
+// proportion to the weight of its bucket. The result is the smallest value at
+// which the cumulative weight reaches the percentile of the total weight. An
+// empty window, or one where every weight is zero, has a percentile of zero.
+func WeightedPercentile(perc float64) func(w Window, weights []float64) float64 {
+	return func(w Window, weights []float64) float64 {
+		var values = make([]weightedValue, 0, len(w))
+		var total = 0.0
+		for offset, bucket := range w {
+			for _, p := range bucket {
+				values = append(values, weightedValue{value: p, weight: weights[offset]})
+				total = total + weights[offset]
+			}
+		}
+		if total <= 0 {
+			return 0
+		}
+		sort.Slice(values, func(i int, j int) bool {
+			return values[i].value < values[j].value
+		})
+		var target = total * perc / 100
+		var cumulative = 0.0
+		for _, v := range values {
+			cumulative = cumulative + v.weight
+			if v.weight > 0 && cumulative >= target {
+				return v.value
+			}
+		}
+		return values[len(values)-1].value
+	}
+}
@@ -0,0 +1,68 @@
+package rolling
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLinearDecay(t *testing.T) {
+	var d = LinearDecay()
+	if !floatEquals(d(0, 4), 1) || !floatEquals(d(1, 4), .75) || !floatEquals(d(3, 4), .25) {
+		t.Fatalf("linear decay calculated incorrectly: %f %f %f", d(0, 4), d(1, 4), d(3, 4))
+	}
+}
+
+func TestExponentialDecay(t *testing.T) {
+	var d = ExponentialDecay(.5)
+	if !floatEquals(d(0, 4), 1) || !floatEquals(d(1, 4), .5) || !floatEquals(d(3, 4), .125) {
+		t.Fatalf("exponential decay calculated incorrectly: %f %f %f", d(0, 4), d(1, 4), d(3, 4))
+	}
+}
+
+func TestTimeWindowReduceWeighted(t *testing.T) {
+	var c = &testClock{now: time.Unix(0, 0)}
+	var p = NewTimePolicy(NewWindow(4), time.Second, WithClock(c))
+	p.Append(8)
+	c.now = c.now.Add(time.Second)
+	p.Append(4)
+	c.now = c.now.Add(2 * time.Second)
+	p.Append(2)
+	p.Append(2)
+
+	var sum = p.ReduceWeighted(ExponentialDecay(.5), WeightedSum)
+	var expected = 8*.125 + 4*.25 + 2 + 2
+	if !floatEquals(sum, expected) {
+		t.Fatalf("weighted sum calculated incorrectly: %f versus %f", expected, sum)
+	}
+	var count = p.ReduceWeighted(ExponentialDecay(.5), WeightedCount)
+	expected = .125 + .25 + 2
+	if !floatEquals(count, expected) {
+		t.Fatalf("weighted count calculated incorrectly: %f versus %f", expected, count)
+	}
+	var avg = p.ReduceWeighted(ExponentialDecay(.5), WeightedAvg)
+	expected = sum / count
+	if !floatEquals(avg, expected) {
+		t.Fatalf("weighted avg calculated incorrectly: %f versus %f", expected, avg)
+	}
+}
+
+func TestWeightedAvgWhenEmpty(t *testing.T) {
+	var w = NewWindow(2)
+	if result := WeightedAvg(w, []float64{1, 1}); !floatEquals(result, 0) {
+		t.Fatalf("empty weighted avg should be zero but got %f", result)
+	}
+}
+
+func TestWeightedPercentile(t *testing.T) {
+	var w = Window{{1}, {2}, {3}, {4}}
+	var weights = []float64{1, 1, 1, 7}
+	if result := WeightedPercentile(50)(w, weights); !floatEquals(result, 4) {
+		t.Fatalf("weighted percentile calculated incorrectly: %f versus %f", 4.0, result)
+	}
+	if result := WeightedPercentile(20)(w, weights); !floatEquals(result, 2) {
+		t.Fatalf("weighted percentile calculated incorrectly: %f versus %f", 2.0, result)
+	}
+	if result := WeightedPercentile(50)(w, []float64{0, 0, 0, 0}); result != 0 {
+		t.Fatalf("expected zero when every weight is zero but got %f", result)
+	}
+}
@@ -0,0 +1,132 @@
+package rolling
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Detector counts events for each of many keys, such as the source address
+// of a login attempt, over a rolling time window and reports the keys whose
+// count exceeds a threshold. This is the common pattern behind brute force
+// and scraper detection. Keys that have no events within the window are
+// forgotten so that memory is only used by active keys. Each key is counted
+// with a CounterPolicy so its memory does not grow with the number of events,
+// and the keys are spread across a number of separately locked stripes so
+// that many goroutines may observe different keys concurrently.
+type Detector struct {
+	stripes    []*detectorStripe
+	threshold  float64
+	onExceeded func(key string, count float64)
+}
+
+// detectorStripe holds the share of the keys of a Detector that is guarded by
+// a single lock.
+type detectorStripe struct {
+	keys    *keyed
+	flagged map[string]bool
+	lock    *sync.Mutex
+}
+
+// NewDetector generates a Detector that counts the events of each key over a
+// time window made of the given number of buckets of the given duration. The
+// onExceeded function, if not nil, is called each time the count of a key
+// rises above the threshold. It is called again for the same key only after
+// the count has fallen back to the threshold or below. The function is called
+// while part of the Detector is locked and must not call the Detector.
+func NewDetector(buckets int, bucketDuration time.Duration, threshold float64, onExceeded func(key string, count float64), options ...TimePolicyOption) *Detector {
+	var d = &Detector{
+		stripes:    make([]*detectorStripe, keyStripes),
+		threshold:  threshold,
+		onExceeded: onExceeded,
+	}
+	for offset := range d.stripes {
+		var stripe = &detectorStripe{
+			keys:    newKeyedCounters(buckets, bucketDuration, options),
+			flagged: make(map[string]bool),
+			lock:    &sync.Mutex{},
+		}
+		stripe.keys.onExpire = func(key string) {
+			delete(stripe.flagged, key)
+		}
+		d.stripes[offset] = stripe
+	}
+	return d
+}
+
+func (d *Detector) stripe(key string) *detectorStripe {
+	return d.stripes[stripeOf(key, len(d.stripes))]
+}
+
+// Observe records an event for the given key and returns the number of
+// events for the key within the window and whether that number exceeds the
+// threshold.
+func (d *Detector) Observe(key string) (float64, bool) {
+	var stripe = d.stripe(key)
+	stripe.lock.Lock()
+	defer stripe.lock.Unlock()
+
+	var w = stripe.keys.counter(key)
+	w.Add(1)
+	var count = w.Total()
+	var exceeded = count > d.threshold
+	if exceeded && !stripe.flagged[key] && d.onExceeded != nil {
+		d.onExceeded(key, count)
+	}
+	stripe.flagged[key] = exceeded
+	return count, exceeded
+}
+
+// Count returns the number of events for the given key within the window.
+func (d *Detector) Count(key string) float64 {
+	var stripe = d.stripe(key)
+	stripe.lock.Lock()
+	defer stripe.lock.Unlock()
+
+	var v, ok = stripe.keys.values[key]
+	if !ok {
+		return 0
+	}
+	return v.(*CounterPolicy).Total()
+}
+
+// Exceeding returns every key whose number of events within the window
+// exceeds the threshold, in sorted order.
+func (d *Detector) Exceeding() []string {
+	var result = make([]string, 0)
+	for _, stripe := range d.stripes {
+		stripe.lock.Lock()
+		stripe.keys.expireEvery()
+		for key, v := range stripe.keys.values {
+			if v.(*CounterPolicy).Total() > d.threshold {
+				result = append(result, key)
+			}
+		}
+		stripe.lock.Unlock()
+	}
+	sort.Strings(result)
+	return result
+}
+
+// Len returns the number of keys being tracked. Keys without events in the
+// window are forgotten periodically by Observe, Exceeding, and Len or
+// immediately by Expire.
+func (d *Detector) Len() int {
+	var result = 0
+	for _, stripe := range d.stripes {
+		stripe.lock.Lock()
+		stripe.keys.expireEvery()
+		result = result + len(stripe.keys.values)
+		stripe.lock.Unlock()
+	}
+	return result
+}
+
+// Expire forgets every key that has no events within the window.
+func (d *Detector) Expire() {
+	for _, stripe := range d.stripes {
+		stripe.lock.Lock()
+		stripe.keys.expire()
+		stripe.lock.Unlock()
+	}
+}
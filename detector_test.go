@@ -0,0 +1,97 @@
+package rolling
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestDetector(t *testing.T) {
+	var c = &testClock{now: time.Unix(0, 0)}
+	var alerts = make(map[string]float64)
+	var d = NewDetector(10, time.Second, 3, func(key string, count float64) {
+		alerts[key] = count
+	}, WithClock(c))
+
+	for x := 0; x < 5; x = x + 1 {
+		d.Observe("10.0.0.1")
+	}
+	var count, exceeded = d.Observe("10.0.0.2")
+	if count != 1 || exceeded {
+		t.Fatalf("expected a single event below the threshold but got %f, %t", count, exceeded)
+	}
+	if len(alerts) != 1 || alerts["10.0.0.1"] != 4 {
+		t.Fatalf("expected one alert when the count rose above the threshold but got %v", alerts)
+	}
+	if keys := d.Exceeding(); len(keys) != 1 || keys[0] != "10.0.0.1" {
+		t.Fatalf("unexpected keys exceeding the threshold %v", keys)
+	}
+	if result := d.Count("10.0.0.1"); result != 5 {
+		t.Fatalf("counted incorrectly: %f versus %f", 5.0, result)
+	}
+	if result := d.Count("unknown"); result != 0 {
+		t.Fatalf("expected no events for an unknown key but got %f", result)
+	}
+
+	// The events expire and the key may alert again.
+	c.now = c.now.Add(20 * time.Second)
+	if keys := d.Exceeding(); len(keys) != 0 {
+		t.Fatalf("expected no keys exceeding the threshold but got %v", keys)
+	}
+	for x := 0; x < 4; x = x + 1 {
+		d.Observe("10.0.0.1")
+	}
+	if d.Len() != 1 {
+		t.Fatalf("expected the idle key to be forgotten but tracking %d keys", d.Len())
+	}
+	delete(alerts, "10.0.0.1")
+	for x := 0; x < 4; x = x + 1 {
+		d.Observe("10.0.0.3")
+	}
+	if _, ok := alerts["10.0.0.3"]; !ok {
+		t.Fatalf("expected a new alert but got %v", alerts)
+	}
+	c.now = c.now.Add(20 * time.Second)
+	d.Expire()
+	if d.Len() != 0 {
+		t.Fatalf("expected every key to be forgotten but tracking %d keys", d.Len())
+	}
+}
+
+func TestDetectorConcurrentKeys(t *testing.T) {
+	var c = &testClock{now: time.Unix(0, 0)}
+	var lock = &sync.Mutex{}
+	var alerts = make(map[string]float64)
+	var d = NewDetector(10, time.Second, 50, func(key string, count float64) {
+		lock.Lock()
+		defer lock.Unlock()
+		alerts[key] = count
+	}, WithClock(c))
+
+	var keys = 100
+	var wg = &sync.WaitGroup{}
+	for worker := 0; worker < 4; worker = worker + 1 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for x := 0; x < 25; x = x + 1 {
+				for key := 0; key < keys; key = key + 1 {
+					d.Observe(strconv.Itoa(key))
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if d.Len() != keys {
+		t.Fatalf("expected %d keys but tracking %d", keys, d.Len())
+	}
+	for key := 0; key < keys; key = key + 1 {
+		if result := d.Count(strconv.Itoa(key)); result != 100 {
+			t.Fatalf("counted incorrectly: %f versus %f", 100.0, result)
+		}
+	}
+	if exceeding := d.Exceeding(); len(exceeding) != keys || len(alerts) != keys {
+		t.Fatalf("expected every key to exceed the threshold once but got %d keys and %d alerts", len(exceeding), len(alerts))
+	}
+}
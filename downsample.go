@@ -0,0 +1,36 @@
+package rolling
+
+import "time"
+
+// Downsample reduces the window to a coarser series of, at most, the given
+// number of points, ordered from the oldest to the newest. Consecutive buckets
+// are grouped together and each group is reduced with the reduction function
+// as a Window of only its buckets. For example, 600 one second buckets
+// downsampled to 10 points with Avg produces the average of each minute. When
+// the buckets do not divide evenly the groups differ in size by at most one
+// bucket. The time of each point is the start of the first bucket of its
+// group. A non-positive number of points results in an empty series.
+func (w *TimePolicy) Downsample(points int, f func(Window) float64) []SeriesPoint {
+	if points < 1 {
+		return nil
+	}
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if points > w.numberOfBuckets {
+		points = w.numberOfBuckets
+	}
+	var starts = make([]time.Time, 0, w.numberOfBuckets)
+	var ordered = make(Window, 0, w.numberOfBuckets)
+	w.eachBucket(w.clock.Now(), func(start time.Time, window Window) {
+		starts = append(starts, start)
+		ordered = append(ordered, window[0])
+	})
+	var result = make([]SeriesPoint, 0, points)
+	for group := 0; group < points; group = group + 1 {
+		var first = group * w.numberOfBuckets / points
+		var last = (group + 1) * w.numberOfBuckets / points
+		result = append(result, SeriesPoint{Time: starts[first], Value: f(ordered[first:last])})
+	}
+	return result
+}
@@ -0,0 +1,35 @@
+package rolling
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimePolicyDownsample(t *testing.T) {
+	var c = &testClock{now: time.Unix(0, 0)}
+	var p = NewTimePolicy(NewWindow(6), time.Second, WithClock(c))
+	for x := 1; x <= 6; x = x + 1 {
+		p.AppendBatch([]float64{float64(x), float64(x)})
+		c.now = c.now.Add(time.Second)
+	}
+	c.now = c.now.Add(-time.Second)
+
+	var series = p.Downsample(3, Sum)
+	if len(series) != 3 || series[0].Value != 6 || series[1].Value != 14 || series[2].Value != 22 {
+		t.Fatalf("expected the sum of each pair of buckets but got %v", series)
+	}
+	if !series[0].Time.Equal(time.Unix(0, 0)) || !series[2].Time.Equal(time.Unix(4, 0)) {
+		t.Fatalf("expected each point to start with its first bucket but got %v", series)
+	}
+
+	series = p.Downsample(4, Count)
+	if len(series) != 4 || series[0].Value != 2 || series[1].Value != 4 || series[2].Value != 2 || series[3].Value != 4 {
+		t.Fatalf("expected uneven groups to differ by one bucket but got %v", series)
+	}
+	if series = p.Downsample(100, Avg); len(series) != 6 || series[5].Value != 6 {
+		t.Fatalf("expected at most one point per bucket but got %v", series)
+	}
+	if series = p.Downsample(0, Avg); len(series) != 0 {
+		t.Fatalf("expected no points but got %v", series)
+	}
+}
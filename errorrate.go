@@ -0,0 +1,41 @@
+package rolling
+
+import "math"
+
+// ErrorRate computes the ratio of failures to total attempts from a pair of
+// windows. Each window is summed so values may be appended as a one for each
+// event or as a count of events. The windows should share the same duration
+// and bucket size so that both cover the same period of time.
+type ErrorRate struct {
+	errors  Reducer
+	total   Reducer
+	minimum float64
+}
+
+// NewErrorRate generates an ErrorRate from a window of failures and a window
+// of all attempts. The rate is not reported until the total window contains at
+// least the given minimum volume so that a handful of failures during a quiet
+// period does not appear as a high error rate.
+func NewErrorRate(errors Reducer, total Reducer, minimum float64) *ErrorRate {
+	return &ErrorRate{errors: errors, total: total, minimum: minimum}
+}
+
+// Rate returns the fraction of attempts that failed and whether the total
+// volume met the minimum. Both windows are summed while the locks of both are
+// held, as with Combine, so either may be a Combine or any other Reducer.
+// Time windows each rotate to their own reading of the clock, so a bucket of
+// attempts may expire a moment before the matching bucket of failures. The
+// rate is therefore clamped to the range of zero to one. A rate of zero is
+// returned when the volume is below the minimum.
+func (r *ErrorRate) Rate() (float64, bool) {
+	var errors, total float64
+	reduceTogether([]Reducer{r.errors, r.total}, func(windows []Window) float64 {
+		errors = Sum(windows[0])
+		total = Sum(windows[1])
+		return 0
+	})
+	if total <= 0 || total < r.minimum {
+		return 0, false
+	}
+	return math.Max(0, math.Min(1, errors/total)), true
+}
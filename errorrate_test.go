@@ -0,0 +1,70 @@
+package rolling
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestErrorRate(t *testing.T) {
+	var errors = NewPointPolicy(NewWindow(10))
+	var total = NewPointPolicy(NewWindow(10))
+	var rate = NewErrorRate(errors, total, 10)
+
+	for x := 0; x < 5; x = x + 1 {
+		total.Append(1)
+	}
+	errors.Append(1)
+	if result, ok := rate.Rate(); ok || result != 0 {
+		t.Fatalf("expected no rate below the minimum volume but got %f", result)
+	}
+
+	for x := 0; x < 5; x = x + 1 {
+		total.Append(1)
+	}
+	errors.Append(1)
+	var result, ok = rate.Rate()
+	if !ok {
+		t.Fatal("expected a rate at the minimum volume")
+	}
+	if !floatEquals(result, 0.2) {
+		t.Fatalf("error rate calculated incorrectly: %f versus %f", 0.2, result)
+	}
+}
+
+func TestErrorRateEmpty(t *testing.T) {
+	var rate = NewErrorRate(NewPointPolicy(NewWindow(1)), NewPointPolicy(NewWindow(1)), 0)
+	if result, ok := rate.Rate(); ok || result != 0 {
+		t.Fatalf("expected no rate for an empty window but got %f", result)
+	}
+}
+
+func TestErrorRateConcurrentRotation(t *testing.T) {
+	var errors = NewTimePolicy(NewWindow(3), time.Millisecond)
+	var total = NewTimePolicy(NewWindow(3), time.Millisecond)
+	var rate = NewErrorRate(errors, total, 1)
+	var done = make(chan struct{})
+	var wg = &sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-done:
+				return
+			default:
+				total.Append(1)
+				errors.Append(1)
+			}
+		}
+	}()
+	var deadline = time.Now().Add(50 * time.Millisecond)
+	for time.Now().Before(deadline) {
+		if result, ok := rate.Rate(); ok && (result < 0 || result > 1) {
+			t.Errorf("expected a rate between zero and one but got %f", result)
+			break
+		}
+	}
+	close(done)
+	wg.Wait()
+}
@@ -0,0 +1,64 @@
+package rolling
+
+import "math"
+
+// confidenceZ is the standard score of a two sided 95% confidence interval.
+const confidenceZ = 1.959964
+
+// Estimate is a percentile along with the information needed to judge how
+// far it may be trusted.
+type Estimate struct {
+	// Value is the estimated percentile.
+	Value float64
+	// Percentile is the requested percentile between 0 and 100.
+	Percentile float64
+	// Samples is the number of values the estimate was computed from.
+	Samples int
+	// Epsilon is the half width of a 95% confidence interval on the rank of
+	// the estimate, in percentiles, that arises from the number of samples
+	// alone. A p99.9 from 200 samples has an epsilon of about 0.44 which
+	// means it may represent anything from p99.46 to p100.
+	Epsilon float64
+	// Exact is true when the value was computed from the sorted samples
+	// rather than estimated. Estimation adds error beyond Epsilon.
+	Exact bool
+}
+
+// Bounds returns the range of percentiles, clamped to 0 and 100, that the
+// estimate may represent.
+func (e Estimate) Bounds() (float64, float64) {
+	return math.Max(0, e.Percentile-e.Epsilon), math.Min(100, e.Percentile+e.Epsilon)
+}
+
+// percentileEpsilon computes the Epsilon of an Estimate of the given
+// percentile from the given number of samples using the normal approximation
+// to the distribution of a sample quantile.
+func percentileEpsilon(perc float64, samples int) float64 {
+	if samples < 1 {
+		return 100
+	}
+	var p = perc / 100
+	return 100 * confidenceZ * math.Sqrt(p*(1-p)/float64(samples))
+}
+
+// FastPercentileEstimate returns an aggregating function that computes the
+// same value as FastPercentile along with the number of samples it was
+// computed from and its error bound. An empty window results in an Estimate
+// with no samples and the widest possible bound.
+func FastPercentileEstimate(perc float64, options ...FastPercentileOption) func(w Window) Estimate {
+	var config = &fastPercentile{exactBelow: defaultExactBelow}
+	for _, option := range options {
+		option(config)
+	}
+	var f = FastPercentile(perc, options...)
+	return func(w Window) Estimate {
+		var count = int(Count(w))
+		return Estimate{
+			Value:      f(w),
+			Percentile: perc,
+			Samples:    count,
+			Epsilon:    percentileEpsilon(perc, count),
+			Exact:      count > 0 && count < config.exactBelow,
+		}
+	}
+}
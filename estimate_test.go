@@ -0,0 +1,40 @@
+package rolling
+
+import "testing"
+
+func TestFastPercentileEstimate(t *testing.T) {
+	var p = NewPointPolicy(NewWindow(200))
+	for x := 1; x <= 200; x = x + 1 {
+		p.Append(float64(x))
+	}
+	var e Estimate
+	p.Reduce(func(w Window) float64 {
+		e = FastPercentileEstimate(99.9)(w)
+		if e.Value != FastPercentile(99.9)(w) {
+			t.Fatalf("expected the value of FastPercentile but got %f", e.Value)
+		}
+		return 0
+	})
+	if e.Samples != 200 || e.Exact {
+		t.Fatalf("expected an estimate from 200 samples but got %+v", e)
+	}
+	if !floatMostlyEquals(e.Epsilon, 0.438) {
+		t.Fatalf("expected an epsilon of about 0.44 but got %f", e.Epsilon)
+	}
+	var lower, upper = e.Bounds()
+	if !floatMostlyEquals(lower, 99.462) || upper != 100 {
+		t.Fatalf("unexpected bounds %f, %f", lower, upper)
+	}
+
+	var small = FastPercentileEstimate(50)(Window{{1, 2, 3}})
+	if !small.Exact || small.Value != 2 || small.Samples != 3 {
+		t.Fatalf("expected an exact median of a small window but got %+v", small)
+	}
+	var empty = FastPercentileEstimate(50)(Window{})
+	if empty.Exact || empty.Samples != 0 || empty.Epsilon != 100 {
+		t.Fatalf("expected the widest bound for an empty window but got %+v", empty)
+	}
+	if lower, upper = empty.Bounds(); lower != 0 || upper != 100 {
+		t.Fatalf("unexpected bounds %f, %f", lower, upper)
+	}
+}
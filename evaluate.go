@@ -0,0 +1,98 @@
+package rolling
+
+import (
+	"reflect"
+	"sync"
+)
+
+// metricGroup is a set of metrics that share the same Policy along with the
+// names under which their results are reported.
+type metricGroup struct {
+	policy  Policy
+	names   []string
+	metrics []*Metric
+}
+
+// policyIdentity identifies a Policy that is a pointer by its type and
+// address so that policies are grouped without comparing their values, which
+// panics for types that cannot be compared.
+type policyIdentity struct {
+	kind    reflect.Type
+	address uintptr
+}
+
+// groupMetrics groups the given metrics by the Policy they reduce. Policies
+// that are not pointers cannot be identified and are given a group of their
+// own.
+func groupMetrics(metrics map[string]*Metric) []*metricGroup {
+	var groups = make([]*metricGroup, 0, len(metrics))
+	var index = make(map[policyIdentity]*metricGroup)
+	for name, m := range metrics {
+		var value = reflect.ValueOf(m.Policy)
+		var group *metricGroup
+		if value.Kind() == reflect.Ptr {
+			var id = policyIdentity{kind: value.Type(), address: value.Pointer()}
+			group = index[id]
+			if group == nil {
+				group = &metricGroup{policy: m.Policy}
+				index[id] = group
+				groups = append(groups, group)
+			}
+		} else {
+			group = &metricGroup{policy: m.Policy}
+			groups = append(groups, group)
+		}
+		group.names = append(group.names, name)
+		group.metrics = append(group.metrics, m)
+	}
+	return groups
+}
+
+// EvaluateAll computes the Value of every given Metric using, at most, the
+// given number of goroutines and returns the results keyed the same way as
+// the given map. Metrics that share the same Policy are evaluated one after
+// another by the same goroutine, while holding the lock of the Policy only
+// once, so that workers do not contend with each other for the same window.
+// A number of workers less than one evaluates every Metric on the calling
+// goroutine.
+func EvaluateAll(metrics map[string]*Metric, workers int) map[string]float64 {
+	var groups = groupMetrics(metrics)
+	var results = make(map[string]float64, len(metrics))
+	var lock = &sync.Mutex{}
+	var evaluate = func(group *metricGroup) {
+		var aggregates = make([]func(Window) float64, len(group.metrics))
+		for offset, m := range group.metrics {
+			aggregates[offset] = m.Aggregate
+		}
+		var values = ReduceAll(group.policy, aggregates...)
+		lock.Lock()
+		defer lock.Unlock()
+		for offset, name := range group.names {
+			results[name] = values[offset]
+		}
+	}
+
+	if workers < 1 {
+		for _, group := range groups {
+			evaluate(group)
+		}
+		return results
+	}
+	var jobs = make(chan *metricGroup)
+	var wg = &sync.WaitGroup{}
+	for x := 0; x < workers && x < len(groups); x = x + 1 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for group := range jobs {
+				evaluate(group)
+			}
+		}()
+	}
+	for _, group := range groups {
+		jobs <- group
+	}
+	close(jobs)
+	wg.Wait()
+	return results
+}
@@ -0,0 +1,64 @@
+package rolling
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestEvaluateAll(t *testing.T) {
+	var metrics = make(map[string]*Metric)
+	for x := 0; x < 50; x = x + 1 {
+		var p = NewPointPolicy(NewWindow(10))
+		p.Append(float64(x))
+		metrics[fmt.Sprintf("sum%d", x)] = &Metric{Name: fmt.Sprintf("sum%d", x), Policy: p, Aggregate: Sum}
+		metrics[fmt.Sprintf("max%d", x)] = &Metric{Name: fmt.Sprintf("max%d", x), Policy: p, Aggregate: Max}
+	}
+	for _, workers := range []int{0, 1, 4, 1000} {
+		var results = EvaluateAll(metrics, workers)
+		if len(results) != len(metrics) {
+			t.Fatalf("expected %d results but got %d", len(metrics), len(results))
+		}
+		for x := 0; x < 50; x = x + 1 {
+			if result := results[fmt.Sprintf("sum%d", x)]; !floatEquals(result, float64(x)) {
+				t.Fatalf("sum%d calculated incorrectly with %d workers: %f versus %f", x, workers, float64(x), result)
+			}
+			if result := results[fmt.Sprintf("max%d", x)]; !floatEquals(result, float64(x)) {
+				t.Fatalf("max%d calculated incorrectly with %d workers: %f versus %f", x, workers, float64(x), result)
+			}
+		}
+	}
+	if results := EvaluateAll(nil, 4); len(results) != 0 {
+		t.Fatalf("expected no results but got %v", results)
+	}
+}
+
+// valuePolicy is a Policy that is not a pointer and cannot be compared.
+type valuePolicy struct {
+	values []float64
+}
+
+func (p valuePolicy) Append(float64) {}
+
+func (p valuePolicy) Reduce(f func(Window) float64) float64 {
+	return f(Window{p.values})
+}
+
+func TestEvaluateAllKeys(t *testing.T) {
+	var p = NewPointPolicy(NewWindow(2))
+	p.Append(1)
+	p.Append(2)
+	var metrics = map[string]*Metric{
+		"sum":     From(p).Sum().Build(),
+		"max":     From(p).Max().Build(),
+		"renamed": From(p).Min().Named("sum").Build(),
+		"value":   From(valuePolicy{values: []float64{5, 6}}).Sum().Build(),
+		"other":   From(valuePolicy{values: []float64{7}}).Sum().Build(),
+	}
+	var results = EvaluateAll(metrics, 0)
+	if len(results) != 5 || results["sum"] != 3 || results["max"] != 2 || results["renamed"] != 1 {
+		t.Fatalf("expected results keyed by the map but got %v", results)
+	}
+	if results["value"] != 11 || results["other"] != 7 {
+		t.Fatalf("expected policies that cannot be compared to be evaluated but got %v", results)
+	}
+}
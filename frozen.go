@@ -0,0 +1,26 @@
+package rolling
+
+// Frozen is an immutable copy of the contents of a window at a point in time.
+// Every reduction of a Frozen window operates on exactly the same data which
+// allows several aggregates to be computed consistently with each other.
+type Frozen struct {
+	window Window
+}
+
+// Freeze copies the current contents of a window. The copy is made while the
+// lock of the window is held and does not change as new values are appended
+// to the original.
+func Freeze(r Reducer) *Frozen {
+	var frozen = &Frozen{}
+	r.Reduce(func(w Window) float64 {
+		frozen.window = w.Clone()
+		return 0
+	})
+	return frozen
+}
+
+// Reduce the frozen window to a single value using a reduction function. The
+// reduction function must not modify the window it is given.
+func (w *Frozen) Reduce(f func(Window) float64) float64 {
+	return f(w.window)
+}
@@ -0,0 +1,21 @@
+package rolling
+
+import "testing"
+
+func TestFreeze(t *testing.T) {
+	var p = NewPointPolicy(NewWindow(3))
+	p.Append(1)
+	p.Append(2)
+	var frozen = Freeze(p)
+	p.Append(3)
+	p.Append(4)
+	if result := frozen.Reduce(Sum); !floatEquals(result, 3) {
+		t.Fatalf("frozen sum calculated incorrectly: %f versus %f", 3.0, result)
+	}
+	if result := frozen.Reduce(Count); !floatEquals(result, 3) {
+		t.Fatalf("frozen count calculated incorrectly: %f versus %f", 3.0, result)
+	}
+	if result := p.Reduce(Sum); !floatEquals(result, 9) {
+		t.Fatalf("sum calculated incorrectly: %f versus %f", 9.0, result)
+	}
+}
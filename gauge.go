@@ -0,0 +1,127 @@
+package rolling
+
+import (
+	"sync"
+	"time"
+)
+
+// GaugePolicy is a rolling window policy for values that are sampled at
+// irregular intervals, such as the depth of a queue. It tracks the last N
+// values, along with the time each was observed, for as long as they are
+// within a duration. Each value is considered current from the time it was
+// observed until the next value replaced it which allows aggregates to be
+// weighted by how long each value was in effect rather than by how often it
+// happened to be sampled.
+type GaugePolicy struct {
+	windowSize int
+	window     Window
+	times      []time.Time
+	offset     int
+	count      int
+	duration   time.Duration
+	ordered    Window
+	weights    []float64
+	clock      Clock
+	lock       *sync.Mutex
+}
+
+// NewGaugePolicy generates a Policy that operates on, at most, a number of
+// samples equal to the size of the given window and that covers the given
+// duration of time. Each bucket will contain, at most, one data point.
+func NewGaugePolicy(window Window, duration time.Duration, options ...TimePolicyOption) *GaugePolicy {
+	var o = newTimeOptions(options)
+	for offset := range window {
+		window[offset] = make([]float64, 1)
+	}
+	return &GaugePolicy{
+		windowSize: len(window),
+		window:     window,
+		times:      make([]time.Time, len(window)),
+		duration:   duration,
+		ordered:    make(Window, 0, len(window)),
+		weights:    make([]float64, 0, len(window)),
+		clock:      o.clock,
+		lock:       &sync.Mutex{},
+	}
+}
+
+// Append a value to the window as observed at the current time.
+func (w *GaugePolicy) Append(value float64) {
+	w.AppendWithTimestamp(value, w.clock.Now())
+}
+
+// AppendWithTimestamp appends a value to the window as observed at the given
+// time. Values must be appended in the order they were observed. A value that
+// is older than the one before it is treated as having been current for no
+// time at all.
+func (w *GaugePolicy) AppendWithTimestamp(value float64, timestamp time.Time) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	w.window[w.offset][0] = value
+	w.times[w.offset] = timestamp
+	w.offset = (w.offset + 1) % w.windowSize
+	if w.count < w.windowSize {
+		w.count = w.count + 1
+	}
+}
+
+func (w *GaugePolicy) index(x int) int {
+	return (w.offset - w.count + x + w.windowSize) % w.windowSize
+}
+
+// Reduce the window to a single value using a reduction function. The
+// reduction function is given each value observed within the duration, from
+// the oldest to the newest, with one value in each bucket.
+func (w *GaugePolicy) Reduce(f func(Window) float64) float64 {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	var cutoff = w.clock.Now().Add(-w.duration)
+	w.ordered = w.ordered[:0]
+	for x := 0; x < w.count; x = x + 1 {
+		var offset = w.index(x)
+		if !w.times[offset].Before(cutoff) {
+			w.ordered = append(w.ordered, w.window[offset])
+		}
+	}
+	return f(w.ordered)
+}
+
+// ReduceTimeWeighted reduces the window to a single value using a reduction
+// function that is also given the number of seconds, within the duration,
+// that each value was current. The newest value is current until now. The
+// value that was current at the start of the duration is included, weighted
+// by the time remaining until it was replaced, even though it was observed
+// before the duration began. Functions such as WeightedAvg and
+// WeightedPercentile may be used as the reduction function.
+func (w *GaugePolicy) ReduceTimeWeighted(f func(Window, []float64) float64) float64 {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	var now = w.clock.Now()
+	var cutoff = now.Add(-w.duration)
+	w.ordered = w.ordered[:0]
+	w.weights = w.weights[:0]
+	for x := 0; x < w.count; x = x + 1 {
+		var offset = w.index(x)
+		var start = w.times[offset]
+		var end = now
+		if x+1 < w.count {
+			end = w.times[w.index(x+1)]
+		}
+		if !end.After(cutoff) {
+			continue
+		}
+		if start.Before(cutoff) {
+			start = cutoff
+		}
+		var weight = end.Sub(start).Seconds()
+		if weight < 0 {
+			weight = 0
+		}
+		w.ordered = append(w.ordered, w.window[offset])
+		w.weights = append(w.weights, weight)
+	}
+	return f(w.ordered, w.weights)
+}
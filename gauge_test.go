@@ -0,0 +1,45 @@
+package rolling
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGaugeWindowTimeWeighted(t *testing.T) {
+	var c = &testClock{now: time.Unix(0, 0)}
+	var p = NewGaugePolicy(NewWindow(10), 10*time.Second, WithClock(c))
+	p.Append(10)
+	p.AppendWithTimestamp(100, c.now.Add(9*time.Second))
+	p.AppendWithTimestamp(100, c.now.Add(9500*time.Millisecond))
+	c.now = c.now.Add(10 * time.Second)
+
+	if result := p.Reduce(Avg); !floatEquals(result, 70) {
+		t.Fatalf("average calculated incorrectly: %f versus %f", 70.0, result)
+	}
+	if result := p.ReduceTimeWeighted(WeightedAvg); !floatEquals(result, 19) {
+		t.Fatalf("time weighted average calculated incorrectly: %f versus %f", 19.0, result)
+	}
+	if result := p.ReduceTimeWeighted(WeightedPercentile(50)); !floatEquals(result, 10) {
+		t.Fatalf("time weighted percentile calculated incorrectly: %f versus %f", 10.0, result)
+	}
+
+	c.now = c.now.Add(5 * time.Second)
+	if result := p.Reduce(Count); !floatEquals(result, 2) {
+		t.Fatalf("expected the oldest value to expire but counted %f", result)
+	}
+	// The oldest value was still current for the first four seconds of the
+	// duration and continues to contribute to the weighted average.
+	if result := p.ReduceTimeWeighted(WeightedAvg); !floatEquals(result, 64) {
+		t.Fatalf("time weighted average calculated incorrectly: %f versus %f", 64.0, result)
+	}
+}
+
+func TestGaugeWindowEmpty(t *testing.T) {
+	var p = NewGaugePolicy(NewWindow(3), time.Second)
+	if result := p.ReduceTimeWeighted(WeightedAvg); result != 0 {
+		t.Fatalf("expected zero for an empty window but got %f", result)
+	}
+	if result := p.Reduce(Count); result != 0 {
+		t.Fatalf("expected an empty window but counted %f", result)
+	}
+}
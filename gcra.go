@@ -0,0 +1,113 @@
+package rolling
+
+import (
+	"sync"
+	"time"
+)
+
+// gcraState is the theoretical arrival time of the next event for a single
+// key. Events that arrive before it, by more than the burst allowance, are
+// not admitted.
+type gcraState struct {
+	tat time.Time
+}
+
+func (s *gcraState) idle(now time.Time) bool {
+	return !s.tat.After(now)
+}
+
+// GCRALimiter is a leaky bucket rate limiter, implemented with the generic
+// cell rate algorithm, that admits a limited number of events per key within
+// a period. Rather than admitting every event until a window is full, events
+// are spaced evenly across the period with a fixed allowance for bursts which
+// results in smoother admission than a RateLimiter. Only a single timestamp
+// is kept for each active key.
+type GCRALimiter struct {
+	keys     *keyed
+	emission time.Duration
+	burst    time.Duration
+	clock    Clock
+	lock     *sync.Mutex
+}
+
+// NewGCRALimiter generates a GCRALimiter that admits the given number of
+// events for each key per period. Up to burst events may be admitted at once
+// after a key has been idle, after which events are admitted at the steady
+// rate. A limit or burst of less than one is treated as one. Only the
+// WithClock option applies to a GCRALimiter.
+func NewGCRALimiter(limit int, period time.Duration, burst int, options ...TimePolicyOption) *GCRALimiter {
+	var o = newTimeOptions(options)
+	if burst < 1 {
+		burst = 1
+	}
+	var emission = period
+	if limit > 0 {
+		emission = period / time.Duration(limit)
+	}
+	return &GCRALimiter{
+		keys: newKeyed(func() keyedValue {
+			return &gcraState{}
+		}, period, o.clock),
+		emission: emission,
+		burst:    emission * time.Duration(burst),
+		clock:    o.clock,
+		lock:     &sync.Mutex{},
+	}
+}
+
+// wait returns the theoretical arrival time that follows an event admitted
+// now and the duration until such an event conforms. The lock must be held by
+// the caller.
+func (l *GCRALimiter) wait(s *gcraState, now time.Time) (time.Time, time.Duration) {
+	var tat = s.tat
+	if tat.Before(now) {
+		tat = now
+	}
+	tat = tat.Add(l.emission)
+	var wait = tat.Sub(now) - l.burst
+	if wait < 0 {
+		wait = 0
+	}
+	return tat, wait
+}
+
+// Allow records an event for the given key if it conforms to the rate and
+// reports whether the event was admitted. When the event is not admitted the
+// duration until an event would conform is also returned.
+func (l *GCRALimiter) Allow(key string) (bool, time.Duration) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	var s = l.keys.get(key).(*gcraState)
+	var now = l.clock.Now()
+	var tat, wait = l.wait(s, now)
+	if wait > 0 {
+		return false, wait
+	}
+	s.tat = tat
+	return true, 0
+}
+
+// Conformance returns the duration until an event for the given key would
+// conform to the rate without recording an event. A key that may be admitted
+// immediately returns zero.
+func (l *GCRALimiter) Conformance(key string) time.Duration {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	var v, ok = l.keys.values[key]
+	if !ok {
+		return 0
+	}
+	var _, wait = l.wait(v.(*gcraState), l.clock.Now())
+	return wait
+}
+
+// Len returns the number of keys being tracked. Keys that could admit a full
+// burst again are forgotten periodically by Allow.
+func (l *GCRALimiter) Len() int {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	return len(l.keys.values)
+}
@@ -0,0 +1,67 @@
+package rolling
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestGCRALimiter(t *testing.T) {
+	var c = &testClock{now: time.Unix(0, 0)}
+	var l = NewGCRALimiter(10, time.Second, 2, WithClock(c))
+	for x := 0; x < 2; x = x + 1 {
+		if ok, _ := l.Allow("a"); !ok {
+			t.Fatalf("expected event %d of the burst to be admitted", x)
+		}
+	}
+	var ok, wait = l.Allow("a")
+	if ok || wait != 100*time.Millisecond {
+		t.Fatalf("expected to wait one emission interval but got %t, %s", ok, wait)
+	}
+	if result := l.Conformance("a"); result != 100*time.Millisecond {
+		t.Fatalf("expected the same conformance time but got %s", result)
+	}
+	if result := l.Conformance("b"); result != 0 {
+		t.Fatalf("expected an unknown key to conform but got %s", result)
+	}
+	c.now = c.now.Add(wait)
+	if ok, _ = l.Allow("a"); !ok {
+		t.Fatal("expected an event to be admitted after the wait")
+	}
+	if ok, _ = l.Allow("a"); ok {
+		t.Fatal("expected events to be spaced at the steady rate")
+	}
+
+	// Idle keys are forgotten once a full period has passed.
+	c.now = c.now.Add(time.Second)
+	l.Allow("b")
+	if l.Len() != 1 {
+		t.Fatalf("expected the idle key to be forgotten but tracking %d keys", l.Len())
+	}
+}
+
+func TestGCRALimiterMiddleware(t *testing.T) {
+	var c = &testClock{now: time.Unix(0, 0)}
+	var l = NewGCRALimiter(1, time.Minute, 1, WithClock(c))
+	var handler = RateLimit(l, func(r *http.Request) string {
+		return r.RemoteAddr
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	var recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest("GET", "/", nil))
+	if recorder.Code != http.StatusNoContent {
+		t.Fatalf("expected the first request to be admitted but got %d", recorder.Code)
+	}
+	c.now = c.now.Add(30 * time.Second)
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest("GET", "/", nil))
+	if recorder.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected the second request to be rejected but got %d", recorder.Code)
+	}
+	if retry := recorder.Header().Get("Retry-After"); retry != "30" {
+		t.Fatalf("expected to retry after 30 seconds but got %q", retry)
+	}
+}
@@ -0,0 +1,139 @@
+package rolling
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// WriteGraphite evaluates every given Metric and writes the results to the
+// given writer using the Graphite plaintext protocol. Each line contains the
+// prefix joined to the key of the Metric in the map with a period, the value,
+// and the given time in seconds since the Unix epoch. Whitespace in names is
+// replaced by underscores. Metrics are written in order of their keys.
+func WriteGraphite(w io.Writer, prefix string, now time.Time, metrics map[string]*Metric) error {
+	var values = Evaluate(metrics)
+	if prefix != "" {
+		prefix = prefix + "."
+	}
+	var timestamp = strconv.FormatInt(now.Unix(), 10)
+
+	var b = bufio.NewWriter(w)
+	for _, v := range values {
+		_, _ = b.WriteString(graphiteName(prefix + v.Name))
+		_ = b.WriteByte(' ')
+		_, _ = b.WriteString(strconv.FormatFloat(v.Value, 'f', -1, 64))
+		_ = b.WriteByte(' ')
+		_, _ = b.WriteString(timestamp)
+		_ = b.WriteByte('\n')
+	}
+	return b.Flush()
+}
+
+func graphiteName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r == ' ' || r == '\t' || r == '\n' || r == '\r' {
+			return '_'
+		}
+		return r
+	}, name)
+}
+
+type graphiteOptions struct {
+	prefix   string
+	interval time.Duration
+	timeout  time.Duration
+	onError  func(error)
+	clock    Clock
+}
+
+// GraphiteOption is used to modify the behavior of a GraphiteEmitter.
+type GraphiteOption func(*graphiteOptions)
+
+// WithGraphitePrefix sets the prefix added to the name of every Metric. There
+// is no prefix by default.
+func WithGraphitePrefix(prefix string) GraphiteOption {
+	return func(o *graphiteOptions) {
+		o.prefix = prefix
+	}
+}
+
+// WithGraphiteInterval sets how often Start emits the metrics. The default is
+// one minute.
+func WithGraphiteInterval(interval time.Duration) GraphiteOption {
+	return func(o *graphiteOptions) {
+		o.interval = interval
+	}
+}
+
+// WithGraphiteTimeout sets the time allowed to connect to the endpoint and
+// write the metrics. The default is ten seconds.
+func WithGraphiteTimeout(timeout time.Duration) GraphiteOption {
+	return func(o *graphiteOptions) {
+		o.timeout = timeout
+	}
+}
+
+// WithGraphiteErrorHandler sets a function that is called with any error
+// encountered by Start. Errors are discarded by default.
+func WithGraphiteErrorHandler(onError func(error)) GraphiteOption {
+	return func(o *graphiteOptions) {
+		o.onError = onError
+	}
+}
+
+// WithGraphiteClock sets the source of the timestamps written with each
+// value. The system clock is used by default.
+func WithGraphiteClock(clock Clock) GraphiteOption {
+	return func(o *graphiteOptions) {
+		o.clock = clock
+	}
+}
+
+// GraphiteEmitter periodically evaluates a set of Metrics and sends them to a
+// Graphite, or Carbon, endpoint over TCP using the plaintext protocol.
+type GraphiteEmitter struct {
+	address string
+	metrics map[string]*Metric
+	options *graphiteOptions
+}
+
+// NewGraphiteEmitter generates a GraphiteEmitter that sends the given Metrics
+// to the endpoint at the given address, such as "carbon.example.com:2003".
+func NewGraphiteEmitter(address string, metrics map[string]*Metric, options ...GraphiteOption) *GraphiteEmitter {
+	var o = &graphiteOptions{
+		interval: time.Minute,
+		timeout:  10 * time.Second,
+		onError:  func(error) {},
+		clock:    systemClock{},
+	}
+	for _, option := range options {
+		option(o)
+	}
+	return &GraphiteEmitter{address: address, metrics: metrics, options: o}
+}
+
+// Emit evaluates the Metrics and sends them to the endpoint once. A new
+// connection is made for each call.
+func (e *GraphiteEmitter) Emit() error {
+	var conn, err = net.DialTimeout("tcp", e.address, e.options.timeout)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	_ = conn.SetWriteDeadline(time.Now().Add(e.options.timeout))
+	return WriteGraphite(conn, e.options.prefix, e.options.clock.Now(), e.metrics)
+}
+
+// Start calls Emit each time the interval elapses until the returned function
+// is called. Errors are given to the error handler.
+func (e *GraphiteEmitter) Start() func() {
+	return every(e.options.interval, func() {
+		if err := e.Emit(); err != nil {
+			e.options.onError(err)
+		}
+	})
+}
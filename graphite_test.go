@@ -0,0 +1,84 @@
+package rolling
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestWriteGraphite(t *testing.T) {
+	var p = NewPointPolicy(NewWindow(3))
+	p.Append(1.5)
+	p.Append(2)
+	var metrics = map[string]*Metric{
+		"requests.sum": {Name: "requests.sum", Policy: p, Aggregate: Sum},
+		"requests max": {Name: "requests max", Policy: p, Aggregate: Max},
+	}
+	var b = &bytes.Buffer{}
+	if err := WriteGraphite(b, "service", time.Unix(1546300800, 0), metrics); err != nil {
+		t.Fatal(err)
+	}
+	var expected = "service.requests_max 2 1546300800\nservice.requests.sum 3.5 1546300800\n"
+	if b.String() != expected {
+		t.Fatalf("expected\n%s\nbut got\n%s", expected, b.String())
+	}
+	b.Reset()
+	_ = WriteGraphite(b, "", time.Unix(0, 0), map[string]*Metric{"a": {Name: "a", Policy: p, Aggregate: Sum}})
+	if b.String() != "a 3.5 0\n" {
+		t.Fatalf("unexpected output without a prefix: %q", b.String())
+	}
+}
+
+func TestGraphiteEmitter(t *testing.T) {
+	var listener, err = net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("unable to listen: %v", err)
+	}
+	defer listener.Close()
+	var received = make(chan string, 1)
+	go func() {
+		var conn, err = listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		var b, _ = ioutil.ReadAll(conn)
+		received <- string(b)
+	}()
+
+	var p = NewPointPolicy(NewWindow(1))
+	p.Append(7)
+	var c = &testClock{now: time.Unix(60, 0)}
+	var e = NewGraphiteEmitter(listener.Addr().String(), map[string]*Metric{
+		"value": {Name: "value", Policy: p, Aggregate: Sum},
+	}, WithGraphitePrefix("test"), WithGraphiteClock(c), WithGraphiteInterval(time.Millisecond))
+	var errors = make(chan error, 1)
+	var stop = NewGraphiteEmitter("127.0.0.1:0", nil, WithGraphiteInterval(time.Millisecond), WithGraphiteTimeout(time.Second),
+		WithGraphiteErrorHandler(func(err error) {
+			select {
+			case errors <- err:
+			default:
+			}
+		})).Start()
+	defer stop()
+
+	if err = e.Emit(); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case result := <-received:
+		if result != "test.value 7 60\n" {
+			t.Fatalf("unexpected output %q", result)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for metrics")
+	}
+	select {
+	case <-errors:
+	case <-time.After(time.Second):
+		t.Fatal("expected an error connecting to an invalid address")
+	}
+	stop()
+}
@@ -0,0 +1,45 @@
+package rolling
+
+import "time"
+
+// Hedger recommends when a client should send a second, hedged, copy of a
+// request that has not yet completed. The delay is a high percentile of the
+// recent latencies so that only the slowest requests are hedged. Hedging is
+// only advised when the tail is meaningfully slower than the typical request
+// because otherwise the extra load buys little.
+type Hedger struct {
+	latency    *LatencyTracker
+	percentile float64
+	minimum    int
+	spread     float64
+}
+
+// NewHedger generates a Hedger that recommends hedging after the given
+// percentile of the latencies recorded by the tracker. Hedging is advised
+// once the tracker holds at least the given minimum number of latencies and
+// the percentile is at least spread times the median.
+func NewHedger(latency *LatencyTracker, percentile float64, minimum int, spread float64) *Hedger {
+	return &Hedger{
+		latency:    latency,
+		percentile: percentile,
+		minimum:    minimum,
+		spread:     spread,
+	}
+}
+
+// Record the latency of a completed request.
+func (h *Hedger) Record(d time.Duration) {
+	h.latency.Record(d)
+}
+
+// Delay returns how long to wait for a response before sending a hedged
+// request and whether hedging is currently advisable.
+func (h *Hedger) Delay() (time.Duration, bool) {
+	var results = ReduceAll(h.latency, Count, Percentile(50), Percentile(h.percentile))
+	var count, median, delay = results[0], results[1], results[2]
+	if count < 1 {
+		return 0, false
+	}
+	var advisable = count >= float64(h.minimum) && delay >= median*h.spread
+	return time.Duration(delay), advisable
+}
@@ -0,0 +1,31 @@
+package rolling
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHedger(t *testing.T) {
+	var c = &testClock{now: time.Unix(0, 0)}
+	var h = NewHedger(NewLatencyTracker(10, time.Second, WithClock(c)), 95, 20, 2)
+	if delay, ok := h.Delay(); ok || delay != 0 {
+		t.Fatalf("expected no advice without latencies but got %s, %t", delay, ok)
+	}
+	for x := 0; x < 19; x = x + 1 {
+		h.Record(10 * time.Millisecond)
+	}
+	if delay, ok := h.Delay(); ok || delay != 10*time.Millisecond {
+		t.Fatalf("expected a delay without advice below the minimum but got %s, %t", delay, ok)
+	}
+	h.Record(10 * time.Millisecond)
+	if _, ok := h.Delay(); ok {
+		t.Fatal("expected no advice when the tail matches the median")
+	}
+	for x := 0; x < 4; x = x + 1 {
+		h.Record(100 * time.Millisecond)
+	}
+	var delay, ok = h.Delay()
+	if !ok || delay != 100*time.Millisecond {
+		t.Fatalf("expected to hedge after 100ms but got %s, %t", delay, ok)
+	}
+}
@@ -0,0 +1,152 @@
+package rolling
+
+import (
+	"errors"
+	"math"
+	"sort"
+)
+
+var errHistogramBounds = errors.New("rolling: histograms have different bucket bounds")
+
+// HistogramBucket is a single bucket of a Histogram. It counts the values that
+// are less than or equal to its upper bound and greater than the upper bound
+// of the previous bucket.
+type HistogramBucket struct {
+	UpperBound float64
+	Count      uint64
+}
+
+// Histogram counts values in buckets with fixed bounds. Histograms with the
+// same bounds may be added together without any loss of information, so the
+// histograms of many shards or hosts can be combined into a single
+// distribution before extracting percentiles. This is not possible with the
+// percentiles themselves. A Histogram is not safe for concurrent use; it is
+// meant to be built from a window, such as with HistogramOf, and then read
+// or combined by a single goroutine.
+type Histogram struct {
+	bounds []float64
+	counts []uint64
+	count  uint64
+	sum    float64
+	min    float64
+	max    float64
+}
+
+// NewHistogram generates an empty Histogram with buckets for each of the
+// given upper bounds, in any order, and a final bucket for any value greater
+// than the largest bound.
+func NewHistogram(bounds ...float64) *Histogram {
+	var sorted = append([]float64(nil), bounds...)
+	sort.Float64s(sorted)
+	return &Histogram{
+		bounds: sorted,
+		counts: make([]uint64, len(sorted)+1),
+		min:    math.Inf(1),
+		max:    math.Inf(-1),
+	}
+}
+
+// HistogramOf reduces a window into a new Histogram with the given bounds.
+func HistogramOf(r Reducer, bounds ...float64) *Histogram {
+	var h = NewHistogram(bounds...)
+	r.Reduce(func(w Window) float64 {
+		for _, bucket := range w {
+			for _, value := range bucket {
+				h.Observe(value)
+			}
+		}
+		return 0
+	})
+	return h
+}
+
+// Observe counts a value in the histogram. NaN falls in no bucket and is
+// ignored.
+func (h *Histogram) Observe(value float64) {
+	if math.IsNaN(value) {
+		return
+	}
+	var offset = sort.SearchFloat64s(h.bounds, value)
+	h.counts[offset] = h.counts[offset] + 1
+	h.count = h.count + 1
+	h.sum = h.sum + value
+	h.min = math.Min(h.min, value)
+	h.max = math.Max(h.max, value)
+}
+
+// Add the counts of another histogram to this one. An error is returned, and
+// the histogram is unchanged, if the histograms have different bounds.
+func (h *Histogram) Add(other *Histogram) error {
+	if len(h.bounds) != len(other.bounds) {
+		return errHistogramBounds
+	}
+	for offset, bound := range h.bounds {
+		if other.bounds[offset] != bound {
+			return errHistogramBounds
+		}
+	}
+	for offset, count := range other.counts {
+		h.counts[offset] = h.counts[offset] + count
+	}
+	h.count = h.count + other.count
+	h.sum = h.sum + other.sum
+	h.min = math.Min(h.min, other.min)
+	h.max = math.Max(h.max, other.max)
+	return nil
+}
+
+// Buckets returns every bucket of the histogram in order of its upper bound.
+// The last bucket has an upper bound of positive infinity.
+func (h *Histogram) Buckets() []HistogramBucket {
+	var result = make([]HistogramBucket, len(h.counts))
+	for offset, count := range h.counts {
+		var bound = math.Inf(1)
+		if offset < len(h.bounds) {
+			bound = h.bounds[offset]
+		}
+		result[offset] = HistogramBucket{UpperBound: bound, Count: count}
+	}
+	return result
+}
+
+// Count returns the number of values observed.
+func (h *Histogram) Count() uint64 {
+	return h.count
+}
+
+// Sum returns the sum of the values observed.
+func (h *Histogram) Sum() float64 {
+	return h.sum
+}
+
+// Percentile estimates the value at the given percentile, between 0 and 100,
+// by linear interpolation within the bucket that contains it. The first and
+// last buckets are bounded by the smallest and largest values observed. An
+// empty histogram returns zero.
+func (h *Histogram) Percentile(perc float64) float64 {
+	if h.count < 1 {
+		return 0
+	}
+	var rank = perc / 100 * float64(h.count)
+	var seen = 0.0
+	for offset, count := range h.counts {
+		if count < 1 {
+			continue
+		}
+		if seen+float64(count) < rank {
+			seen = seen + float64(count)
+			continue
+		}
+		var lower = h.min
+		if offset > 0 {
+			lower = math.Max(lower, h.bounds[offset-1])
+		}
+		var upper = h.max
+		if offset < len(h.bounds) {
+			upper = math.Min(upper, h.bounds[offset])
+		}
+		var fraction = (rank - seen) / float64(count)
+		return lower + (upper-lower)*math.Max(0, fraction)
+	}
+	return h.max
+}
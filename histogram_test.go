@@ -0,0 +1,65 @@
+package rolling
+
+import (
+	"math"
+	"testing"
+)
+
+func TestHistogram(t *testing.T) {
+	var p = NewPointPolicy(NewWindow(100))
+	for x := 1; x <= 100; x = x + 1 {
+		p.Append(float64(x))
+	}
+	var h = HistogramOf(p, 50, 10, 25)
+	if h.Count() != 100 || h.Sum() != 5050 {
+		t.Fatalf("expected 100 values summing to 5050 but got %d %f", h.Count(), h.Sum())
+	}
+	var buckets = h.Buckets()
+	if len(buckets) != 4 || buckets[0].UpperBound != 10 || buckets[0].Count != 10 || buckets[2].Count != 25 {
+		t.Fatalf("expected buckets sorted by bound but got %v", buckets)
+	}
+	if !math.IsInf(buckets[3].UpperBound, 1) || buckets[3].Count != 50 {
+		t.Fatalf("expected a final bucket for the largest values but got %v", buckets[3])
+	}
+	if p50 := h.Percentile(50); p50 != 50 {
+		t.Fatalf("expected a median of 50 but got %f", p50)
+	}
+	if p75 := h.Percentile(75); p75 != 75 {
+		t.Fatalf("expected a p75 of 75 but got %f", p75)
+	}
+	if p100 := h.Percentile(100); p100 != 100 {
+		t.Fatalf("expected a p100 of 100 but got %f", p100)
+	}
+	if p0 := NewHistogram(1).Percentile(50); p0 != 0 {
+		t.Fatalf("expected zero for an empty histogram but got %f", p0)
+	}
+	h.Observe(math.NaN())
+	if h.Count() != 100 || h.Sum() != 5050 || h.Percentile(0) != 1 || h.Percentile(100) != 100 {
+		t.Fatalf("expected NaN to be ignored but got %d values summing to %f", h.Count(), h.Sum())
+	}
+}
+
+func TestHistogramAdd(t *testing.T) {
+	var a = NewHistogram(10, 100)
+	var b = NewHistogram(100, 10)
+	a.Observe(5)
+	a.Observe(50)
+	b.Observe(500)
+	b.Observe(1)
+	if err := a.Add(b); err != nil {
+		t.Fatal(err)
+	}
+	var buckets = a.Buckets()
+	if a.Count() != 4 || buckets[0].Count != 2 || buckets[1].Count != 1 || buckets[2].Count != 1 {
+		t.Fatalf("expected the counts of both histograms but got %v", buckets)
+	}
+	if p100 := a.Percentile(100); p100 != 500 {
+		t.Fatalf("expected the largest value of either histogram but got %f", p100)
+	}
+	if err := a.Add(NewHistogram(10, 1000)); err == nil {
+		t.Fatal("expected an error for histograms with different bounds")
+	}
+	if a.Count() != 4 {
+		t.Fatalf("expected a failed add to leave the histogram unchanged but got %d", a.Count())
+	}
+}
@@ -0,0 +1,67 @@
+package rolling
+
+import "time"
+
+// Hooks are functions called when a TimePolicy performs an internal
+// transition that changes its contents without a value being appended. They
+// allow operators to log or count these transitions to explain a sudden drop
+// in an aggregate. Any hook may be nil. Hooks are called while the window is
+// locked and must not call the window.
+type Hooks struct {
+	// OnReset is called when every bucket of the window is cleared at once
+	// because no data arrived for longer than the window. It receives the
+	// number of values that were discarded and is only called if there were
+	// any.
+	OnReset func(dropped int)
+	// OnExpire is called when a single bucket ages out of the window. It
+	// receives the number of values that were discarded and is only called
+	// if there were any.
+	OnExpire func(dropped int)
+	// OnWrap is called when the bucket receiving data moves from the end of
+	// the window back to its start, which happens once per window duration
+	// while data is arriving.
+	OnWrap func()
+	// OnEvict is called with the start time and the values of each bucket
+	// as it leaves the window, whether because it aged out or because the
+	// window was reset. This allows values to be archived or subtracted from
+	// an external accumulator. The values are only valid for the duration of
+	// the call and must be copied to be retained.
+	OnEvict func(start time.Time, values []float64)
+}
+
+// WithHooks sets the functions called when a TimePolicy resets, expires a
+// bucket, or wraps around. Policies that are not built on a TimePolicy ignore
+// this option.
+func WithHooks(hooks Hooks) TimePolicyOption {
+	return func(o *timeOptions) {
+		o.hooks = hooks
+	}
+}
+
+// evict gives the contents of a bucket that is about to be cleared to the
+// OnEvict hook. The bucket is assumed to hold the most recent period that
+// maps to its offset.
+func (w *TimePolicy) evict(offset int) {
+	if w.hooks.OnEvict == nil || len(w.window[offset]) < 1 {
+		return
+	}
+	var age = w.ring.offset(w.ring.last) - offset
+	if age < 0 {
+		age = age + w.numberOfBuckets
+	}
+	w.hooks.OnEvict(w.ring.start(w.ring.last-int64(age)), w.window[offset])
+}
+
+// expireBucket empties a single bucket that has aged out of the window.
+func (w *TimePolicy) expireBucket(offset int) {
+	var dropped = len(w.window[offset])
+	w.evict(offset)
+	w.clearBucket(offset)
+	if dropped < 1 {
+		return
+	}
+	w.counters.expired = w.counters.expired + uint64(dropped)
+	if w.hooks.OnExpire != nil {
+		w.hooks.OnExpire(dropped)
+	}
+}
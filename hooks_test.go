@@ -0,0 +1,75 @@
+package rolling
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHooks(t *testing.T) {
+	var c = &testClock{now: time.Unix(0, 0)}
+	var expired, wraps, reset = 0, 0, 0
+	var p = NewTimePolicy(NewWindow(5), time.Second, WithClock(c), WithHooks(Hooks{
+		OnExpire: func(dropped int) {
+			expired = expired + dropped
+		},
+		OnWrap: func() {
+			wraps = wraps + 1
+		},
+		OnReset: func(dropped int) {
+			reset = reset + dropped
+		},
+	}))
+	for x := 0; x < 5; x = x + 1 {
+		p.Append(1)
+		c.now = c.now.Add(time.Second)
+	}
+	if expired != 0 || wraps != 0 || reset != 0 {
+		t.Fatalf("expected no transitions while filling the window but got %d, %d, %d", expired, wraps, reset)
+	}
+	p.Append(1)
+	if expired != 1 || wraps != 1 {
+		t.Fatalf("expected the first bucket to expire as the window wrapped but got %d, %d", expired, wraps)
+	}
+	c.now = c.now.Add(2 * time.Second)
+	p.Append(1)
+	if expired != 3 {
+		t.Fatalf("expected the skipped bucket and the reused bucket to expire but got %d", expired)
+	}
+	c.now = c.now.Add(time.Minute)
+	p.Reduce(Sum)
+	if reset != 4 {
+		t.Fatalf("expected a reset to drop four values but got %d", reset)
+	}
+	p.Reduce(Sum)
+	if reset != 4 || expired != 3 {
+		t.Fatalf("expected empty buckets not to be reported but got %d, %d", reset, expired)
+	}
+}
+
+func TestHooksOnEvict(t *testing.T) {
+	var c = &testClock{now: time.Unix(0, 0)}
+	var starts = make([]int64, 0)
+	var sum = 0.0
+	var p = NewTimePolicy(NewWindow(3), time.Second, WithClock(c), WithHooks(Hooks{
+		OnEvict: func(start time.Time, values []float64) {
+			starts = append(starts, start.Unix())
+			sum = sum + Sum(Window{values})
+		},
+	}))
+	for x := 0; x < 4; x = x + 1 {
+		p.Append(float64(x))
+		p.Append(float64(x))
+		c.now = c.now.Add(time.Second)
+	}
+	if len(starts) != 1 || starts[0] != 0 || sum != 0 {
+		t.Fatalf("expected the first bucket to be evicted but got %v, %f", starts, sum)
+	}
+	c.now = c.now.Add(time.Minute)
+	p.Reduce(Sum)
+	if len(starts) != 4 || starts[1] != 1 || starts[2] != 2 || starts[3] != 3 {
+		t.Fatalf("expected a reset to evict the remaining buckets in order but got %v", starts)
+	}
+	if sum != 12 {
+		t.Fatalf("expected every evicted value to be received but got %f", sum)
+	}
+}
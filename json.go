@@ -0,0 +1,72 @@
+package rolling
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// BucketSnapshot is a copy of the values of a single bucket of a time window
+// along with the time at which the bucket begins.
+type BucketSnapshot struct {
+	Start  time.Time `json:"start"`
+	Values []float64 `json:"values"`
+}
+
+// TimeSnapshot is a copy of the contents of a time window that may be encoded
+// as JSON. This allows the state of a window to be exchanged with tools that
+// are not written in Go or to be inspected by a person while debugging. Note
+// that JSON cannot represent NaN or infinite values.
+type TimeSnapshot struct {
+	BucketDuration Duration         `json:"bucketDuration"`
+	Buckets        []BucketSnapshot `json:"buckets"`
+}
+
+// Snapshot copies the current contents of the window, ordered from the
+// oldest bucket to the newest.
+func (w *TimePolicy) Snapshot() *TimeSnapshot {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	var s = &TimeSnapshot{
+		BucketDuration: Duration(w.bucketSize),
+		Buckets:        make([]BucketSnapshot, 0, w.numberOfBuckets),
+	}
+	w.eachBucket(w.clock.Now(), func(start time.Time, window Window) {
+		s.Buckets = append(s.Buckets, BucketSnapshot{
+			Start:  start,
+			Values: append(make([]float64, 0, len(window[0])), window[0]...),
+		})
+	})
+	return s
+}
+
+// Reduce the snapshot to a single value using a reduction function. The
+// buckets are given to the function from the oldest to the newest.
+func (s *TimeSnapshot) Reduce(f func(Window) float64) float64 {
+	var w = make(Window, len(s.Buckets))
+	for offset, bucket := range s.Buckets {
+		w[offset] = bucket.Values
+	}
+	return f(w)
+}
+
+// MarshalJSON encodes the frozen window as an array of buckets where each
+// bucket is an array of values.
+func (w *Frozen) MarshalJSON() ([]byte, error) {
+	var buckets = make([][]float64, len(w.window))
+	for offset, bucket := range w.window {
+		buckets[offset] = append(make([]float64, 0, len(bucket)), bucket...)
+	}
+	return json.Marshal(buckets)
+}
+
+// UnmarshalJSON decodes a frozen window from an array of buckets where each
+// bucket is an array of values.
+func (w *Frozen) UnmarshalJSON(b []byte) error {
+	var buckets [][]float64
+	if err := json.Unmarshal(b, &buckets); err != nil {
+		return err
+	}
+	w.window = Window(buckets)
+	return nil
+}
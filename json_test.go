@@ -0,0 +1,65 @@
+package rolling
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestTimeWindowSnapshotJSON(t *testing.T) {
+	var c = &testClock{now: time.Unix(0, 0)}
+	var p = NewTimePolicy(NewWindow(3), time.Second, WithClock(c))
+	p.Append(1)
+	c.now = c.now.Add(2 * time.Second)
+	p.Append(2)
+	p.Append(3)
+
+	var b, err = json.Marshal(p.Snapshot())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var expected = `{"bucketDuration":"1s","buckets":[` +
+		`{"start":"` + time.Unix(0, 0).Format(time.RFC3339Nano) + `","values":[1]},` +
+		`{"start":"` + time.Unix(1, 0).Format(time.RFC3339Nano) + `","values":[]},` +
+		`{"start":"` + time.Unix(2, 0).Format(time.RFC3339Nano) + `","values":[2,3]}]}`
+	if string(b) != expected {
+		t.Fatalf("expected %s but got %s", expected, string(b))
+	}
+
+	var decoded = &TimeSnapshot{}
+	if err = json.Unmarshal(b, decoded); err != nil {
+		t.Fatal(err)
+	}
+	if time.Duration(decoded.BucketDuration) != time.Second {
+		t.Fatalf("expected one second buckets but got %s", time.Duration(decoded.BucketDuration))
+	}
+	if result := decoded.Reduce(Sum); !floatEquals(result, p.Reduce(Sum)) {
+		t.Fatalf("decoded sum calculated incorrectly: %f versus %f", p.Reduce(Sum), result)
+	}
+	if !decoded.Buckets[2].Start.Equal(time.Unix(2, 0)) {
+		t.Fatalf("expected the newest bucket to start at 2s but got %s", decoded.Buckets[2].Start)
+	}
+}
+
+func TestFrozenJSON(t *testing.T) {
+	var p = NewPointPolicy(NewWindow(3))
+	p.Append(1)
+	p.Append(2)
+	var b, err = json.Marshal(Freeze(p))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != `[[1],[2],[0]]` {
+		t.Fatalf("unexpected encoding %s", string(b))
+	}
+	var decoded = &Frozen{}
+	if err = json.Unmarshal(b, decoded); err != nil {
+		t.Fatal(err)
+	}
+	if result := decoded.Reduce(Sum); !floatEquals(result, 3) {
+		t.Fatalf("decoded sum calculated incorrectly: %f versus %f", 3.0, result)
+	}
+	if err = json.Unmarshal([]byte(`{}`), decoded); err == nil {
+		t.Fatal("expected an error for an invalid encoding")
+	}
+}
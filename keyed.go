@@ -0,0 +1,107 @@
+package rolling
+
+import "time"
+
+// keyedValue is the state kept for a single key by a keyed manager.
+type keyedValue interface {
+	// idle reports whether the state holds nothing that still matters at the
+	// given time and may be forgotten.
+	idle(now time.Time) bool
+}
+
+// keyed maintains state for each of many keys and forgets the state of keys
+// that have gone idle. It performs no locking of its own and is guarded by its
+// owner. The state of each key is expected to be unsynchronized for the same
+// reason.
+type keyed struct {
+	create     func() keyedValue
+	clock      Clock
+	interval   time.Duration
+	values     map[string]keyedValue
+	lastExpire time.Time
+	onExpire   func(key string)
+}
+
+// newKeyed generates a manager that creates the state of new keys using the
+// given function and forgets idle keys at most once per interval.
+func newKeyed(create func() keyedValue, interval time.Duration, clock Clock) *keyed {
+	return &keyed{
+		create:     create,
+		clock:      clock,
+		interval:   interval,
+		values:     make(map[string]keyedValue),
+		lastExpire: clock.Now(),
+	}
+}
+
+// newKeyedWindows generates a manager that keeps a time window, made of the
+// given number of buckets of the given duration, for each key. Keys are idle
+// once their window is empty.
+func newKeyedWindows(buckets int, bucketDuration time.Duration, options []TimePolicyOption) *keyed {
+	var o = newTimeOptions(options)
+	return newKeyed(func() keyedValue {
+		return NewNoLockTimePolicy(NewWindow(buckets), bucketDuration, options...)
+	}, bucketDuration*time.Duration(buckets), o.clock)
+}
+
+// newKeyedCounters generates a manager that keeps a counter window, made of
+// the given number of buckets of the given duration, for each key. Keys are
+// idle once their total is zero.
+func newKeyedCounters(buckets int, bucketDuration time.Duration, options []TimePolicyOption) *keyed {
+	var o = newTimeOptions(options)
+	return newKeyed(func() keyedValue {
+		return NewNoLockCounterPolicy(buckets, bucketDuration, options...)
+	}, bucketDuration*time.Duration(buckets), o.clock)
+}
+
+// get returns the state of the given key, creating it if needed. Idle keys
+// are forgotten at most once per interval as a side effect.
+func (k *keyed) get(key string) keyedValue {
+	k.expireEvery()
+	var v, ok = k.values[key]
+	if !ok {
+		v = k.create()
+		k.values[key] = v
+	}
+	return v
+}
+
+// window returns the time window of the given key, creating it if needed.
+func (k *keyed) window(key string) *TimePolicy {
+	return k.get(key).(*TimePolicy)
+}
+
+// counter returns the counter window of the given key, creating it if needed.
+func (k *keyed) counter(key string) *CounterPolicy {
+	return k.get(key).(*CounterPolicy)
+}
+
+// expireEvery forgets every key that is idle if at least one interval has
+// passed since keys were last forgotten.
+func (k *keyed) expireEvery() {
+	if k.clock.Now().Sub(k.lastExpire) >= k.interval {
+		k.expire()
+	}
+}
+
+// expire forgets every key that is idle.
+func (k *keyed) expire() {
+	var now = k.clock.Now()
+	for key, v := range k.values {
+		if v.idle(now) {
+			delete(k.values, key)
+			if k.onExpire != nil {
+				k.onExpire(key)
+			}
+		}
+	}
+	k.lastExpire = now
+}
+
+func (w *TimePolicy) idle(time.Time) bool {
+	return w.Len() == 0
+}
+
+func (w *CounterPolicy) idle(time.Time) bool {
+	return w.Total() == 0
+}
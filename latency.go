@@ -0,0 +1,52 @@
+package rolling
+
+import "time"
+
+// LatencyTracker records the durations of an operation over a rolling time
+// window and answers whether a percentile of those durations is within a
+// target, such as a latency objective of 250ms at the 99th percentile.
+type LatencyTracker struct {
+	policy *TimePolicy
+}
+
+// NewLatencyTracker generates a LatencyTracker over a time window made of the
+// given number of buckets of the given duration.
+func NewLatencyTracker(buckets int, bucketDuration time.Duration, options ...TimePolicyOption) *LatencyTracker {
+	return &LatencyTracker{
+		policy: NewTimePolicy(NewWindow(buckets), bucketDuration, options...),
+	}
+}
+
+// Record a single duration.
+func (t *LatencyTracker) Record(d time.Duration) {
+	t.policy.Append(float64(d))
+}
+
+// RecordSince records the duration between the given time and now according
+// to the clock of the tracker. It is convenient to defer:
+//
+//	defer tracker.RecordSince(time.Now())
+func (t *LatencyTracker) RecordSince(start time.Time) {
+	t.Record(t.policy.clock.Now().Sub(start))
+}
+
+// Percentile returns the given percentile of the durations within the window.
+// An empty window has a percentile of zero.
+func (t *LatencyTracker) Percentile(perc float64) time.Duration {
+	return time.Duration(t.policy.Reduce(Percentile(perc)))
+}
+
+// Within reports whether the given percentile of the durations within the
+// window is at or below the target. The margin is the target minus the
+// percentile and is negative when the target is missed. An empty window is
+// within any target that is not negative.
+func (t *LatencyTracker) Within(perc float64, target time.Duration) (bool, time.Duration) {
+	var margin = target - t.Percentile(perc)
+	return margin >= 0, margin
+}
+
+// Reduce the window to a single value using a reduction function. Durations
+// are stored as a number of nanoseconds.
+func (t *LatencyTracker) Reduce(f func(Window) float64) float64 {
+	return t.policy.Reduce(f)
+}
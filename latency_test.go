@@ -0,0 +1,32 @@
+package rolling
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLatencyTracker(t *testing.T) {
+	var c = &testClock{now: time.Unix(0, 0)}
+	var tracker = NewLatencyTracker(10, time.Second, WithClock(c))
+	if ok, margin := tracker.Within(99, 250*time.Millisecond); !ok || margin != 250*time.Millisecond {
+		t.Fatalf("expected an empty tracker to be within the target but got %t, %s", ok, margin)
+	}
+	for x := 1; x <= 100; x = x + 1 {
+		tracker.Record(time.Duration(x) * time.Millisecond)
+	}
+	if result := tracker.Percentile(50); result != 50500*time.Microsecond {
+		t.Fatalf("expected a median of 50.5ms but got %s", result)
+	}
+	if ok, margin := tracker.Within(99, 250*time.Millisecond); !ok || margin != 150500*time.Microsecond {
+		t.Fatalf("expected to be within the target by 150.5ms but got %t, %s", ok, margin)
+	}
+	var start = c.now
+	c.now = c.now.Add(time.Second)
+	tracker.RecordSince(start)
+	if ok, margin := tracker.Within(100, 250*time.Millisecond); ok || margin != -750*time.Millisecond {
+		t.Fatalf("expected to miss the target by 750ms but got %t, %s", ok, margin)
+	}
+	if result := tracker.Reduce(Count); result != 101 {
+		t.Fatalf("expected 101 durations but got %f", result)
+	}
+}
@@ -0,0 +1,37 @@
+package rolling
+
+import "time"
+
+// LimitedDurationPolicy wraps a TimePolicy such that every reduction results
+// in zero until the window has been collecting data for a minimum amount of
+// time. This prevents decisions from being made on a window that has only
+// observed a fraction of the time it is meant to cover, such as just after a
+// process starts or in services that receive too little traffic for a count
+// based limit to be useful.
+type LimitedDurationPolicy struct {
+	policy *TimePolicy
+	minAge time.Duration
+}
+
+// NewLimitedDurationPolicy wraps the given TimePolicy such that it reduces to
+// zero until it is at least as old as the given duration.
+func NewLimitedDurationPolicy(policy *TimePolicy, minAge time.Duration) *LimitedDurationPolicy {
+	return &LimitedDurationPolicy{
+		policy: policy,
+		minAge: minAge,
+	}
+}
+
+// Append a value to the underlying window.
+func (w *LimitedDurationPolicy) Append(value float64) {
+	w.policy.Append(value)
+}
+
+// Reduce the window to a single value using a reduction function. The result
+// is always zero if the window is younger than the minimum duration.
+func (w *LimitedDurationPolicy) Reduce(f func(Window) float64) float64 {
+	if w.policy.Age() < w.minAge {
+		return 0
+	}
+	return w.policy.Reduce(f)
+}
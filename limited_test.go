@@ -0,0 +1,25 @@
+package rolling
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLimitedDurationPolicy(t *testing.T) {
+	var c = &testClock{now: time.Unix(0, 0)}
+	var tp = NewTimePolicy(NewWindow(10), time.Second, WithClock(c))
+	var p Policy = NewLimitedDurationPolicy(tp, 5*time.Second)
+	p.Append(1)
+	if result := p.Reduce(Sum); !floatEquals(result, 0) {
+		t.Fatalf("young window should reduce to zero but got %f", result)
+	}
+	c.now = c.now.Add(4 * time.Second)
+	p.Append(1)
+	if result := p.Reduce(Sum); !floatEquals(result, 0) {
+		t.Fatalf("young window should reduce to zero but got %f", result)
+	}
+	c.now = c.now.Add(time.Second)
+	if result := p.Reduce(Sum); !floatEquals(result, 2) {
+		t.Fatalf("sum calculated incorrectly: %f versus %f", 2.0, result)
+	}
+}
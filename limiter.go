@@ -0,0 +1,86 @@
+package rolling
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RateLimiter admits, at most, a limited number of events for each key within
+// a rolling time window. Unlike a limit that resets at fixed intervals, a
+// rolling limit cannot be exceeded by a burst that straddles the boundary
+// between two intervals.
+type RateLimiter struct {
+	keys  *keyed
+	limit int
+	lock  *sync.Mutex
+}
+
+// NewRateLimiter generates a RateLimiter that admits the given number of
+// events for each key within a time window made of the given number of
+// buckets of the given duration. Smaller buckets allow admission to resume
+// more smoothly as old events expire.
+func NewRateLimiter(limit int, buckets int, bucketDuration time.Duration, options ...TimePolicyOption) *RateLimiter {
+	return &RateLimiter{
+		keys:  newKeyedWindows(buckets, bucketDuration, options),
+		limit: limit,
+		lock:  &sync.Mutex{},
+	}
+}
+
+// Allow records an event for the given key if doing so would not exceed the
+// limit and reports whether the event was admitted. When the event is not
+// admitted the duration until the oldest event in the window expires, and an
+// event may be admitted again, is also returned.
+func (l *RateLimiter) Allow(key string) (bool, time.Duration) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	var w = l.keys.window(key)
+	if w.Len() < l.limit {
+		w.Append(1)
+		return true, 0
+	}
+	var _, oldest, ok = w.Oldest()
+	if !ok {
+		// A limit of zero or less admits nothing and never recovers.
+		return false, w.WindowDuration()
+	}
+	var retry = oldest.Add(w.WindowDuration()).Sub(w.clock.Now())
+	if retry < 0 {
+		retry = 0
+	}
+	return false, retry
+}
+
+// Limiter is implemented by each of the per key limiters, such as
+// RateLimiter and GCRALimiter. Allow records an event for the key if it is
+// admitted and otherwise returns the duration until an event would be.
+type Limiter interface {
+	Allow(key string) (bool, time.Duration)
+}
+
+// RateLimit returns HTTP middleware that applies the given Limiter to every
+// request. Requests are grouped by the key returned by the given
+// function, such as the remote address or an API token. Requests over the
+// limit receive a 429 Too Many Requests response with a Retry-After header
+// giving the number of seconds until a request will be admitted again.
+func RateLimit(l Limiter, key func(*http.Request) string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var ok, retry = l.Allow(key(r))
+			if !ok {
+				var seconds = int(math.Ceil(retry.Seconds()))
+				if seconds < 1 {
+					seconds = 1
+				}
+				w.Header().Set("Retry-After", strconv.Itoa(seconds))
+				http.Error(w, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package rolling
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRateLimiter(t *testing.T) {
+	var c = &testClock{now: time.Unix(0, 0)}
+	var l = NewRateLimiter(3, 10, time.Second, WithClock(c))
+	for x := 0; x < 3; x = x + 1 {
+		if ok, _ := l.Allow("a"); !ok {
+			t.Fatalf("expected event %d to be admitted", x)
+		}
+		c.now = c.now.Add(time.Second)
+	}
+	var ok, retry = l.Allow("a")
+	if ok {
+		t.Fatal("expected the event over the limit to be rejected")
+	}
+	// The oldest event was at zero seconds and expires at ten.
+	if retry != 7*time.Second {
+		t.Fatalf("expected to retry after 7s but got %s", retry)
+	}
+	if ok, _ = l.Allow("b"); !ok {
+		t.Fatal("expected a different key to be admitted")
+	}
+	c.now = c.now.Add(retry)
+	if ok, _ = l.Allow("a"); !ok {
+		t.Fatal("expected an event to be admitted after the retry duration")
+	}
+	if ok, _ = l.Allow("a"); ok {
+		t.Fatal("expected the event over the limit to be rejected")
+	}
+}
+
+func TestRateLimiterZero(t *testing.T) {
+	var l = NewRateLimiter(0, 10, time.Second)
+	if ok, retry := l.Allow("a"); ok || retry != 10*time.Second {
+		t.Fatalf("expected a zero limit to reject every event but got %t, %s", ok, retry)
+	}
+}
+
+func TestRateLimitMiddleware(t *testing.T) {
+	var c = &testClock{now: time.Unix(0, 500*int64(time.Millisecond))}
+	var l = NewRateLimiter(1, 60, time.Second, WithClock(c))
+	var handler = RateLimit(l, func(r *http.Request) string {
+		return r.Header.Get("Authorization")
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	var request = httptest.NewRequest("GET", "/", nil)
+	request.Header.Set("Authorization", "token")
+	var recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusNoContent {
+		t.Fatalf("expected the first request to be admitted but got %d", recorder.Code)
+	}
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected the second request to be rejected but got %d", recorder.Code)
+	}
+	if retry := recorder.Header().Get("Retry-After"); retry != "60" {
+		t.Fatalf("expected to retry after 60 seconds but got %q", retry)
+	}
+}
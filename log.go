@@ -0,0 +1,35 @@
+package rolling
+
+import "time"
+
+// LogFunc writes a structured log entry made of a message followed by
+// alternating keys and values. The Info method of a *slog.Logger, and the
+// Infow method of a zap SugaredLogger, both have this form and may be given
+// wherever a LogFunc is expected:
+//
+//	rolling.LogMetrics(slog.Default().Info, "metrics", metrics)
+//
+// Other loggers may be adapted with a function literal.
+type LogFunc func(msg string, keysAndValues ...interface{})
+
+// LogMetrics evaluates every given Metric and writes the results as a single
+// structured log entry with the given message. Each Metric becomes a field
+// whose key is the key of the Metric in the map. Fields are written in order
+// of their keys.
+func LogMetrics(log LogFunc, msg string, metrics map[string]*Metric) {
+	var values = Evaluate(metrics)
+	var fields = make([]interface{}, 0, 2*len(values))
+	for _, v := range values {
+		fields = append(fields, v.Name, v.Value)
+	}
+	log(msg, fields...)
+}
+
+// LogMetricsEvery calls LogMetrics each time the interval elapses until the
+// returned function is called. This is useful for teams whose only telemetry
+// pipeline is their logs.
+func LogMetricsEvery(interval time.Duration, log LogFunc, msg string, metrics map[string]*Metric) func() {
+	return every(interval, func() {
+		LogMetrics(log, msg, metrics)
+	})
+}
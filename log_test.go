@@ -0,0 +1,55 @@
+package rolling
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLogMetrics(t *testing.T) {
+	var p = NewPointPolicy(NewWindow(2))
+	p.Append(1)
+	p.Append(3)
+	var metrics = map[string]*Metric{
+		"sum": {Name: "sum", Policy: p, Aggregate: Sum},
+		"avg": {Name: "avg", Policy: p, Aggregate: Avg},
+	}
+	var message string
+	var fields []interface{}
+	LogMetrics(func(msg string, keysAndValues ...interface{}) {
+		message = msg
+		fields = keysAndValues
+	}, "window metrics", metrics)
+	if message != "window metrics" {
+		t.Fatalf("unexpected message %q", message)
+	}
+	if result := fmt.Sprint(fields...); result != fmt.Sprint("avg", 2.0, "sum", 4.0) {
+		t.Fatalf("unexpected fields %v", fields)
+	}
+}
+
+func TestLogMetricsEvery(t *testing.T) {
+	var lock = &sync.Mutex{}
+	var entries = 0
+	var stop = LogMetricsEvery(time.Millisecond, func(string, ...interface{}) {
+		lock.Lock()
+		defer lock.Unlock()
+		entries = entries + 1
+	}, "metrics", nil)
+	defer stop()
+	var deadline = time.Now().Add(time.Second)
+	for {
+		lock.Lock()
+		var logged = entries
+		lock.Unlock()
+		if logged > 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("expected metrics to be logged")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	stop()
+}
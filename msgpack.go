@@ -0,0 +1,181 @@
+package rolling
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"time"
+)
+
+var errInvalidMsgpack = errors.New("rolling: invalid MessagePack encoding")
+
+// MarshalMsgpack encodes the snapshot as MessagePack. The encoding is an
+// array of two elements: the bucket duration in nanoseconds and an array of
+// buckets. Each bucket is an array of two elements: the time at which the
+// bucket begins, in nanoseconds since the Unix epoch, and an array of its
+// values.
+//
+//	[1000000000, [[1546300800000000000, [1.5, 2]], ...]]
+//
+// This is considerably smaller, and faster to produce and consume, than JSON
+// when window state is shipped between processes many times per second.
+func (s *TimeSnapshot) MarshalMsgpack() ([]byte, error) {
+	var b = make([]byte, 0, 16+len(s.Buckets)*16)
+	b = appendMsgpackArray(b, 2)
+	b = appendMsgpackInt(b, int64(s.BucketDuration))
+	b = appendMsgpackArray(b, len(s.Buckets))
+	for _, bucket := range s.Buckets {
+		b = appendMsgpackArray(b, 2)
+		var start int64
+		if !bucket.Start.IsZero() {
+			start = bucket.Start.UnixNano()
+		}
+		b = appendMsgpackInt(b, start)
+		b = appendMsgpackArray(b, len(bucket.Values))
+		for _, v := range bucket.Values {
+			b = append(b, 0xcb)
+			var buf [8]byte
+			binary.BigEndian.PutUint64(buf[:], math.Float64bits(v))
+			b = append(b, buf[:]...)
+		}
+	}
+	return b, nil
+}
+
+// UnmarshalMsgpack decodes the snapshot from the MessagePack encoding
+// produced by MarshalMsgpack. Integers and floats of any width are accepted.
+func (s *TimeSnapshot) UnmarshalMsgpack(b []byte) error {
+	var d = &msgpackDecoder{b: b}
+	*s = TimeSnapshot{}
+	if n := d.array(); n != 2 {
+		return errInvalidMsgpack
+	}
+	s.BucketDuration = Duration(d.int())
+	var buckets = d.array()
+	if d.err == nil {
+		s.Buckets = make([]BucketSnapshot, 0, buckets)
+	}
+	for x := 0; x < buckets && d.err == nil; x = x + 1 {
+		if n := d.array(); n != 2 {
+			return errInvalidMsgpack
+		}
+		var bucket = BucketSnapshot{Start: time.Unix(0, d.int())}
+		var values = d.array()
+		bucket.Values = make([]float64, 0, values)
+		for y := 0; y < values && d.err == nil; y = y + 1 {
+			bucket.Values = append(bucket.Values, d.float())
+		}
+		s.Buckets = append(s.Buckets, bucket)
+	}
+	if d.err == nil && len(d.b) > 0 {
+		return errInvalidMsgpack
+	}
+	return d.err
+}
+
+func appendMsgpackArray(b []byte, n int) []byte {
+	switch {
+	case n < 16:
+		return append(b, 0x90|byte(n))
+	case n <= math.MaxUint16:
+		return append(b, 0xdc, byte(n>>8), byte(n))
+	default:
+		return append(b, 0xdd, byte(n>>24), byte(n>>16), byte(n>>8), byte(n))
+	}
+}
+
+func appendMsgpackInt(b []byte, v int64) []byte {
+	switch {
+	case v >= 0 && v <= 127:
+		return append(b, byte(v))
+	case v < 0 && v >= -32:
+		return append(b, byte(v))
+	case v >= math.MinInt32 && v <= math.MaxInt32:
+		var buf [4]byte
+		binary.BigEndian.PutUint32(buf[:], uint32(v))
+		return append(append(b, 0xd2), buf[:]...)
+	default:
+		var buf [8]byte
+		binary.BigEndian.PutUint64(buf[:], uint64(v))
+		return append(append(b, 0xd3), buf[:]...)
+	}
+}
+
+// msgpackDecoder reads the subset of MessagePack used by MarshalMsgpack. The
+// first error encountered is retained and every later read returns zero.
+type msgpackDecoder struct {
+	b   []byte
+	err error
+}
+
+func (d *msgpackDecoder) next(n int) []byte {
+	if d.err != nil || len(d.b) < n {
+		d.err = errInvalidMsgpack
+		return make([]byte, n)
+	}
+	var result = d.b[:n]
+	d.b = d.b[n:]
+	return result
+}
+
+func (d *msgpackDecoder) array() int {
+	var t = d.next(1)[0]
+	switch {
+	case t&0xf0 == 0x90:
+		return int(t & 0x0f)
+	case t == 0xdc:
+		return int(binary.BigEndian.Uint16(d.next(2)))
+	case t == 0xdd:
+		var n = binary.BigEndian.Uint32(d.next(4))
+		// Every element occupies at least one byte.
+		if uint64(n) > uint64(len(d.b)) {
+			d.err = errInvalidMsgpack
+			return 0
+		}
+		return int(n)
+	}
+	d.err = errInvalidMsgpack
+	return 0
+}
+
+func (d *msgpackDecoder) int() int64 {
+	var t = d.next(1)[0]
+	switch {
+	case t <= 0x7f:
+		return int64(t)
+	case t >= 0xe0:
+		return int64(int8(t))
+	case t == 0xcc:
+		return int64(d.next(1)[0])
+	case t == 0xcd:
+		return int64(binary.BigEndian.Uint16(d.next(2)))
+	case t == 0xce:
+		return int64(binary.BigEndian.Uint32(d.next(4)))
+	case t == 0xcf:
+		return int64(binary.BigEndian.Uint64(d.next(8)))
+	case t == 0xd0:
+		return int64(int8(d.next(1)[0]))
+	case t == 0xd1:
+		return int64(int16(binary.BigEndian.Uint16(d.next(2))))
+	case t == 0xd2:
+		return int64(int32(binary.BigEndian.Uint32(d.next(4))))
+	case t == 0xd3:
+		return int64(binary.BigEndian.Uint64(d.next(8)))
+	}
+	d.err = errInvalidMsgpack
+	return 0
+}
+
+func (d *msgpackDecoder) float() float64 {
+	if len(d.b) > 0 {
+		switch d.b[0] {
+		case 0xca:
+			d.next(1)
+			return float64(math.Float32frombits(binary.BigEndian.Uint32(d.next(4))))
+		case 0xcb:
+			d.next(1)
+			return math.Float64frombits(binary.BigEndian.Uint64(d.next(8)))
+		}
+	}
+	return float64(d.int())
+}
@@ -0,0 +1,76 @@
+package rolling
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestTimeSnapshotMsgpackEncoding(t *testing.T) {
+	var s = &TimeSnapshot{
+		BucketDuration: Duration(time.Second),
+		Buckets:        []BucketSnapshot{{Start: time.Unix(0, 5), Values: []float64{1}}},
+	}
+	var b, err = s.MarshalMsgpack()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var expected = []byte{
+		0x92,                         // array of two
+		0xd2, 0x3b, 0x9a, 0xca, 0x00, // int32 1e9
+		0x91,       // array of one bucket
+		0x92, 0x05, // array of two, start of 5
+		0x91, 0xcb, 0x3f, 0xf0, 0, 0, 0, 0, 0, 0, // array of one value, float64 1
+	}
+	if !bytes.Equal(b, expected) {
+		t.Fatalf("expected % x but got % x", expected, b)
+	}
+}
+
+func TestTimeSnapshotMsgpackRoundTrip(t *testing.T) {
+	var c = &testClock{now: time.Unix(1546300800, 0)}
+	var p = NewTimePolicy(NewWindow(20), time.Second, WithClock(c))
+	for x := 0; x < 20; x = x + 1 {
+		for y := 0; y < x; y = y + 1 {
+			p.Append(float64(y) - 2.5)
+		}
+		c.now = c.now.Add(time.Second)
+	}
+	c.now = c.now.Add(-time.Second)
+
+	var s = p.Snapshot()
+	var b, err = s.MarshalMsgpack()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded = &TimeSnapshot{}
+	if err = decoded.UnmarshalMsgpack(b); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.BucketDuration != s.BucketDuration || len(decoded.Buckets) != len(s.Buckets) {
+		t.Fatalf("expected %+v but got %+v", s, decoded)
+	}
+	for offset, bucket := range s.Buckets {
+		if !decoded.Buckets[offset].Start.Equal(bucket.Start) {
+			t.Fatalf("expected bucket %d to start at %s but got %s", offset, bucket.Start, decoded.Buckets[offset].Start)
+		}
+	}
+	if result := decoded.Reduce(Sum); !floatEquals(result, p.Reduce(Sum)) {
+		t.Fatalf("decoded sum calculated incorrectly: %f versus %f", p.Reduce(Sum), result)
+	}
+}
+
+func TestTimeSnapshotMsgpackInvalid(t *testing.T) {
+	var decoded = &TimeSnapshot{}
+	if err := decoded.UnmarshalMsgpack([]byte{0x92, 0x01, 0x91, 0x92, 0x00, 0x91, 0xca, 0x3f, 0x80, 0, 0}); err != nil {
+		t.Fatalf("expected float32 values to be accepted: %v", err)
+	}
+	if result := decoded.Reduce(Sum); !floatEquals(result, 1) {
+		t.Fatalf("decoded sum calculated incorrectly: %f versus %f", 1.0, result)
+	}
+	for _, invalid := range [][]byte{{}, {0x93}, {0x92, 0x01}, {0x92, 0x01, 0x91, 0x92, 0x00, 0x91, 0xcb}, {0x92, 0x01, 0x90, 0x00}, {0x92, 0x01, 0xdd, 0xff, 0xff, 0xff, 0xff}} {
+		if err := decoded.UnmarshalMsgpack(invalid); err == nil {
+			t.Fatalf("expected an error decoding % x", invalid)
+		}
+	}
+}
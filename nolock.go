@@ -0,0 +1,55 @@
+package rolling
+
+import (
+	"sync"
+	"time"
+)
+
+// rwLocker is the subset of sync.RWMutex used by policies that allow
+// concurrent readers.
+type rwLocker interface {
+	sync.Locker
+	RLock()
+	RUnlock()
+}
+
+// noLock satisfies the lock interfaces of a policy without synchronizing
+// anything.
+type noLock struct{}
+
+func (noLock) Lock()    {}
+func (noLock) Unlock()  {}
+func (noLock) RLock()   {}
+func (noLock) RUnlock() {}
+
+// NewNoLockPointPolicy generates a PointPolicy that performs no locking. It
+// avoids the cost of a mutex on every call for callers that already ensure
+// the policy is only used by one goroutine at a time, such as a single
+// goroutine pipeline. It is not safe for concurrent use. A Clone of the
+// policy is safe for concurrent use.
+func NewNoLockPointPolicy(window Window) *PointPolicy {
+	var p = NewPointPolicy(window)
+	p.lock = noLock{}
+	return p
+}
+
+// NewNoLockCounterPolicy generates a CounterPolicy that performs no locking.
+// It avoids the cost of a mutex on every call for callers that already
+// ensure the policy is only used by one goroutine at a time. It is not safe
+// for concurrent use.
+func NewNoLockCounterPolicy(buckets int, bucketDuration time.Duration, options ...TimePolicyOption) *CounterPolicy {
+	var p = NewCounterPolicy(buckets, bucketDuration, options...)
+	p.lock = noLock{}
+	return p
+}
+
+// NewNoLockTimePolicy generates a TimePolicy that performs no locking. It
+// avoids the cost of a mutex on every call for callers that already ensure
+// the policy is only used by one goroutine at a time, such as a single
+// goroutine pipeline. It is not safe for concurrent use. A Clone of the
+// policy is safe for concurrent use.
+func NewNoLockTimePolicy(window Window, bucketDuration time.Duration, options ...TimePolicyOption) *TimePolicy {
+	var p = NewTimePolicy(window, bucketDuration, options...)
+	p.lock = noLock{}
+	return p
+}
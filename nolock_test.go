@@ -0,0 +1,49 @@
+package rolling
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNoLockPointWindow(t *testing.T) {
+	var p = NewNoLockPointPolicy(NewWindow(3))
+	for x := 1; x <= 4; x = x + 1 {
+		p.Append(float64(x))
+	}
+	if result := p.Reduce(Sum); !floatEquals(result, 9) {
+		t.Fatalf("sum calculated incorrectly: %f versus %f", 9.0, result)
+	}
+	var c = p.Clone()
+	if _, ok := c.lock.(noLock); ok {
+		t.Fatal("expected a clone to be safe for concurrent use")
+	}
+}
+
+func TestNoLockTimeWindow(t *testing.T) {
+	var c = &testClock{now: time.Unix(0, 0)}
+	var p = NewNoLockTimePolicy(NewWindow(3), time.Second, WithClock(c))
+	p.Append(1)
+	c.now = c.now.Add(time.Second)
+	p.Append(2)
+	c.now = c.now.Add(5 * time.Second)
+	p.Append(3)
+	if result := p.Reduce(Sum); !floatEquals(result, 3) {
+		t.Fatalf("sum calculated incorrectly: %f versus %f", 3.0, result)
+	}
+}
+
+func BenchmarkNoLockPointWindow(b *testing.B) {
+	var p = NewNoLockPointPolicy(NewWindow(100))
+	b.ResetTimer()
+	for n := 0; n < b.N; n = n + 1 {
+		p.Append(1)
+	}
+}
+
+func BenchmarkNoLockTimeWindow(b *testing.B) {
+	var p = NewNoLockTimePolicy(NewWindow(100), time.Millisecond)
+	b.ResetTimer()
+	for n := 0; n < b.N; n = n + 1 {
+		p.Append(1)
+	}
+}
@@ -0,0 +1,144 @@
+package rolling
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+)
+
+// HistogramMetric is a Histogram of the values of a window, computed with
+// the given bounds each time the metric is written, such as by
+// WriteOpenMetrics.
+type HistogramMetric struct {
+	Policy Policy
+	Bounds []float64
+}
+
+// Histogram reduces the window of the metric into a Histogram with its
+// bounds.
+func (m *HistogramMetric) Histogram() *Histogram {
+	return HistogramOf(m.Policy, m.Bounds...)
+}
+
+// WriteOpenMetrics evaluates every given Metric and HistogramMetric and writes
+// the results to the given writer in the OpenMetrics text format. Each Metric
+// is written as a gauge and each HistogramMetric as a histogram, named after
+// its key in the map, with any character that is not valid in an OpenMetrics
+// name replaced by an underscore. Gauges are written in order of their keys,
+// followed by histograms in order of their keys. An error is returned, and
+// nothing is written, if two keys have the same name once replaced or if a
+// gauge has the name of a sample of a histogram, such as its _count. This
+// allows a service to serve scrapeable metrics without a metrics client
+// library:
+//
+//	http.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+//		w.Header().Set("Content-Type", "application/openmetrics-text; version=1.0.0; charset=utf-8")
+//		_ = rolling.WriteOpenMetrics(w, metrics, histograms)
+//	})
+func WriteOpenMetrics(w io.Writer, metrics map[string]*Metric, histograms map[string]*HistogramMetric) error {
+	var keys = make([]string, 0, len(histograms))
+	for key := range histograms {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var names = make(map[string]string, len(metrics)+4*len(histograms))
+	var reserve = func(key string, sanitized string) error {
+		if other, ok := names[sanitized]; ok {
+			return fmt.Errorf("rolling: metrics %q and %q have the same OpenMetrics name %q", other, key, sanitized)
+		}
+		names[sanitized] = key
+		return nil
+	}
+	var gauges = Evaluate(metrics)
+	for _, v := range gauges {
+		if err := reserve(v.Name, openMetricsName(v.Name)); err != nil {
+			return err
+		}
+	}
+	for _, key := range keys {
+		var sanitized = openMetricsName(key)
+		for _, name := range []string{sanitized, sanitized + "_bucket", sanitized + "_count", sanitized + "_sum"} {
+			if err := reserve(key, name); err != nil {
+				return err
+			}
+		}
+	}
+
+	var b = bufio.NewWriter(w)
+	for _, v := range gauges {
+		var sanitized = openMetricsName(v.Name)
+		_, _ = b.WriteString("# TYPE ")
+		_, _ = b.WriteString(sanitized)
+		_, _ = b.WriteString(" gauge\n")
+		_, _ = b.WriteString(sanitized)
+		_ = b.WriteByte(' ')
+		_, _ = b.WriteString(openMetricsValue(v.Value))
+		_ = b.WriteByte('\n')
+	}
+	for _, key := range keys {
+		writeOpenMetricsHistogram(b, openMetricsName(key), histograms[key].Histogram())
+	}
+	_, _ = b.WriteString("# EOF\n")
+	return b.Flush()
+}
+
+// writeOpenMetricsHistogram writes a histogram with cumulative buckets. The
+// sum is omitted when the histogram may contain negative values, as required
+// by OpenMetrics.
+func writeOpenMetricsHistogram(b *bufio.Writer, name string, h *Histogram) {
+	_, _ = b.WriteString("# TYPE ")
+	_, _ = b.WriteString(name)
+	_, _ = b.WriteString(" histogram\n")
+	var cumulative uint64
+	for _, bucket := range h.Buckets() {
+		cumulative = cumulative + bucket.Count
+		_, _ = b.WriteString(name)
+		_, _ = b.WriteString("_bucket{le=\"")
+		_, _ = b.WriteString(openMetricsValue(bucket.UpperBound))
+		_, _ = b.WriteString("\"} ")
+		_, _ = b.WriteString(strconv.FormatUint(cumulative, 10))
+		_ = b.WriteByte('\n')
+	}
+	_, _ = b.WriteString(name)
+	_, _ = b.WriteString("_count ")
+	_, _ = b.WriteString(strconv.FormatUint(h.Count(), 10))
+	_ = b.WriteByte('\n')
+	if h.min >= 0 && (len(h.bounds) < 1 || h.bounds[0] >= 0) {
+		_, _ = b.WriteString(name)
+		_, _ = b.WriteString("_sum ")
+		_, _ = b.WriteString(openMetricsValue(h.Sum()))
+		_ = b.WriteByte('\n')
+	}
+}
+
+// openMetricsName replaces each character that may not appear in an
+// OpenMetrics metric name with an underscore.
+func openMetricsName(name string) string {
+	var result = []byte(name)
+	for offset, c := range result {
+		var valid = c == '_' || c == ':' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') ||
+			(offset > 0 && c >= '0' && c <= '9')
+		if !valid {
+			result[offset] = '_'
+		}
+	}
+	if len(result) == 0 {
+		return "_"
+	}
+	return string(result)
+}
+
+func openMetricsValue(v float64) string {
+	switch {
+	case math.IsNaN(v):
+		return "NaN"
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
@@ -0,0 +1,114 @@
+package rolling
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestWriteOpenMetrics(t *testing.T) {
+	var requests = NewPointPolicy(NewWindow(3))
+	requests.Append(1)
+	requests.Append(2)
+	var constant = func(v float64) func(Window) float64 {
+		return func(Window) float64 { return v }
+	}
+	var metrics = map[string]*Metric{
+		"http.requests": {Name: "http.requests", Policy: requests, Aggregate: Sum},
+		"9lives":        {Name: "9lives", Policy: requests, Aggregate: constant(math.Inf(1))},
+		"ratio":         {Name: "ratio", Policy: requests, Aggregate: constant(.25)},
+	}
+	var b = &bytes.Buffer{}
+	if err := WriteOpenMetrics(b, metrics, nil); err != nil {
+		t.Fatal(err)
+	}
+	var expected = "# TYPE _lives gauge\n_lives +Inf\n" +
+		"# TYPE http_requests gauge\nhttp_requests 3\n" +
+		"# TYPE ratio gauge\nratio 0.25\n" +
+		"# EOF\n"
+	if b.String() != expected {
+		t.Fatalf("expected\n%s\nbut got\n%s", expected, b.String())
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("closed")
+}
+
+func TestWriteOpenMetricsError(t *testing.T) {
+	if err := WriteOpenMetrics(failingWriter{}, nil, nil); err == nil {
+		t.Fatal("expected an error from the writer")
+	}
+}
+
+func TestWriteOpenMetricsUnnamed(t *testing.T) {
+	var p = NewPointPolicy(NewWindow(2))
+	p.Append(1)
+	p.Append(2)
+	var metrics = map[string]*Metric{
+		"requests_sum": From(p).Sum().Build(),
+		"requests_max": From(p).Max().Build(),
+	}
+	var b = &bytes.Buffer{}
+	if err := WriteOpenMetrics(b, metrics, nil); err != nil {
+		t.Fatal(err)
+	}
+	var expected = "# TYPE requests_max gauge\nrequests_max 2\n" +
+		"# TYPE requests_sum gauge\nrequests_sum 3\n" +
+		"# EOF\n"
+	if b.String() != expected {
+		t.Fatalf("expected\n%s\nbut got\n%s", expected, b.String())
+	}
+}
+
+func TestWriteOpenMetricsHistogram(t *testing.T) {
+	var p = NewPointPolicy(NewWindow(4))
+	for _, v := range []float64{0.5, 1, 3, 20} {
+		p.Append(v)
+	}
+	var histograms = map[string]*HistogramMetric{
+		"latency": {Policy: p, Bounds: []float64{1, 5}},
+	}
+	var metrics = map[string]*Metric{
+		"requests": From(p).Count().Build(),
+	}
+	var b = &bytes.Buffer{}
+	if err := WriteOpenMetrics(b, metrics, histograms); err != nil {
+		t.Fatal(err)
+	}
+	var expected = "# TYPE requests gauge\nrequests 4\n" +
+		"# TYPE latency histogram\n" +
+		"latency_bucket{le=\"1\"} 2\nlatency_bucket{le=\"5\"} 3\nlatency_bucket{le=\"+Inf\"} 4\n" +
+		"latency_count 4\nlatency_sum 24.5\n" +
+		"# EOF\n"
+	if b.String() != expected {
+		t.Fatalf("expected\n%s\nbut got\n%s", expected, b.String())
+	}
+}
+
+func TestWriteOpenMetricsCollision(t *testing.T) {
+	var p = NewPointPolicy(NewWindow(1))
+	var b = &bytes.Buffer{}
+	var metrics = map[string]*Metric{
+		"http.requests": From(p).Sum().Build(),
+		"http_requests": From(p).Max().Build(),
+	}
+	if err := WriteOpenMetrics(b, metrics, nil); err == nil {
+		t.Fatal("expected an error for names that are the same once sanitized")
+	}
+	metrics = map[string]*Metric{
+		"latency_count": From(p).Count().Build(),
+	}
+	var histograms = map[string]*HistogramMetric{
+		"latency": {Policy: p, Bounds: []float64{1}},
+	}
+	if err := WriteOpenMetrics(b, metrics, histograms); err == nil {
+		t.Fatal("expected an error for a gauge named after a sample of a histogram")
+	}
+	if b.Len() != 0 {
+		t.Fatalf("expected nothing to be written but got %q", b.String())
+	}
+}
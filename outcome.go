@@ -0,0 +1,89 @@
+package rolling
+
+import (
+	"sync"
+	"time"
+)
+
+// OutcomeTracker counts the successes and failures of an operation over a
+// rolling time window. Both counts are kept in windows that share a lock and
+// a clock so they always cover exactly the same period, which makes the
+// tracker suitable for circuit breaker style decisions.
+type OutcomeTracker struct {
+	successes *TimePolicy
+	failures  *TimePolicy
+	minimum   int
+	lock      *sync.Mutex
+}
+
+// NewOutcomeTracker generates an OutcomeTracker over a time window made of
+// the given number of buckets of the given duration. The success rate is not
+// reported until the window contains at least the given minimum number of
+// outcomes so that a handful of failures during a quiet period does not
+// appear as an outage.
+func NewOutcomeTracker(buckets int, bucketDuration time.Duration, minimum int, options ...TimePolicyOption) *OutcomeTracker {
+	return &OutcomeTracker{
+		successes: NewNoLockTimePolicy(NewWindow(buckets), bucketDuration, options...),
+		failures:  NewNoLockTimePolicy(NewWindow(buckets), bucketDuration, options...),
+		minimum:   minimum,
+		lock:      &sync.Mutex{},
+	}
+}
+
+// RecordSuccess records a successful outcome.
+func (t *OutcomeTracker) RecordSuccess() {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.successes.Append(1)
+}
+
+// RecordFailure records a failed outcome.
+func (t *OutcomeTracker) RecordFailure() {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.failures.Append(1)
+}
+
+// Record records a successful outcome if the given error is nil and a failed
+// outcome otherwise.
+func (t *OutcomeTracker) Record(err error) {
+	if err != nil {
+		t.RecordFailure()
+		return
+	}
+	t.RecordSuccess()
+}
+
+// Counts returns the number of successes and failures within the window.
+func (t *OutcomeTracker) Counts() (successes int, failures int) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	return t.successes.Len(), t.failures.Len()
+}
+
+// SuccessRate returns the fraction of outcomes within the window that were
+// successful and whether the number of outcomes met the minimum. A rate of one
+// is returned when the volume is below the minimum so that callers which
+// ignore the second value fail open.
+func (t *OutcomeTracker) SuccessRate() (float64, bool) {
+	var successes, failures = t.Counts()
+	var total = successes + failures
+	if total == 0 || total < t.minimum {
+		return 1, false
+	}
+	return float64(successes) / float64(total), true
+}
+
+// FailureRate returns the fraction of outcomes within the window that failed
+// and whether the number of outcomes met the minimum. A rate of zero is
+// returned when the volume is below the minimum.
+func (t *OutcomeTracker) FailureRate() (float64, bool) {
+	var rate, ok = t.SuccessRate()
+	if !ok {
+		return 0, false
+	}
+	return 1 - rate, true
+}
@@ -0,0 +1,40 @@
+package rolling
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestOutcomeTracker(t *testing.T) {
+	var c = &testClock{now: time.Unix(0, 0)}
+	var tracker = NewOutcomeTracker(10, time.Second, 4, WithClock(c))
+	tracker.RecordSuccess()
+	tracker.RecordFailure()
+	if rate, ok := tracker.SuccessRate(); ok || rate != 1 {
+		t.Fatalf("expected no rate below the minimum volume but got %f, %t", rate, ok)
+	}
+	if rate, ok := tracker.FailureRate(); ok || rate != 0 {
+		t.Fatalf("expected no rate below the minimum volume but got %f, %t", rate, ok)
+	}
+	tracker.Record(nil)
+	tracker.Record(errors.New("failed"))
+	if successes, failures := tracker.Counts(); successes != 2 || failures != 2 {
+		t.Fatalf("expected two of each outcome but got %d, %d", successes, failures)
+	}
+	if rate, ok := tracker.SuccessRate(); !ok || !floatEquals(rate, 0.5) {
+		t.Fatalf("expected a success rate of 0.5 but got %f, %t", rate, ok)
+	}
+
+	c.now = c.now.Add(5 * time.Second)
+	for x := 0; x < 4; x = x + 1 {
+		tracker.RecordFailure()
+	}
+	if rate, ok := tracker.FailureRate(); !ok || !floatEquals(rate, 0.75) {
+		t.Fatalf("expected a failure rate of 0.75 but got %f, %t", rate, ok)
+	}
+	c.now = c.now.Add(6 * time.Second)
+	if successes, failures := tracker.Counts(); successes != 0 || failures != 4 {
+		t.Fatalf("expected the earlier outcomes to expire but got %d, %d", successes, failures)
+	}
+}
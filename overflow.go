@@ -0,0 +1,85 @@
+package rolling
+
+// Overflow determines what a window does when appending a value would exceed
+// its limit on the number of values it may hold.
+type Overflow int
+
+const (
+	// DropNewest discards the value being appended.
+	DropNewest Overflow = iota
+	// EvictOldest removes a value from the oldest bucket that contains data
+	// to make room for the value being appended.
+	EvictOldest
+	// Downsample discards every other value from the bucket that contains
+	// the most values. This halves the resolution of that bucket while
+	// preserving the shape of its distribution. If no bucket contains more
+	// than one value then a value is evicted from the oldest bucket instead.
+	Downsample
+)
+
+// WithPointLimit caps the total number of values a TimePolicy may hold at any
+// one time. Each value occupies eight bytes so the limit also bounds the
+// memory used by the window during a burst of data. The given Overflow
+// determines what happens to values appended while the window is full.
+func WithPointLimit(points int, overflow Overflow) TimePolicyOption {
+	return func(o *timeOptions) {
+		o.pointLimit = points
+		o.overflow = overflow
+	}
+}
+
+// overflow makes room in a full window according to its Overflow mode. It
+// returns false if the value being appended should be discarded instead.
+func (w *TimePolicy) overflow(adjustedTime int64) bool {
+	var before = w.size
+	var ok bool
+	switch w.overflowMode {
+	case EvictOldest:
+		ok = w.evictOldest(adjustedTime)
+	case Downsample:
+		ok = w.downsampleLargest() || w.evictOldest(adjustedTime)
+	}
+	w.counters.dropped = w.counters.dropped + uint64(before-w.size)
+	if !ok {
+		w.counters.dropped = w.counters.dropped + 1
+	}
+	return ok
+}
+
+func (w *TimePolicy) evictOldest(adjustedTime int64) bool {
+	for age := w.ring.numberOfBuckets - 1; age >= 0; age = age - 1 {
+		var offset = w.ring.offset(adjustedTime - age)
+		var bucket = w.window[offset]
+		if len(bucket) > 0 {
+			// Values are appended in order so the first is the oldest. It is
+			// removed by copying the rest down, rather than reslicing, so the
+			// bucket keeps the start of its allocation.
+			copy(bucket, bucket[1:])
+			w.window[offset] = bucket[:len(bucket)-1]
+			w.size = w.size - 1
+			return true
+		}
+	}
+	return false
+}
+
+func (w *TimePolicy) downsampleLargest() bool {
+	var largest = 0
+	for offset, bucket := range w.window {
+		if len(bucket) > len(w.window[largest]) {
+			largest = offset
+		}
+	}
+	var bucket = w.window[largest]
+	if len(bucket) < 2 {
+		return false
+	}
+	var kept = 0
+	for x := 0; x < len(bucket); x = x + 2 {
+		bucket[kept] = bucket[x]
+		kept = kept + 1
+	}
+	w.window[largest] = bucket[:kept]
+	w.size = w.size - (len(bucket) - kept)
+	return true
+}
@@ -0,0 +1,81 @@
+package rolling
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimeWindowPointLimitDropNewest(t *testing.T) {
+	var c = &testClock{now: time.Unix(100, 0)}
+	var p = NewTimePolicy(NewWindow(3), time.Second, WithClock(c), WithPointLimit(4, DropNewest))
+	for x := 1; x <= 10; x = x + 1 {
+		p.Append(float64(x))
+	}
+	if result := p.Reduce(Sum); !floatEquals(result, 10) {
+		t.Fatalf("sum calculated incorrectly: %f versus %f", 10.0, result)
+	}
+	c.now = c.now.Add(time.Minute)
+	p.Append(5)
+	if p.Len() != 1 {
+		t.Fatalf("expired values should not count toward the limit but window has %d", p.Len())
+	}
+}
+
+func TestTimeWindowPointLimitEvictOldest(t *testing.T) {
+	var c = &testClock{now: time.Unix(100, 0)}
+	var p = NewTimePolicy(NewWindow(3), time.Second, WithClock(c), WithPointLimit(4, EvictOldest))
+	p.Append(1)
+	p.Append(1)
+	c.now = c.now.Add(time.Second)
+	p.Append(2)
+	p.Append(2)
+	p.Append(2)
+	p.Append(2)
+	if p.Len() != 4 {
+		t.Fatalf("expected 4 values but got %d", p.Len())
+	}
+	if result := p.Reduce(Sum); !floatEquals(result, 8) {
+		t.Fatalf("oldest values should have been evicted but summed to %f", result)
+	}
+}
+
+func TestTimeWindowPointLimitEvictOldestWithinBucket(t *testing.T) {
+	var c = &testClock{now: time.Unix(100, 0)}
+	var p = NewTimePolicy(NewWindow(3), time.Second, WithClock(c), WithPointLimit(3, EvictOldest))
+	p.Append(1)
+	p.Append(2)
+	p.Append(3)
+	c.now = c.now.Add(time.Second)
+	p.Append(4)
+	if oldest, _, _ := p.Oldest(); oldest != 2 {
+		t.Fatalf("expected the oldest value of the oldest bucket to be evicted but the oldest is %f", oldest)
+	}
+	if first := p.Reduce(First); first != 2 {
+		t.Fatalf("expected the remaining values to keep their order but the first is %f", first)
+	}
+	if result := p.Reduce(Sum); !floatEquals(result, 9) {
+		t.Fatalf("expected 2, 3, and 4 to remain but summed to %f", result)
+	}
+}
+
+func TestTimeWindowPointLimitDownsample(t *testing.T) {
+	var c = &testClock{now: time.Unix(100, 0)}
+	var p = NewTimePolicy(NewWindow(3), time.Second, WithClock(c), WithPointLimit(8, Downsample))
+	for x := 1; x <= 8; x = x + 1 {
+		p.Append(float64(x))
+	}
+	p.Append(9)
+	if p.Len() != 5 {
+		t.Fatalf("expected 5 values but got %d", p.Len())
+	}
+	if result := p.Reduce(Sum); !floatEquals(result, 1+3+5+7+9) {
+		t.Fatalf("sum calculated incorrectly: %f versus %f", 25.0, result)
+	}
+
+	var single = NewTimePolicy(NewWindow(3), time.Second, WithClock(c), WithPointLimit(1, Downsample))
+	single.Append(1)
+	single.Append(2)
+	if result := single.Reduce(Sum); !floatEquals(result, 2) {
+		t.Fatalf("sum calculated incorrectly: %f versus %f", 2.0, result)
+	}
+}
@@ -0,0 +1,74 @@
+package rolling
+
+import "sync"
+
+// Parallel returns an aggregating function that divides the values of a
+// window into, at most, the given number of parts of roughly equal size and
+// reduces each part concurrently using the partial function. The results of
+// the parts are then given to the merge function as a Window containing a
+// single bucket. Buckets are split between parts when needed so that even a
+// window with one very large bucket is divided evenly.
+//
+// Only aggregates that can be computed from the aggregates of their parts are
+// suitable. The sum of a window is the sum of the sums of its parts, for
+// example, and the count is also the sum of the counts:
+//
+//	p.Reduce(rolling.Parallel(8, rolling.Sum, rolling.Sum))
+//	p.Reduce(rolling.Parallel(8, rolling.Count, rolling.Sum))
+//	p.Reduce(rolling.Parallel(8, rolling.Max, rolling.Max))
+//
+// Starting goroutines has a cost of its own so this is only worthwhile for
+// windows containing a very large number of values. Parts that contain no
+// values are not reduced.
+func Parallel(workers int, partial func(w Window) float64, merge func(w Window) float64) func(w Window) float64 {
+	if workers < 1 {
+		workers = 1
+	}
+	return func(w Window) float64 {
+		var parts = splitWindow(w, workers)
+		var results = make([]float64, len(parts))
+		var wg = &sync.WaitGroup{}
+		for offset, part := range parts {
+			wg.Add(1)
+			go func(offset int, part Window) {
+				defer wg.Done()
+				results[offset] = partial(part)
+			}(offset, part)
+		}
+		wg.Wait()
+		return merge(Window{results})
+	}
+}
+
+// splitWindow divides the values of a window into, at most, the given number
+// of non-empty windows without copying any values.
+func splitWindow(w Window, parts int) []Window {
+	var total = int(Count(w))
+	if total < 1 {
+		return nil
+	}
+	var size = (total + parts - 1) / parts
+	var result = make([]Window, 0, parts)
+	var current = Window{}
+	var remaining = size
+	for _, bucket := range w {
+		for len(bucket) > 0 {
+			var take = len(bucket)
+			if take > remaining {
+				take = remaining
+			}
+			current = append(current, bucket[:take])
+			bucket = bucket[take:]
+			remaining = remaining - take
+			if remaining == 0 {
+				result = append(result, current)
+				current = Window{}
+				remaining = size
+			}
+		}
+	}
+	if len(current) > 0 {
+		result = append(result, current)
+	}
+	return result
+}
@@ -0,0 +1,48 @@
+package rolling
+
+import "testing"
+
+func TestParallel(t *testing.T) {
+	var w = Window{make([]float64, 0, 1000), {}, {1, 2, 3}}
+	for x := 1; x <= 1000; x = x + 1 {
+		w[0] = append(w[0], float64(x))
+	}
+	for _, workers := range []int{0, 1, 3, 8, 2000} {
+		if result := Parallel(workers, Sum, Sum)(w); !floatEquals(result, Sum(w)) {
+			t.Fatalf("parallel sum with %d workers calculated incorrectly: %f versus %f", workers, Sum(w), result)
+		}
+		if result := Parallel(workers, Count, Sum)(w); !floatEquals(result, Count(w)) {
+			t.Fatalf("parallel count with %d workers calculated incorrectly: %f versus %f", workers, Count(w), result)
+		}
+		if result := Parallel(workers, Max, Max)(w); !floatEquals(result, 1000) {
+			t.Fatalf("parallel max with %d workers calculated incorrectly: %f versus %f", workers, 1000.0, result)
+		}
+	}
+	if result := Parallel(4, Sum, Sum)(Window{{}, {}}); result != 0 {
+		t.Fatalf("expected zero for an empty window but got %f", result)
+	}
+}
+
+func TestSplitWindow(t *testing.T) {
+	var parts = splitWindow(Window{{1, 2, 3, 4, 5}, {6, 7}}, 3)
+	if len(parts) != 3 {
+		t.Fatalf("expected 3 parts but got %d", len(parts))
+	}
+	for offset, expected := range []float64{3, 3, 1} {
+		if result := Count(parts[offset]); !floatEquals(result, expected) {
+			t.Fatalf("part %d has %f values rather than %f", offset, result, expected)
+		}
+	}
+}
+
+func BenchmarkParallelSum(b *testing.B) {
+	var w = NewPreallocatedWindow(100, 10000)
+	for offset := range w {
+		w[offset] = w[offset][:cap(w[offset])]
+	}
+	var f = Parallel(8, Sum, Sum)
+	b.ResetTimer()
+	for n := 0; n < b.N; n = n + 1 {
+		_ = f(w)
+	}
+}
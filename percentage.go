@@ -0,0 +1,63 @@
+package rolling
+
+type percentage struct {
+	inverted bool
+	clamped  bool
+}
+
+// PercentageOption is used to modify the behavior of Percentage.
+type PercentageOption func(*percentage)
+
+// WithInverted measures the distance of the value below the upper bound
+// rather than above the lower bound. An inverted percentage is one when the
+// value is at the lower bound and zero when it is at the upper bound.
+func WithInverted() PercentageOption {
+	return func(p *percentage) {
+		p.inverted = true
+	}
+}
+
+// WithClamp restricts the percentage to the range of zero to one. Without it,
+// values beyond the bounds produce percentages that are less than zero or
+// greater than one.
+func WithClamp() PercentageOption {
+	return func(p *percentage) {
+		p.clamped = true
+	}
+}
+
+// Percentage returns an aggregating function that reduces the window with the
+// given function and reports where the result falls between the lower and
+// upper bounds as a fraction of that range. A result at the lower bound is
+// zero and a result at the upper bound is one. When the bounds are equal the
+// percentage is one if the result is at or above them and zero otherwise.
+func Percentage(f func(w Window) float64, lower float64, upper float64, options ...PercentageOption) func(w Window) float64 {
+	var config = &percentage{}
+	for _, option := range options {
+		option(config)
+	}
+	return func(w Window) float64 {
+		var value = f(w)
+		var result float64
+		switch {
+		case upper == lower && value >= upper:
+			result = 1
+		case upper == lower:
+			result = 0
+		default:
+			result = (value - lower) / (upper - lower)
+		}
+		if config.inverted {
+			result = 1 - result
+		}
+		if config.clamped {
+			if result < 0 {
+				result = 0
+			}
+			if result > 1 {
+				result = 1
+			}
+		}
+		return result
+	}
+}
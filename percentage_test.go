@@ -0,0 +1,34 @@
+package rolling
+
+import "testing"
+
+func TestPercentage(t *testing.T) {
+	var w = NewWindow(4)
+	var p = NewPointPolicy(w)
+	for _, v := range []float64{10, 20, 30, 40} {
+		p.Append(v)
+	}
+
+	var tc = []struct {
+		name     string
+		lower    float64
+		upper    float64
+		options  []PercentageOption
+		expected float64
+	}{
+		{name: "within", lower: 0, upper: 100, expected: 0.25},
+		{name: "inverted", lower: 0, upper: 100, options: []PercentageOption{WithInverted()}, expected: 0.75},
+		{name: "above", lower: 0, upper: 20, expected: 1.25},
+		{name: "above clamped", lower: 0, upper: 20, options: []PercentageOption{WithClamp()}, expected: 1},
+		{name: "below inverted", lower: 0, upper: 20, options: []PercentageOption{WithInverted()}, expected: -0.25},
+		{name: "below inverted clamped", lower: 0, upper: 20, options: []PercentageOption{WithInverted(), WithClamp()}, expected: 0},
+		{name: "equal bounds at", lower: 25, upper: 25, expected: 1},
+		{name: "equal bounds below", lower: 30, upper: 30, expected: 0},
+	}
+	for _, c := range tc {
+		var result = p.Reduce(Percentage(Avg, c.lower, c.upper, c.options...))
+		if !floatEquals(result, c.expected) {
+			t.Fatalf("%s: percentage calculated incorrectly: %f versus %f", c.name, c.expected, result)
+		}
+	}
+}
@@ -7,35 +7,232 @@ import "sync"
 type PointPolicy struct {
 	windowSize int
 	window     Window
+	values     []float64
 	offset     int
-	lock       *sync.RWMutex
+	count      int
+	ordered    Window
+	lock       rwLocker
 }
 
 // NewPointPolicy generates a Policy that operates on a rolling set of
 // input points. The number of points is determined by the size of the given
 // window. Each bucket will contain, at most, one data point when the window
 // is full.
+//
+// The points are stored in a single, contiguous slice and each bucket of the
+// window is a view of one element of it. Any values already in the buckets
+// of the given window are copied into the slice.
 func NewPointPolicy(window Window) *PointPolicy {
-	var p = &PointPolicy{
+	var values = make([]float64, len(window))
+	for offset, bucket := range window {
+		if len(bucket) > 0 {
+			values[offset] = bucket[0]
+		}
+	}
+	return &PointPolicy{
 		windowSize: len(window),
-		window:     window,
+		window:     flatWindow(window, values),
+		values:     values,
 		lock:       &sync.RWMutex{},
 	}
-	for offset, bucket := range window {
-		if len(bucket) < 1 {
-			window[offset] = make([]float64, 1)
-		}
+}
+
+// flatWindow points each bucket of the window at the matching element of the
+// given values and returns the window.
+func flatWindow(window Window, values []float64) Window {
+	for offset := range window {
+		window[offset] = values[offset : offset+1 : offset+1]
 	}
-	return p
+	return window
 }
 
 // Append a value to the window.
 func (w *PointPolicy) Append(value float64) {
+	w.AppendEvict(value)
+}
+
+// AppendEvict appends a value to the window and returns the value that it
+// replaced. The boolean result is false if the replaced point had not yet
+// received a value, such as while the window is first filling. This allows
+// callers to maintain their own running aggregates by adding each new value
+// and removing each evicted one.
+func (w *PointPolicy) AppendEvict(value float64) (float64, bool) {
 	w.lock.Lock()
 	defer w.lock.Unlock()
 
+	var evicted = w.window[w.offset][0]
+	var ok = w.count == w.windowSize
 	w.window[w.offset][0] = value
 	w.offset = (w.offset + 1) % w.windowSize
+	if w.count < w.windowSize {
+		w.count = w.count + 1
+	}
+	if !ok {
+		return 0, false
+	}
+	return evicted, true
+}
+
+// Size returns the number of points in the window.
+func (w *PointPolicy) Size() int {
+	w.lock.RLock()
+	defer w.lock.RUnlock()
+
+	return w.windowSize
+}
+
+// Len returns the number of values that have been appended to the window, up
+// to the size of the window. Points that have not yet received a value are
+// not counted even though they contain placeholder zeros.
+func (w *PointPolicy) Len() int {
+	w.lock.RLock()
+	defer w.lock.RUnlock()
+
+	return w.count
+}
+
+// Oldest returns the oldest value appended to the window that is still
+// within it and whether the window contains any appended values.
+func (w *PointPolicy) Oldest() (float64, bool) {
+	w.lock.RLock()
+	defer w.lock.RUnlock()
+
+	if w.count < 1 {
+		return 0, false
+	}
+	return w.window[(w.offset-w.count+w.windowSize)%w.windowSize][0], true
+}
+
+// Newest returns the most recent value appended to the window and whether the
+// window contains any appended values.
+func (w *PointPolicy) Newest() (float64, bool) {
+	w.lock.RLock()
+	defer w.lock.RUnlock()
+
+	if w.count < 1 {
+		return 0, false
+	}
+	return w.window[(w.offset-1+w.windowSize)%w.windowSize][0], true
+}
+
+// Fill returns the fraction of the window, between 0 and 1, that contains
+// appended values. Windows that have not yet received enough values to fill
+// every point contain placeholder zeros which may skew the results of a
+// reduction.
+func (w *PointPolicy) Fill() float64 {
+	w.lock.RLock()
+	defer w.lock.RUnlock()
+
+	if w.windowSize < 1 {
+		return 0
+	}
+	return float64(w.count) / float64(w.windowSize)
+}
+
+// Resize changes the number of points in the window. The most recently
+// appended values are preserved, up to the new size, and any new points
+// contain placeholder zeros until values are appended. The window given to
+// NewPointPolicy is replaced and no longer used after a resize. Sizes less
+// than one are ignored.
+func (w *PointPolicy) Resize(size int) {
+	if size < 1 {
+		return
+	}
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	var keep = w.count
+	if keep > size {
+		keep = size
+	}
+	var values = make([]float64, size)
+	// Copy the kept values such that the oldest is at the start of the new
+	// window and the next append follows the newest.
+	for x := 0; x < keep; x = x + 1 {
+		var source = (w.offset - keep + x + w.windowSize) % w.windowSize
+		values[x] = w.values[source]
+	}
+	w.window = flatWindow(NewWindow(size), values)
+	w.values = values
+	w.windowSize = size
+	w.offset = keep % size
+	w.count = keep
+}
+
+// Iterate calls the given function with each value appended to the window,
+// from the oldest to the newest, until the function returns false. Points
+// that have not yet received a value are skipped.
+func (w *PointPolicy) Iterate(f func(float64) bool) {
+	w.lock.RLock()
+	defer w.lock.RUnlock()
+
+	for x := 0; x < w.count; x = x + 1 {
+		if !f(w.window[(w.offset-w.count+x+w.windowSize)%w.windowSize][0]) {
+			return
+		}
+	}
+}
+
+// IterateReverse calls the given function with each value appended to the
+// window, from the newest to the oldest, until the function returns false.
+// Points that have not yet received a value are skipped.
+func (w *PointPolicy) IterateReverse(f func(float64) bool) {
+	w.lock.RLock()
+	defer w.lock.RUnlock()
+
+	for x := 1; x <= w.count; x = x + 1 {
+		if !f(w.window[(w.offset-x+w.windowSize)%w.windowSize][0]) {
+			return
+		}
+	}
+}
+
+// ReduceOrdered reduces the window to a single value using a reduction
+// function that is given the appended values in order. The first bucket of the
+// window contains the oldest value and the last bucket contains the newest.
+// Points that have not yet received a value are omitted. This is useful for
+// reductions, such as the change from the first value to the last, that
+// depend on the order of the values.
+func (w *PointPolicy) ReduceOrdered(f func(Window) float64) float64 {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	w.ordered = w.ordered[:0]
+	for x := 0; x < w.count; x = x + 1 {
+		w.ordered = append(w.ordered, w.window[(w.offset-w.count+x+w.windowSize)%w.windowSize])
+	}
+	return f(w.ordered)
+}
+
+// Clone returns a deep copy of the policy and its window. The copy is made
+// while the lock of the policy is held and does not share any state with the
+// original.
+func (w *PointPolicy) Clone() *PointPolicy {
+	w.lock.RLock()
+	defer w.lock.RUnlock()
+
+	var values = append([]float64(nil), w.values...)
+	return &PointPolicy{
+		windowSize: w.windowSize,
+		window:     flatWindow(NewWindow(w.windowSize), values),
+		values:     values,
+		offset:     w.offset,
+		count:      w.count,
+		lock:       &sync.RWMutex{},
+	}
+}
+
+// ReduceValues reduces the window to a single value using a reduction function
+// that is given every point of the window as one contiguous slice. Iterating a
+// flat slice is friendlier to CPU caches than iterating a Window. The points
+// are in the same order as the buckets of the window and points that have not
+// yet received a value contain placeholder zeros. The slice is owned by the
+// window and must not be modified or retained after the function returns.
+func (w *PointPolicy) ReduceValues(f func([]float64) float64) float64 {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	return f(w.values)
 }
 
 // Reduce the window to a single value using a reduction function.
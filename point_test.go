@@ -85,3 +85,242 @@ func BenchmarkPointWindow(b *testing.B) {
 		}
 	}
 }
+
+func TestPointWindowFill(t *testing.T) {
+	var p = NewPointPolicy(NewWindow(4))
+	if !floatEquals(p.Fill(), 0) {
+		t.Fatalf("expected empty window but got %f", p.Fill())
+	}
+	p.Append(1)
+	if !floatEquals(p.Fill(), .25) {
+		t.Fatalf("expected quarter full window but got %f", p.Fill())
+	}
+	for x := 0; x < 10; x = x + 1 {
+		p.Append(1)
+	}
+	if !floatEquals(p.Fill(), 1) {
+		t.Fatalf("expected full window but got %f", p.Fill())
+	}
+}
+
+func TestPointWindowResize(t *testing.T) {
+	var p = NewPointPolicy(NewWindow(4))
+	for x := 1; x <= 6; x = x + 1 {
+		p.Append(float64(x))
+	}
+	p.Resize(2)
+	if p.Size() != 2 {
+		t.Fatalf("expected size 2 but got %d", p.Size())
+	}
+	if result := p.Reduce(Sum); !floatEquals(result, 11) {
+		t.Fatalf("shrunk window should keep the newest values but summed to %f", result)
+	}
+	if !floatEquals(p.Fill(), 1) {
+		t.Fatalf("expected full window but got %f", p.Fill())
+	}
+	p.Append(7)
+	if result := p.Reduce(Sum); !floatEquals(result, 13) {
+		t.Fatalf("append after shrink should evict the oldest value but summed to %f", result)
+	}
+
+	p.Resize(4)
+	if result := p.Reduce(Sum); !floatEquals(result, 13) {
+		t.Fatalf("grown window should keep all values but summed to %f", result)
+	}
+	if !floatEquals(p.Fill(), .5) {
+		t.Fatalf("expected half full window but got %f", p.Fill())
+	}
+	p.Append(8)
+	p.Append(9)
+	p.Append(10)
+	if result := p.Reduce(Sum); !floatEquals(result, 34) {
+		t.Fatalf("sum calculated incorrectly: %f versus %f", 34.0, result)
+	}
+	p.Resize(0)
+	if result := p.Reduce(Count); !floatEquals(result, 4) {
+		t.Fatalf("invalid resize should be ignored but window has %f points", result)
+	}
+}
+
+func TestPointWindowLen(t *testing.T) {
+	var p = NewPointPolicy(NewWindow(3))
+	if p.Len() != 0 {
+		t.Fatalf("expected no values but got %d", p.Len())
+	}
+	p.Append(1)
+	p.Append(2)
+	if p.Len() != 2 {
+		t.Fatalf("expected 2 values but got %d", p.Len())
+	}
+	p.Append(3)
+	p.Append(4)
+	if p.Len() != 3 {
+		t.Fatalf("expected 3 values but got %d", p.Len())
+	}
+}
+
+func TestPointWindowOldestNewest(t *testing.T) {
+	var p = NewPointPolicy(NewWindow(3))
+	if _, ok := p.Oldest(); ok {
+		t.Fatal("empty window should not have an oldest value")
+	}
+	if _, ok := p.Newest(); ok {
+		t.Fatal("empty window should not have a newest value")
+	}
+	p.Append(1)
+	p.Append(2)
+	if v, ok := p.Oldest(); !ok || !floatEquals(v, 1) {
+		t.Fatalf("expected oldest value of 1 but got %f", v)
+	}
+	if v, ok := p.Newest(); !ok || !floatEquals(v, 2) {
+		t.Fatalf("expected newest value of 2 but got %f", v)
+	}
+	p.Append(3)
+	p.Append(4)
+	if v, ok := p.Oldest(); !ok || !floatEquals(v, 2) {
+		t.Fatalf("expected oldest value of 2 but got %f", v)
+	}
+	if v, ok := p.Newest(); !ok || !floatEquals(v, 4) {
+		t.Fatalf("expected newest value of 4 but got %f", v)
+	}
+}
+
+func TestPointWindowAppendEvict(t *testing.T) {
+	var p = NewPointPolicy(NewWindow(2))
+	if v, ok := p.AppendEvict(1); ok {
+		t.Fatalf("nothing should be evicted from an empty window but got %f", v)
+	}
+	if v, ok := p.AppendEvict(2); ok {
+		t.Fatalf("nothing should be evicted from a filling window but got %f", v)
+	}
+	if v, ok := p.AppendEvict(3); !ok || !floatEquals(v, 1) {
+		t.Fatalf("expected to evict 1 but got %f", v)
+	}
+	var sum = 0.0
+	for x := 4; x < 10; x = x + 1 {
+		var evicted, _ = p.AppendEvict(float64(x))
+		sum = sum + float64(x) - evicted
+	}
+	if result := p.Reduce(Sum); !floatEquals(result, sum+5) {
+		t.Fatalf("running sum calculated incorrectly: %f versus %f", result, sum+5)
+	}
+}
+
+func TestPointWindowIterate(t *testing.T) {
+	var p = NewPointPolicy(NewWindow(3))
+	var values []float64
+	p.Iterate(func(v float64) bool {
+		values = append(values, v)
+		return true
+	})
+	if len(values) != 0 {
+		t.Fatalf("empty window should have no values but got %v", values)
+	}
+	for x := 1; x <= 5; x = x + 1 {
+		p.Append(float64(x))
+	}
+	p.Iterate(func(v float64) bool {
+		values = append(values, v)
+		return len(values) < 2
+	})
+	if len(values) != 2 || values[0] != 3 || values[1] != 4 {
+		t.Fatalf("expected to stop after the two oldest values but got %v", values)
+	}
+}
+
+func TestPointWindowReduceOrdered(t *testing.T) {
+	var p = NewPointPolicy(NewWindow(4))
+	var delta = func(w Window) float64 {
+		if len(w) < 1 {
+			return 0
+		}
+		return w[len(w)-1][0] - w[0][0]
+	}
+	for x := 1; x <= 3; x = x + 1 {
+		p.Append(float64(x * 10))
+	}
+	if result := p.ReduceOrdered(delta); !floatEquals(result, 20) {
+		t.Fatalf("delta calculated incorrectly: %f versus %f", 20.0, result)
+	}
+	for x := 4; x <= 6; x = x + 1 {
+		p.Append(float64(x * 10))
+	}
+	if result := p.ReduceOrdered(delta); !floatEquals(result, 30) {
+		t.Fatalf("delta calculated incorrectly: %f versus %f", 30.0, result)
+	}
+	if result := p.ReduceOrdered(Count); !floatEquals(result, 4) {
+		t.Fatalf("count calculated incorrectly: %f versus %f", 4.0, result)
+	}
+}
+
+func TestPointWindowIterateReverse(t *testing.T) {
+	var p = NewPointPolicy(NewWindow(4))
+	for x := 1; x <= 6; x = x + 1 {
+		p.Append(float64(x))
+	}
+	var values []float64
+	p.IterateReverse(func(v float64) bool {
+		values = append(values, v)
+		return true
+	})
+	if len(values) != 4 || values[0] != 6 || values[3] != 3 {
+		t.Fatalf("expected newest to oldest values but got %v", values)
+	}
+	var above = 0
+	p.IterateReverse(func(v float64) bool {
+		if v < 5 {
+			return false
+		}
+		above = above + 1
+		return true
+	})
+	if above != 2 {
+		t.Fatalf("expected 2 recent values above the threshold but got %d", above)
+	}
+}
+
+func TestPointWindowClone(t *testing.T) {
+	var p = NewPointPolicy(NewWindow(3))
+	p.Append(1)
+	p.Append(2)
+	var c = p.Clone()
+	p.Append(10)
+	c.Append(3)
+	c.Append(4)
+	if result := p.Reduce(Sum); !floatEquals(result, 13) {
+		t.Fatalf("original sum calculated incorrectly: %f versus %f", 13.0, result)
+	}
+	if result := c.Reduce(Sum); !floatEquals(result, 9) {
+		t.Fatalf("clone sum calculated incorrectly: %f versus %f", 9.0, result)
+	}
+}
+
+func TestPointWindowReduceValues(t *testing.T) {
+	var w = NewWindow(3)
+	var p = NewPointPolicy(w)
+	for x := 1; x <= 4; x = x + 1 {
+		p.Append(float64(x))
+	}
+	p.ReduceValues(func(values []float64) float64 {
+		var expected = []float64{4, 2, 3}
+		for offset, value := range values {
+			if value != expected[offset] {
+				t.Fatalf("expected values %v but got %v", expected, values)
+			}
+		}
+		return 0
+	})
+	if w[0][0] != 4 {
+		t.Fatalf("expected the given window to share storage with the policy but got %v", w)
+	}
+	p.Resize(5)
+	p.Append(5)
+	if result := p.ReduceValues(func(values []float64) float64 { return Sum(Window{values}) }); !floatEquals(result, 14) {
+		t.Fatalf("sum calculated incorrectly after resize: %f versus %f", 14.0, result)
+	}
+	var c = p.Clone()
+	p.Append(100)
+	if result := c.ReduceValues(func(values []float64) float64 { return Sum(Window{values}) }); !floatEquals(result, 14) {
+		t.Fatalf("expected a clone not to share storage but got a sum of %f", result)
+	}
+}
@@ -0,0 +1,156 @@
+package rolling
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"time"
+)
+
+// The wire types of the Protocol Buffers encoding that are used by, or must
+// be skipped when decoding, the messages in rolling.proto.
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+var errInvalidProto = errors.New("rolling: invalid protocol buffer encoding")
+
+// MarshalBinary encodes the snapshot as the TimeSnapshot message defined in
+// rolling.proto so that it may be decoded by any Protocol Buffers library.
+func (s *TimeSnapshot) MarshalBinary() ([]byte, error) {
+	var b []byte
+	if s.BucketDuration != 0 {
+		b = appendVarintField(b, 1, uint64(s.BucketDuration))
+	}
+	var bucket []byte
+	for _, bs := range s.Buckets {
+		bucket = bucket[:0]
+		if !bs.Start.IsZero() {
+			bucket = appendVarintField(bucket, 1, uint64(bs.Start.UnixNano()))
+		}
+		if len(bs.Values) > 0 {
+			bucket = appendTag(bucket, 2, wireBytes)
+			bucket = appendUvarint(bucket, uint64(8*len(bs.Values)))
+			for _, v := range bs.Values {
+				bucket = appendFixed64(bucket, math.Float64bits(v))
+			}
+		}
+		b = appendTag(b, 2, wireBytes)
+		b = appendUvarint(b, uint64(len(bucket)))
+		b = append(b, bucket...)
+	}
+	return b, nil
+}
+
+// UnmarshalBinary decodes the snapshot from the TimeSnapshot message defined
+// in rolling.proto. Fields that are not defined in the message are ignored.
+func (s *TimeSnapshot) UnmarshalBinary(b []byte) error {
+	*s = TimeSnapshot{}
+	return decodeFields(b, func(field uint64, wire uint64, value uint64, data []byte) error {
+		switch {
+		case field == 1 && wire == wireVarint:
+			s.BucketDuration = Duration(int64(value))
+		case field == 2 && wire == wireBytes:
+			var bs, err = decodeBucket(data)
+			if err != nil {
+				return err
+			}
+			s.Buckets = append(s.Buckets, bs)
+		}
+		return nil
+	})
+}
+
+func decodeBucket(b []byte) (BucketSnapshot, error) {
+	var bs = BucketSnapshot{Values: []float64{}}
+	var err = decodeFields(b, func(field uint64, wire uint64, value uint64, data []byte) error {
+		switch {
+		case field == 1 && wire == wireVarint:
+			bs.Start = time.Unix(0, int64(value))
+		case field == 2 && wire == wireFixed64:
+			bs.Values = append(bs.Values, math.Float64frombits(value))
+		case field == 2 && wire == wireBytes:
+			if len(data)%8 != 0 {
+				return errInvalidProto
+			}
+			for len(data) > 0 {
+				bs.Values = append(bs.Values, math.Float64frombits(binary.LittleEndian.Uint64(data)))
+				data = data[8:]
+			}
+		}
+		return nil
+	})
+	return bs, err
+}
+
+func appendUvarint(b []byte, v uint64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	return append(b, buf[:binary.PutUvarint(buf[:], v)]...)
+}
+
+func appendFixed64(b []byte, v uint64) []byte {
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], v)
+	return append(b, buf[:]...)
+}
+
+func appendTag(b []byte, field uint64, wire uint64) []byte {
+	return appendUvarint(b, field<<3|wire)
+}
+
+func appendVarintField(b []byte, field uint64, value uint64) []byte {
+	return appendUvarint(appendTag(b, field, wireVarint), value)
+}
+
+// decodeFields calls the given function with each field of an encoded
+// message. Varint and fixed width fields are given as a value while length
+// delimited fields are given as data.
+func decodeFields(b []byte, f func(field uint64, wire uint64, value uint64, data []byte) error) error {
+	for len(b) > 0 {
+		var tag, n = binary.Uvarint(b)
+		if n <= 0 {
+			return errInvalidProto
+		}
+		b = b[n:]
+		var field, wire = tag >> 3, tag & 7
+		var value uint64
+		var data []byte
+		switch wire {
+		case wireVarint:
+			value, n = binary.Uvarint(b)
+			if n <= 0 {
+				return errInvalidProto
+			}
+			b = b[n:]
+		case wireFixed64:
+			if len(b) < 8 {
+				return errInvalidProto
+			}
+			value = binary.LittleEndian.Uint64(b)
+			b = b[8:]
+		case wireFixed32:
+			if len(b) < 4 {
+				return errInvalidProto
+			}
+			value = uint64(binary.LittleEndian.Uint32(b))
+			b = b[4:]
+		case wireBytes:
+			var length uint64
+			length, n = binary.Uvarint(b)
+			if n <= 0 || uint64(len(b)-n) < length {
+				return errInvalidProto
+			}
+			data = b[n : n+int(length)]
+			b = b[n+int(length):]
+		default:
+			return errInvalidProto
+		}
+		if err := f(field, wire, value, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
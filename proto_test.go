@@ -0,0 +1,80 @@
+package rolling
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestTimeSnapshotProtoEncoding(t *testing.T) {
+	var s = &TimeSnapshot{
+		BucketDuration: Duration(time.Second),
+		Buckets:        []BucketSnapshot{{Start: time.Unix(0, 5), Values: []float64{1}}},
+	}
+	var b, err = s.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var expected = []byte{
+		0x08, 0x80, 0x94, 0xeb, 0xdc, 0x03, // bucket_duration_nanos = 1e9
+		0x12, 0x0c, // buckets, 12 bytes
+		0x08, 0x05, // start_unix_nanos = 5
+		0x12, 0x08, 0, 0, 0, 0, 0, 0, 0xf0, 0x3f, // packed values = [1]
+	}
+	if !bytes.Equal(b, expected) {
+		t.Fatalf("expected % x but got % x", expected, b)
+	}
+}
+
+func TestTimeSnapshotProtoRoundTrip(t *testing.T) {
+	var c = &testClock{now: time.Unix(100, 0)}
+	var p = NewTimePolicy(NewWindow(3), time.Second, WithClock(c))
+	p.Append(1.5)
+	c.now = c.now.Add(2 * time.Second)
+	p.Append(-2)
+	p.Append(3)
+
+	var s = p.Snapshot()
+	var b, err = s.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded = &TimeSnapshot{}
+	if err = decoded.UnmarshalBinary(b); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.BucketDuration != s.BucketDuration || len(decoded.Buckets) != len(s.Buckets) {
+		t.Fatalf("expected %+v but got %+v", s, decoded)
+	}
+	for offset, bucket := range s.Buckets {
+		if !decoded.Buckets[offset].Start.Equal(bucket.Start) || len(decoded.Buckets[offset].Values) != len(bucket.Values) {
+			t.Fatalf("expected %+v but got %+v", bucket, decoded.Buckets[offset])
+		}
+	}
+	if result := decoded.Reduce(Sum); !floatEquals(result, 2.5) {
+		t.Fatalf("decoded sum calculated incorrectly: %f versus %f", 2.5, result)
+	}
+}
+
+func TestTimeSnapshotProtoCompatibility(t *testing.T) {
+	// Unpacked values and unknown fields are both valid encodings.
+	var b = []byte{
+		0x18, 0x01, // unknown varint field 3
+		0x12, 0x12,
+		0x11, 0, 0, 0, 0, 0, 0, 0xf0, 0x3f, // values = 1, unpacked
+		0x11, 0, 0, 0, 0, 0, 0, 0, 0x40, // values = 2, unpacked
+		0x1d, 0, 0, 0, 0, // unknown fixed32 field 3
+	}
+	var decoded = &TimeSnapshot{}
+	if err := decoded.UnmarshalBinary(b); err != nil {
+		t.Fatal(err)
+	}
+	if result := decoded.Reduce(Sum); !floatEquals(result, 3) {
+		t.Fatalf("decoded sum calculated incorrectly: %f versus %f", 3.0, result)
+	}
+	for _, invalid := range [][]byte{{0x08}, {0x12, 0x05, 0x00}, {0x12, 0x02, 0x12, 0x03}, {0x0b}} {
+		if err := decoded.UnmarshalBinary(invalid); err == nil {
+			t.Fatalf("expected an error decoding % x", invalid)
+		}
+	}
+}
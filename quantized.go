@@ -0,0 +1,153 @@
+package rolling
+
+import (
+	"encoding/binary"
+	"math"
+	"sync"
+	"time"
+)
+
+// Quantization is the width of the fixed point integer used to store each
+// value of a QuantizedPolicy.
+type Quantization int
+
+const (
+	// Quantize16 stores each value in two bytes, a quarter of the memory of
+	// a float64, with a range of -32768 to 32767 times the scale.
+	Quantize16 Quantization = 2
+	// Quantize32 stores each value in four bytes, half of the memory of a
+	// float64, with a range of roughly plus or minus two billion times the
+	// scale.
+	Quantize32 Quantization = 4
+)
+
+// QuantizedPolicy is a rolling time window, like TimePolicy, that stores each
+// value as a fixed point integer rather than a float64. Each value is rounded
+// to the nearest multiple of a scale, such as one microsecond for latencies
+// recorded in seconds, and values beyond the range of the integer are clamped
+// to it. This greatly reduces the memory of very large windows whose values
+// do not need the full precision of a float64.
+type QuantizedPolicy struct {
+	width   int
+	scale   float64
+	min     float64
+	max     float64
+	buckets [][]byte
+	ring    ring
+	size    int
+	clock   Clock
+	lock    *sync.Mutex
+}
+
+// NewQuantizedPolicy generates a QuantizedPolicy with the given number of
+// buckets, each covering the given duration, that stores values as integers
+// of the given width in units of the given scale. A width other than
+// Quantize16 is treated as Quantize32. Only the WithClock and WithAlignment
+// options apply to a QuantizedPolicy.
+func NewQuantizedPolicy(buckets int, bucketDuration time.Duration, width Quantization, scale float64, options ...TimePolicyOption) *QuantizedPolicy {
+	var o = newTimeOptions(options)
+	var min, max = float64(math.MinInt32), float64(math.MaxInt32)
+	if width == Quantize16 {
+		min, max = math.MinInt16, math.MaxInt16
+	} else {
+		width = Quantize32
+	}
+	return &QuantizedPolicy{
+		width:   int(width),
+		scale:   scale,
+		min:     min,
+		max:     max,
+		buckets: make([][]byte, buckets),
+		ring:    newTimeRing(buckets, bucketDuration, o.originNano),
+		clock:   o.clock,
+		lock:    &sync.Mutex{},
+	}
+}
+
+// encode appends the fixed point form of the value to the bucket.
+func (w *QuantizedPolicy) encode(bucket []byte, value float64) []byte {
+	var q = math.Round(value / w.scale)
+	switch {
+	case q < w.min || math.IsInf(q, -1):
+		q = w.min
+	case q > w.max || math.IsInf(q, 1) || math.IsNaN(q):
+		q = w.max
+	}
+	if w.width == int(Quantize16) {
+		var b [2]byte
+		binary.LittleEndian.PutUint16(b[:], uint16(int16(q)))
+		return append(bucket, b[:]...)
+	}
+	var b [4]byte
+	binary.LittleEndian.PutUint32(b[:], uint32(int32(q)))
+	return append(bucket, b[:]...)
+}
+
+// decode appends every value of the bucket to the given slice.
+func (w *QuantizedPolicy) decode(bucket []byte, values []float64) []float64 {
+	for x := 0; x < len(bucket); x = x + w.width {
+		var q float64
+		if w.width == int(Quantize16) {
+			q = float64(int16(binary.LittleEndian.Uint16(bucket[x:])))
+		} else {
+			q = float64(int32(binary.LittleEndian.Uint32(bucket[x:])))
+		}
+		values = append(values, q*w.scale)
+	}
+	return values
+}
+
+func (w *QuantizedPolicy) clear(offset int) {
+	w.size = w.size - len(w.buckets[offset])/w.width
+	w.buckets[offset] = w.buckets[offset][:0]
+}
+
+// AppendWithTimestamp same as Append but with timestamp as parameter. Values
+// older than the window are discarded.
+func (w *QuantizedPolicy) AppendWithTimestamp(value float64, timestamp time.Time) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	var adjustedTime = w.ring.index(timestamp)
+	w.ring.advance(adjustedTime, w.clear)
+	if !w.ring.contains(adjustedTime) {
+		return
+	}
+	var offset = w.ring.offset(adjustedTime)
+	w.buckets[offset] = w.encode(w.buckets[offset], value)
+	w.size = w.size + 1
+}
+
+// Append a value to the window.
+func (w *QuantizedPolicy) Append(value float64) {
+	w.AppendWithTimestamp(value, w.clock.Now())
+}
+
+// Len returns the number of values within the window.
+func (w *QuantizedPolicy) Len() int {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	w.ring.advance(w.ring.index(w.clock.Now()), w.clear)
+	return w.size
+}
+
+// Reduce the window to a single value using a reduction function. The values
+// are decoded into a temporary window, from the oldest bucket to the newest,
+// that is released afterwards so that memory is only saved while the values
+// are at rest.
+func (w *QuantizedPolicy) Reduce(f func(Window) float64) float64 {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	var adjustedTime = w.ring.index(w.clock.Now())
+	w.ring.advance(adjustedTime, w.clear)
+	var values = make([]float64, 0, w.size)
+	var window = make(Window, 0, len(w.buckets))
+	for age := w.ring.numberOfBuckets - 1; age >= 0; age = age - 1 {
+		var start = len(values)
+		values = w.decode(w.buckets[w.ring.offset(adjustedTime-age)], values)
+		window = append(window, values[start:len(values):len(values)])
+	}
+	return f(window)
+}
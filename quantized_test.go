@@ -0,0 +1,55 @@
+package rolling
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestQuantizedPolicy(t *testing.T) {
+	var c = &testClock{now: time.Unix(0, 0)}
+	var p = NewQuantizedPolicy(3, time.Second, Quantize32, 0.001, WithClock(c))
+	p.Append(1.2344)
+	p.Append(-2.5)
+	c.now = c.now.Add(time.Second)
+	p.Append(3)
+	if n := p.Len(); n != 3 {
+		t.Fatalf("expected 3 values but got %d", n)
+	}
+	if sum := p.Reduce(Sum); !floatMostlyEquals(sum, 1.734) {
+		t.Fatalf("expected values rounded to the scale but got %f", sum)
+	}
+	if first := p.Reduce(func(w Window) float64 { return w[1][0] }); !floatMostlyEquals(first, 1.234) {
+		t.Fatalf("expected the oldest bucket after the expired one but got %f", first)
+	}
+
+	c.now = c.now.Add(2 * time.Second)
+	if n := p.Len(); n != 1 {
+		t.Fatalf("expected the first bucket to expire but got %d values", n)
+	}
+	p.AppendWithTimestamp(5, time.Unix(0, 0))
+	if n := p.Len(); n != 1 {
+		t.Fatalf("expected a value older than the window to be discarded but got %d values", n)
+	}
+	c.now = c.now.Add(time.Hour)
+	if n := p.Len(); n != 0 {
+		t.Fatalf("expected an empty window but got %d values", n)
+	}
+}
+
+func TestQuantizedPolicySaturates(t *testing.T) {
+	var c = &testClock{now: time.Unix(0, 0)}
+	var p = NewQuantizedPolicy(1, time.Second, Quantize16, 1, WithClock(c))
+	p.Append(40000)
+	p.Append(-40000)
+	p.Append(math.NaN())
+	if max := p.Reduce(Max); max != math.MaxInt16 {
+		t.Fatalf("expected the largest value to be clamped but got %f", max)
+	}
+	if min := p.Reduce(Min); min != math.MinInt16 {
+		t.Fatalf("expected the smallest value to be clamped but got %f", min)
+	}
+	if n := p.Len(); n != 3 {
+		t.Fatalf("expected 3 values but got %d", n)
+	}
+}
@@ -0,0 +1,121 @@
+package rolling
+
+import (
+	"sync"
+	"time"
+)
+
+// RatioPolicy is a rolling time window of boolean outcomes, such as success
+// and failure or cache hit and miss. Rather than storing a 1 or 0 for every
+// outcome, each bucket holds two counters: the number of outcomes that were
+// true and the total number of outcomes. Recording is a constant time
+// increment and the memory of the window does not grow with traffic.
+type RatioPolicy struct {
+	hits    []uint64
+	totals  []uint64
+	minimum int
+	ring    ring
+	clock   Clock
+	lock    *sync.Mutex
+}
+
+// NewRatioPolicy generates a RatioPolicy with the given number of buckets,
+// each covering the given duration. The ratio is not reported until the
+// window contains at least the given minimum number of outcomes so that a
+// handful of outcomes during a quiet period are not mistaken for a trend.
+// Only the WithClock and WithAlignment options apply to a RatioPolicy.
+func NewRatioPolicy(buckets int, bucketDuration time.Duration, minimum int, options ...TimePolicyOption) *RatioPolicy {
+	var o = newTimeOptions(options)
+	return &RatioPolicy{
+		hits:    make([]uint64, buckets),
+		totals:  make([]uint64, buckets),
+		minimum: minimum,
+		ring:    newTimeRing(buckets, bucketDuration, o.originNano),
+		clock:   o.clock,
+		lock:    &sync.Mutex{},
+	}
+}
+
+func (w *RatioPolicy) zero(offset int) {
+	w.hits[offset] = 0
+	w.totals[offset] = 0
+}
+
+// RecordWithTimestamp records an outcome in the bucket that contains the
+// given time. Outcomes older than the window are discarded.
+func (w *RatioPolicy) RecordWithTimestamp(outcome bool, timestamp time.Time) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	var adjustedTime = w.ring.index(timestamp)
+	w.ring.advance(adjustedTime, w.zero)
+	if !w.ring.contains(adjustedTime) {
+		return
+	}
+	var offset = w.ring.offset(adjustedTime)
+	if outcome {
+		w.hits[offset] = w.hits[offset] + 1
+	}
+	w.totals[offset] = w.totals[offset] + 1
+}
+
+// Record an outcome in the current bucket.
+func (w *RatioPolicy) Record(outcome bool) {
+	w.RecordWithTimestamp(outcome, w.clock.Now())
+}
+
+// Append records a true outcome for any value other than zero and a false
+// outcome for zero. This allows the window to be used as a Policy.
+func (w *RatioPolicy) Append(value float64) {
+	w.Record(value != 0)
+}
+
+// counts returns the number of true outcomes and the total number of outcomes
+// within the window.
+func (w *RatioPolicy) counts() (uint64, uint64) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	w.ring.advance(w.ring.index(w.clock.Now()), w.zero)
+	var hits, total uint64
+	for offset := range w.totals {
+		hits = hits + w.hits[offset]
+		total = total + w.totals[offset]
+	}
+	return hits, total
+}
+
+// Count returns the total number of outcomes within the window.
+func (w *RatioPolicy) Count() int {
+	var _, total = w.counts()
+	return int(total)
+}
+
+// Hits returns the number of true outcomes within the window.
+func (w *RatioPolicy) Hits() int {
+	var hits, _ = w.counts()
+	return int(hits)
+}
+
+// Ratio returns the fraction of outcomes within the window that were true and
+// whether the number of outcomes met the minimum. Zero is returned when the
+// volume is below the minimum.
+func (w *RatioPolicy) Ratio() (float64, bool) {
+	var hits, total = w.counts()
+	if total == 0 || total < uint64(w.minimum) {
+		return 0, false
+	}
+	return float64(hits) / float64(total), true
+}
+
+// Reduce the ratio to a single value using a reduction function. The ratio is
+// given as a window containing a single value, or no values when the volume
+// is below the minimum, so that the window may be used anywhere a Reducer is
+// accepted.
+func (w *RatioPolicy) Reduce(f func(Window) float64) float64 {
+	var ratio, ok = w.Ratio()
+	if !ok {
+		return f(Window{{}})
+	}
+	return f(Window{{ratio}})
+}
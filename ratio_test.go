@@ -0,0 +1,44 @@
+package rolling
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRatioPolicy(t *testing.T) {
+	var c = &testClock{now: time.Unix(0, 0)}
+	var p = NewRatioPolicy(3, time.Second, 4, WithClock(c))
+	p.Record(true)
+	p.Record(false)
+	p.Append(1)
+	if _, ok := p.Ratio(); ok {
+		t.Fatal("expected no ratio below the minimum volume")
+	}
+	if count := p.Reduce(Count); count != 0 {
+		t.Fatalf("expected an empty reduction below the minimum volume but got %f", count)
+	}
+	c.now = c.now.Add(time.Second)
+	p.Append(0)
+	if ratio, ok := p.Ratio(); !ok || ratio != 0.5 {
+		t.Fatalf("expected a ratio of 0.5 but got %f %v", ratio, ok)
+	}
+	if count, hits := p.Count(), p.Hits(); count != 4 || hits != 2 {
+		t.Fatalf("expected 2 of 4 outcomes but got %d of %d", hits, count)
+	}
+	if ratio := p.Reduce(Sum); ratio != 0.5 {
+		t.Fatalf("expected the reduction to see the ratio but got %f", ratio)
+	}
+
+	c.now = c.now.Add(2 * time.Second)
+	if count, hits := p.Count(), p.Hits(); count != 1 || hits != 0 {
+		t.Fatalf("expected the first bucket to expire but got %d of %d", hits, count)
+	}
+	p.RecordWithTimestamp(true, time.Unix(0, 0))
+	if count := p.Count(); count != 1 {
+		t.Fatalf("expected an outcome older than the window to be discarded but got %d", count)
+	}
+	c.now = c.now.Add(time.Hour)
+	if count := p.Count(); count != 0 {
+		t.Fatalf("expected an empty window but got %d", count)
+	}
+}
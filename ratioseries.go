@@ -0,0 +1,97 @@
+package rolling
+
+// RatioSeries is the bucket by bucket ratio of two time windows, such as the
+// errors and the requests of each second. Each bucket of the numerator is
+// divided by the bucket of the denominator that begins at the same time so
+// the derived series can be charted or reduced like any other window.
+type RatioSeries struct {
+	numerator   *TimePolicy
+	denominator *TimePolicy
+	aggregate   func(Window) float64
+}
+
+// NewRatioSeries generates a RatioSeries that reduces each bucket of both
+// windows with the given aggregate, such as Sum or Count, before dividing.
+// The windows should share a bucket duration and alignment. Only buckets that
+// begin at the same time in both windows are paired.
+func NewRatioSeries(numerator *TimePolicy, denominator *TimePolicy, aggregate func(Window) float64) *RatioSeries {
+	return &RatioSeries{
+		numerator:   numerator,
+		denominator: denominator,
+		aggregate:   aggregate,
+	}
+}
+
+// each calls the given function with every pair of aligned buckets, from the
+// oldest to the newest, along with whether the ratio is defined. A ratio is
+// undefined when the aggregate of the denominator is zero.
+func (s *RatioSeries) each(f func(point SeriesPoint, ok bool) bool) {
+	// Both windows are read as of the same moment so that their buckets
+	// cover the same periods.
+	var now = s.denominator.clock.Now()
+	var numerators = s.numerator.series(now, s.aggregate)
+	var denominators = s.denominator.series(now, s.aggregate)
+	var offset = 0
+	for _, denominator := range denominators {
+		for offset < len(numerators) && numerators[offset].Time.Before(denominator.Time) {
+			offset = offset + 1
+		}
+		if offset >= len(numerators) {
+			return
+		}
+		if !numerators[offset].Time.Equal(denominator.Time) {
+			continue
+		}
+		if denominator.Value == 0 {
+			if !f(SeriesPoint{Time: denominator.Time}, false) {
+				return
+			}
+			continue
+		}
+		var point = SeriesPoint{Time: denominator.Time, Value: numerators[offset].Value / denominator.Value}
+		if !f(point, true) {
+			return
+		}
+	}
+}
+
+// Iterate calls the given function with the ratio of each pair of aligned
+// buckets, from the oldest to the newest, until the function returns false.
+// Buckets in which the denominator is zero are skipped.
+func (s *RatioSeries) Iterate(f func(SeriesPoint) bool) {
+	s.each(func(point SeriesPoint, ok bool) bool {
+		if !ok {
+			return true
+		}
+		return f(point)
+	})
+}
+
+// Series returns the ratio of each pair of aligned buckets, from the oldest
+// to the newest. Buckets in which the denominator is zero are skipped.
+func (s *RatioSeries) Series() []SeriesPoint {
+	var result []SeriesPoint
+	s.Iterate(func(point SeriesPoint) bool {
+		result = append(result, point)
+		return true
+	})
+	return result
+}
+
+// Reduce the series to a single value using a reduction function. The window
+// given to the function has one bucket for each pair of aligned buckets, from
+// the oldest to the newest, that contains the ratio of the pair or no values
+// when the denominator is zero. For example, reducing with Max returns the
+// worst error rate of any single bucket.
+func (s *RatioSeries) Reduce(f func(Window) float64) float64 {
+	var window = make(Window, 0, s.denominator.BucketCount())
+	s.each(func(point SeriesPoint, ok bool) bool {
+		if !ok {
+			window = append(window, []float64{})
+			return true
+		}
+		window = append(window, []float64{point.Value})
+		return true
+	})
+	return f(window)
+}
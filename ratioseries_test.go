@@ -0,0 +1,64 @@
+package rolling
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRatioSeries(t *testing.T) {
+	var c = &testClock{now: time.Unix(0, 0)}
+	var errors = NewTimePolicy(NewWindow(4), time.Second, WithClock(c))
+	var requests = NewTimePolicy(NewWindow(4), time.Second, WithClock(c))
+	requests.AppendBatch([]float64{1, 1, 1, 1})
+	errors.Append(1)
+	c.now = c.now.Add(time.Second)
+	requests.AppendBatch([]float64{1, 1})
+	errors.AppendBatch([]float64{1, 1})
+	c.now = c.now.Add(2 * time.Second)
+	requests.Append(1)
+
+	var s = NewRatioSeries(errors, requests, Sum)
+	var series = s.Series()
+	if len(series) != 3 {
+		t.Fatalf("expected the bucket without requests to be skipped but got %v", series)
+	}
+	if series[0].Value != 0.25 || series[1].Value != 1 || series[2].Value != 0 {
+		t.Fatalf("expected the ratio of each bucket but got %v", series)
+	}
+	if !series[0].Time.Equal(time.Unix(0, 0)) || !series[2].Time.Equal(time.Unix(3, 0)) {
+		t.Fatalf("expected the start of each bucket but got %v", series)
+	}
+	var visited = 0
+	s.Iterate(func(SeriesPoint) bool {
+		visited = visited + 1
+		return false
+	})
+	if visited != 1 {
+		t.Fatalf("expected iteration to stop early but visited %d", visited)
+	}
+	if max := s.Reduce(Max); max != 1 {
+		t.Fatalf("expected the worst bucket to be 1 but got %f", max)
+	}
+	if buckets := s.Reduce(func(w Window) float64 { return float64(len(w)) }); buckets != 4 {
+		t.Fatalf("expected a bucket for every aligned period but got %f", buckets)
+	}
+	if avg := s.Reduce(Avg); !floatMostlyEquals(avg, 1.25/3) {
+		t.Fatalf("expected the average of the defined ratios but got %f", avg)
+	}
+}
+
+func TestRatioSeriesAlignment(t *testing.T) {
+	var c = &testClock{now: time.Unix(0, 0)}
+	var numerator = NewTimePolicy(NewWindow(2), time.Second, WithClock(c))
+	var denominator = NewTimePolicy(NewWindow(4), time.Second, WithClock(c))
+	for x := 0; x < 4; x = x + 1 {
+		numerator.Append(1)
+		denominator.Append(2)
+		c.now = c.now.Add(time.Second)
+	}
+	c.now = c.now.Add(-time.Second)
+	var series = NewRatioSeries(numerator, denominator, Sum).Series()
+	if len(series) != 2 || !series[0].Time.Equal(time.Unix(2, 0)) || series[1].Value != 0.5 {
+		t.Fatalf("expected only the periods covered by both windows but got %v", series)
+	}
+}
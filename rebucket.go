@@ -0,0 +1,56 @@
+package rolling
+
+import "time"
+
+// Rebucket changes the number of buckets and the bucket duration of the
+// window while it is in use, such as to switch from coarse to fine resolution
+// during an incident. The values already in the window are kept wherever they
+// still fall within it: each old bucket is moved, whole, into the new bucket
+// that contains its start. Moving to a coarser resolution therefore preserves
+// every value that is still covered by the window while moving to a finer
+// resolution places the values of each old bucket in the first of the new
+// buckets that it spans, as the original timing is not known. Values in old
+// buckets that start before the new window are discarded without calling any
+// hooks. The window given to NewTimePolicy is replaced and no longer used.
+// Non-positive sizes and durations are ignored.
+func (w *TimePolicy) Rebucket(buckets int, bucketDuration time.Duration) {
+	if buckets < 1 || bucketDuration <= 0 {
+		return
+	}
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	var now = w.clock.Now()
+	var starts = make([]time.Time, 0, w.numberOfBuckets)
+	var old = make(Window, 0, w.numberOfBuckets)
+	var appends = make([]uint64, 0, w.numberOfBuckets)
+	w.eachBucket(now, func(start time.Time, window Window) {
+		starts = append(starts, start)
+		old = append(old, window[0])
+		appends = append(appends, w.counters.perBucket[w.ring.offset(w.ring.index(start))])
+	})
+
+	var window = NewWindow(buckets)
+	for offset := range window {
+		window[offset] = make([]float64, 0, w.bucketHint)
+	}
+	w.bucketSize = bucketDuration
+	w.numberOfBuckets = buckets
+	w.window = window
+	w.size = 0
+	w.counters.perBucket = make([]uint64, buckets)
+	w.ring = newTimeRing(buckets, bucketDuration, w.ring.originNano)
+	w.ring.last = w.ring.index(now)
+	for x, start := range starts {
+		var adjustedTime, offset = w.selectBucket(start)
+		if !w.ring.contains(adjustedTime) {
+			continue
+		}
+		w.counters.perBucket[offset] = w.counters.perBucket[offset] + appends[x]
+		if len(old[x]) < 1 {
+			continue
+		}
+		w.window[offset] = append(w.window[offset], old[x]...)
+		w.size = w.size + len(old[x])
+	}
+}
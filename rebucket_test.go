@@ -0,0 +1,91 @@
+package rolling
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestTimePolicyRebucket(t *testing.T) {
+	var c = &testClock{now: time.Unix(0, 0)}
+	var p = NewTimePolicy(NewWindow(6), time.Second, WithClock(c))
+	for x := 1; x <= 6; x = x + 1 {
+		p.Append(float64(x))
+		c.now = c.now.Add(time.Second)
+	}
+	c.now = c.now.Add(-time.Second)
+
+	// Coarser: two buckets of two seconds cover the last four seconds.
+	p.Rebucket(2, 2*time.Second)
+	if p.BucketCount() != 2 || p.BucketSize() != 2*time.Second {
+		t.Fatalf("expected 2 buckets of 2s but got %d of %s", p.BucketCount(), p.BucketSize())
+	}
+	var series = p.Series(Sum)
+	if p.Len() != 4 || series[0].Value != 7 || series[1].Value != 11 {
+		t.Fatalf("expected the last four values in pairs but got %d %v", p.Len(), series)
+	}
+	if !series[0].Time.Equal(time.Unix(2, 0)) {
+		t.Fatalf("expected the new buckets to be aligned to 2s but got %v", series)
+	}
+	if rate := p.Stats().AppendRate; !floatEquals(rate, 1) {
+		t.Fatalf("expected the appends of the kept buckets to move with them but got %f", rate)
+	}
+
+	// Finer: each old bucket moves to the first new bucket it spans.
+	p.Rebucket(8, 500*time.Millisecond)
+	series = p.Series(Sum)
+	if p.Len() != 4 || len(series) != 8 || series[0].Value != 0 || series[1].Value != 7 || series[5].Value != 11 {
+		t.Fatalf("expected each pair in the first half second of its span but got %v", series)
+	}
+
+	c.now = c.now.Add(500 * time.Millisecond)
+	p.Append(100)
+	if sum := p.Reduce(Sum); sum != 118 {
+		t.Fatalf("expected appends to continue at the new resolution but got %f", sum)
+	}
+	c.now = c.now.Add(4 * time.Second)
+	if n := p.Len(); n != 0 {
+		t.Fatalf("expected the window to expire at the new duration but got %d", n)
+	}
+
+	p.Rebucket(0, time.Second)
+	if p.BucketCount() != 8 {
+		t.Fatalf("expected an invalid size to be ignored but got %d buckets", p.BucketCount())
+	}
+}
+
+func TestTimePolicyRebucketConcurrentReaders(t *testing.T) {
+	var p = NewTimePolicy(NewWindow(6), time.Second)
+	var ratio = NewRatioSeries(NewTimePolicy(NewWindow(6), time.Second), p, Sum)
+	var done = make(chan struct{})
+	var wg = &sync.WaitGroup{}
+	var readers = []func(){
+		func() { _ = p.BucketSize() },
+		func() { _ = p.BucketCount() },
+		func() { _ = p.WindowDuration() },
+		func() { _ = p.Fill() },
+		func() { _ = p.String() },
+		func() { p.RotateEvery(time.Hour)() },
+		func() { _ = ratio.Reduce(Sum) },
+	}
+	for _, read := range readers {
+		wg.Add(1)
+		go func(read func()) {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+					read()
+				}
+			}
+		}(read)
+	}
+	var deadline = time.Now().Add(50 * time.Millisecond)
+	for x := 0; time.Now().Before(deadline); x = x + 1 {
+		p.Rebucket(2+x%5, time.Duration(1+x%3)*time.Second)
+	}
+	close(done)
+	wg.Wait()
+}
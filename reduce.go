@@ -6,6 +6,45 @@ import (
 	"sync"
 )
 
+// Fold returns an aggregating function that combines every value within the
+// window, in order, with an accumulator that starts at the given initial
+// value. This allows one-off aggregations to be written inline:
+//
+//	var sumOfSquares = rolling.Fold(0, func(acc float64, v float64) float64 {
+//		return acc + v*v
+//	})
+//	p.Reduce(sumOfSquares)
+func Fold(initial float64, f func(acc float64, v float64) float64) func(w Window) float64 {
+	return func(w Window) float64 {
+		var result = initial
+		for _, bucket := range w {
+			for _, p := range bucket {
+				result = f(result, p)
+			}
+		}
+		return result
+	}
+}
+
+// Select returns an aggregating function that chooses between two other
+// aggregating functions based on a third. The condition is computed first and
+// its result is given to the predicate. The window is then reduced using the
+// first function if the predicate returns true and the second otherwise. For
+// example, an exact percentile may be used for small windows while an
+// estimate is used once the window grows large:
+//
+//	var p99 = rolling.Select(rolling.Count, func(count float64) bool {
+//		return count > 10000
+//	}, rolling.FastPercentile(99), rolling.Percentile(99))
+func Select(condition func(w Window) float64, predicate func(float64) bool, whenTrue func(w Window) float64, whenFalse func(w Window) float64) func(w Window) float64 {
+	return func(w Window) float64 {
+		if predicate(condition(w)) {
+			return whenTrue(w)
+		}
+		return whenFalse(w)
+	}
+}
+
 // Count returns the number of elements in a window.
 func Count(w Window) float64 {
 	result := 0
@@ -26,8 +65,98 @@ func Sum(w Window) float64 {
 	return result
 }
 
-// Avg the values within the window.
+// First returns the first value within the window, or zero if the window is
+// empty. The result is only meaningful for windows whose buckets are given in
+// order, such as those passed to PointPolicy.ReduceOrdered.
+func First(w Window) float64 {
+	for _, bucket := range w {
+		if len(bucket) > 0 {
+			return bucket[0]
+		}
+	}
+	return 0
+}
+
+// Last returns the last value within the window, or zero if the window is
+// empty. The result is only meaningful for windows whose buckets are given in
+// order, such as those passed to PointPolicy.ReduceOrdered.
+func Last(w Window) float64 {
+	for offset := len(w) - 1; offset >= 0; offset = offset - 1 {
+		if len(w[offset]) > 0 {
+			return w[offset][len(w[offset])-1]
+		}
+	}
+	return 0
+}
+
+// CountInRange returns an aggregating function that counts the values within
+// the window that are at least lo and at most hi.
+func CountInRange(lo float64, hi float64) func(w Window) float64 {
+	return func(w Window) float64 {
+		var result = 0
+		for _, bucket := range w {
+			for _, p := range bucket {
+				if p >= lo && p <= hi {
+					result = result + 1
+				}
+			}
+		}
+		return float64(result)
+	}
+}
+
+// FractionInRange returns an aggregating function that computes the fraction
+// of the values within the window that are at least lo and at most hi. An
+// empty window results in zero.
+func FractionInRange(lo float64, hi float64) func(w Window) float64 {
+	var inRange = CountInRange(lo, hi)
+	return func(w Window) float64 {
+		var count = Count(w)
+		if count == 0 {
+			return 0
+		}
+		return inRange(w) / count
+	}
+}
+
+// Product multiplies the values within the window. As with any empty product,
+// an empty window results in one. Use LogProduct for windows whose product
+// may overflow or underflow a float64.
+func Product(w Window) float64 {
+	var result = 1.0
+	for _, bucket := range w {
+		for _, p := range bucket {
+			result = result * p
+		}
+	}
+	return result
+}
+
+// LogProduct returns the natural logarithm of the product of the values
+// within the window. The logarithms are summed rather than the values
+// multiplied so that long windows of small ratios, such as availability
+// measurements, do not underflow to zero. The values must be positive. An
+// empty window results in zero.
+func LogProduct(w Window) float64 {
+	var result = 0.0
+	for _, bucket := range w {
+		for _, p := range bucket {
+			result = result + math.Log(p)
+		}
+	}
+	return result
+}
+
+// Avg the values within the window. An empty window has an average of zero.
+// Use AvgOK to distinguish an empty window from one with an average of zero.
 func Avg(w Window) float64 {
+	var result, _ = AvgOK(w)
+	return result
+}
+
+// AvgOK returns the average of the values within the window and whether the
+// window contained any values at all.
+func AvgOK(w Window) (float64, bool) {
 	var result = 0.0
 	var count = 0.0
 	for _, bucket := range w {
@@ -36,18 +165,29 @@ func Avg(w Window) float64 {
 			count = count + 1
 		}
 	}
-	return result / count
+	if count == 0 {
+		return 0, false
+	}
+	return result / count, true
 }
 
-// Min the values within the window.
+// Min the values within the window. An empty window has a minimum of zero. Use
+// MinOK to distinguish an empty window from one with a minimum of zero.
 func Min(w Window) float64 {
+	var result, _ = MinOK(w)
+	return result
+}
+
+// MinOK returns the minimum of the values within the window and whether the
+// window contained any values at all.
+func MinOK(w Window) (float64, bool) {
 	var result = 0.0
-	var started = true
+	var started = false
 	for _, bucket := range w {
 		for _, p := range bucket {
-			if started {
+			if !started {
 				result = p
-				started = false
+				started = true
 				continue
 			}
 			if p < result {
@@ -55,18 +195,26 @@ func Min(w Window) float64 {
 			}
 		}
 	}
-	return result
+	return result, started
 }
 
-// Max the values within the window.
+// Max the values within the window. An empty window has a maximum of zero. Use
+// MaxOK to distinguish an empty window from one with a maximum of zero.
 func Max(w Window) float64 {
+	var result, _ = MaxOK(w)
+	return result
+}
+
+// MaxOK returns the maximum of the values within the window and whether the
+// window contained any values at all.
+func MaxOK(w Window) (float64, bool) {
 	var result = 0.0
-	var started = true
+	var started = false
 	for _, bucket := range w {
 		for _, p := range bucket {
-			if started {
+			if !started {
 				result = p
-				started = false
+				started = true
 				continue
 			}
 			if p > result {
@@ -74,12 +222,133 @@ func Max(w Window) float64 {
 			}
 		}
 	}
-	return result
+	return result, started
+}
+
+// welford computes the count, mean, and sum of squared differences from the
+// mean of the values within the window in a single, numerically stable pass.
+func welford(w Window) (float64, float64, float64) {
+	var count, mean, m2 float64
+	for _, bucket := range w {
+		for _, p := range bucket {
+			count = count + 1
+			var delta = p - mean
+			mean = mean + delta/count
+			m2 = m2 + delta*(p-mean)
+		}
+	}
+	return count, mean, m2
+}
+
+// Variance returns the population variance of the values within the window.
+// An empty window has a variance of zero.
+func Variance(w Window) float64 {
+	var count, _, m2 = welford(w)
+	if count < 1 {
+		return 0
+	}
+	return m2 / count
+}
+
+// StdDev returns the population standard deviation of the values within the
+// window. An empty window has a standard deviation of zero.
+func StdDev(w Window) float64 {
+	return math.Sqrt(Variance(w))
+}
+
+// OutlierCount returns an aggregating function that counts the values within
+// the window that are greater than the mean by more than k standard
+// deviations. This measures how much of the window is misbehaving rather than
+// only whether any of it is. The population standard deviation of the window
+// is used and an empty window has no outliers.
+func OutlierCount(k float64) func(w Window) float64 {
+	return func(w Window) float64 {
+		var count, mean, m2 = welford(w)
+		if count < 1 {
+			return 0
+		}
+		var threshold = mean + k*math.Sqrt(m2/count)
+		var result = 0.0
+		for _, bucket := range w {
+			for _, p := range bucket {
+				if p > threshold {
+					result = result + 1
+				}
+			}
+		}
+		return result
+	}
 }
 
 // Percentile returns an aggregating function that computes the
 // given percentile calculation for a window.
+//
+// The percentile is estimated by linear interpolation between the closest
+// ranks of the sorted values where the rank of the nth smallest value is
+// (n - 0.5) / count. This is definition 5 of Hyndman and Fan, "Sample
+// Quantiles in Statistical Packages". Percentiles that fall below the first
+// rank or above the last rank are clamped to the minimum or maximum value.
 func Percentile(perc float64) func(w Window) float64 {
+	var values []float64
+	var lock = &sync.Mutex{}
+	return func(w Window) float64 {
+		lock.Lock()
+		defer lock.Unlock()
+
+		values = sortedValues(w, values[:0])
+		if len(values) < 1 {
+			return 0.0
+		}
+		return percentileOfSorted(values, perc)
+	}
+}
+
+// sortedValues appends every value of the window to the given slice and
+// sorts the result.
+func sortedValues(w Window, values []float64) []float64 {
+	for _, bucket := range w {
+		values = append(values, bucket...)
+	}
+	sort.Float64s(values)
+	return values
+}
+
+// IQR returns the interquartile range of the values within the window, which
+// is the difference between the 75th and 25th percentiles. Both percentiles
+// are computed as described by Percentile from a single sort of the values.
+func IQR(w Window) float64 {
+	var values = sortedValues(w, make([]float64, 0, int(Count(w))))
+	if len(values) < 1 {
+		return 0.0
+	}
+	return percentileOfSorted(values, 75) - percentileOfSorted(values, 25)
+}
+
+// Spread returns an aggregating function that computes the difference between
+// two percentiles of the window, such as the 99th minus the 50th. A wide
+// spread between the tail and the median is a common sign of bimodal
+// latency. Both percentiles are computed as described by Percentile from a
+// single sort of the values.
+func Spread(lower float64, upper float64) func(w Window) float64 {
+	var values []float64
+	var lock = &sync.Mutex{}
+	return func(w Window) float64 {
+		lock.Lock()
+		defer lock.Unlock()
+
+		values = sortedValues(w, values[:0])
+		if len(values) < 1 {
+			return 0.0
+		}
+		return percentileOfSorted(values, upper) - percentileOfSorted(values, lower)
+	}
+}
+
+// DeviationPercentile returns an aggregating function that computes the given
+// percentile of the absolute distance of each value from a target, such as
+// the jitter of response times around the midpoint of an SLA. The percentile
+// is computed as described by Percentile.
+func DeviationPercentile(target float64, perc float64) func(w Window) float64 {
 	var values []float64
 	var lock = &sync.Mutex{}
 	return func(w Window) float64 {
@@ -88,32 +357,149 @@ func Percentile(perc float64) func(w Window) float64 {
 
 		values = values[:0]
 		for _, bucket := range w {
-			values = append(values, bucket...)
+			for _, p := range bucket {
+				values = append(values, math.Abs(p-target))
+			}
 		}
 		if len(values) < 1 {
 			return 0.0
 		}
 		sort.Float64s(values)
-		var position = (float64(len(values))*(perc/100) + .5) - 1
-		var k = int(math.Floor(position))
-		var f = math.Mod(position, 1)
-		if f == 0.0 {
-			return values[k]
+		return percentileOfSorted(values, perc)
+	}
+}
+
+// percentileOfSorted computes the percentile of a non-empty, sorted slice of
+// values as described by Percentile.
+func percentileOfSorted(values []float64, perc float64) float64 {
+	var position = (float64(len(values))*(perc/100) + .5) - 1
+	if position <= 0 {
+		return values[0]
+	}
+	var k = int(math.Floor(position))
+	if k >= len(values)-1 {
+		return values[len(values)-1]
+	}
+	var f = position - float64(k)
+	if f == 0.0 {
+		return values[k]
+	}
+	return ((1 - f) * values[k]) + (f * values[k+1])
+}
+
+// TrimmedMean returns an aggregating function that discards the given
+// fraction of the smallest values and the same fraction of the largest values
+// before averaging those that remain. A fraction of 0.05, for example,
+// ignores the lowest and highest five percent of the window. At least one
+// value is always kept so a fraction of 0.5 or more results in the median.
+func TrimmedMean(fraction float64) func(w Window) float64 {
+	var values []float64
+	var lock = &sync.Mutex{}
+	return func(w Window) float64 {
+		lock.Lock()
+		defer lock.Unlock()
+
+		values = sortedValues(w, values[:0])
+		if len(values) < 1 {
+			return 0.0
+		}
+		var trim = int(math.Floor(float64(len(values)) * fraction))
+		if trim < 0 {
+			trim = 0
+		}
+		if len(values)-2*trim < 1 {
+			trim = (len(values) - 1) / 2
+		}
+		var kept = values[trim : len(values)-trim]
+		var result = 0.0
+		for _, v := range kept {
+			result = result + v
+		}
+		return result / float64(len(kept))
+	}
+}
+
+// Entropy returns an aggregating function that computes the Shannon entropy,
+// in bits, of the values within the window. Values are grouped into bins of
+// the given width, such that a value v falls into bin floor(v / width), and
+// the entropy is computed from the fraction of values in each bin. A width of
+// zero or less places each distinct value in its own bin. A sudden change in
+// entropy indicates a change in the distribution of the values, such as
+// request sizes that suddenly become uniform.
+func Entropy(width float64) func(w Window) float64 {
+	var bins = make(map[float64]int)
+	var lock = &sync.Mutex{}
+	return func(w Window) float64 {
+		lock.Lock()
+		defer lock.Unlock()
+
+		for bin := range bins {
+			delete(bins, bin)
 		}
-		var plusOne = k + 1
-		if plusOne > len(values)-1 {
-			plusOne = k
+		var count = 0
+		for _, bucket := range w {
+			for _, p := range bucket {
+				var bin = p
+				if width > 0 {
+					bin = math.Floor(p / width)
+				}
+				bins[bin] = bins[bin] + 1
+				count = count + 1
+			}
+		}
+		var result = 0.0
+		for _, n := range bins {
+			var probability = float64(n) / float64(count)
+			result = result - probability*math.Log2(probability)
+		}
+		return result
+	}
+}
+
+// defaultExactBelow is the smallest number of values for which FastPercentile
+// uses estimation. The estimator requires five observations to initialize its
+// markers before it can process any further values.
+const defaultExactBelow = 6
+
+type fastPercentile struct {
+	exactBelow int
+}
+
+// FastPercentileOption is used to modify the behavior of FastPercentile.
+type FastPercentileOption func(*fastPercentile)
+
+// WithExactBelow sets the number of values below which FastPercentile
+// computes the exact percentile, as Percentile does, rather than an
+// estimate. The estimate is least accurate for small windows so the exact
+// calculation is preferable whenever it is affordable. Values smaller than
+// the default of six are ignored.
+func WithExactBelow(samples int) FastPercentileOption {
+	return func(f *fastPercentile) {
+		if samples > defaultExactBelow {
+			f.exactBelow = samples
 		}
-		return ((1 - f) * values[k]) + (f * values[plusOne])
 	}
 }
 
 // FastPercentile implements the pSquare percentile estimation
 // algorithm for calculating percentiles from streams of data
-// using fixed memory allocations.
-func FastPercentile(perc float64) func(w Window) float64 {
+// using fixed memory allocations. Windows containing too few values for the
+// estimate to be meaningful are given the exact percentile instead.
+func FastPercentile(perc float64, options ...FastPercentileOption) func(w Window) float64 {
+	var config = &fastPercentile{exactBelow: defaultExactBelow}
+	for _, option := range options {
+		option(config)
+	}
+	var exact = perc
 	perc = perc / 100.0
 	return func(w Window) float64 {
+		var count = int(Count(w))
+		if count < 1 {
+			return 0.0
+		}
+		if count < config.exactBelow {
+			return percentileOfSorted(sortedValues(w, make([]float64, 0, count)), exact)
+		}
 		var initalObservations = make([]float64, 0, 5)
 		var q [5]float64
 		var n [5]int
@@ -184,10 +570,17 @@ func FastPercentile(perc float64) func(w Window) float64 {
 						var qx = q[x]
 						var qxPlusOne = q[x+1]
 						var qxMinusOne = q[x-1]
-						var parab = q[x] + (s/(nxPlusOne-nxMinusOne))*((nx-nxMinusOne+s)*(qxPlusOne-qx)/(nxPlusOne-nx)+(nxPlusOne-nx-s)*(qx-qxMinusOne)/(nx-nxMinusOne))
-						if qxMinusOne < parab && parab < qxPlusOne {
+						// Markers that share a position would cause a division
+						// by zero so only the adjustments with well defined
+						// results are attempted.
+						var parab = math.NaN()
+						if nxPlusOne != nxMinusOne && nxPlusOne != nx && nx != nxMinusOne {
+							parab = q[x] + (s/(nxPlusOne-nxMinusOne))*((nx-nxMinusOne+s)*(qxPlusOne-qx)/(nxPlusOne-nx)+(nxPlusOne-nx-s)*(qx-qxMinusOne)/(nx-nxMinusOne))
+						}
+						switch {
+						case qxMinusOne < parab && parab < qxPlusOne:
 							q[x] = parab
-						} else {
+						case n[x+si] != n[x]:
 							q[x] = q[x] + s*((q[x+si]-q[x])/float64(n[x+si]-n[x]))
 						}
 						n[x] = n[x] + si
@@ -197,15 +590,6 @@ func FastPercentile(perc float64) func(w Window) float64 {
 			}
 		}
 
-		if observations < 1 {
-			return 0.0
-		}
-		// If we have less than five values then degenerate into a max function.
-		// This is a reasonable value for data sets this small.
-		if observations < 5 {
-			bubbleSort(initalObservations)
-			return initalObservations[len(initalObservations)-1]
-		}
 		return q[2]
 	}
 }
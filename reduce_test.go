@@ -2,7 +2,10 @@ package rolling
 
 import (
 	"fmt"
+	"math"
+	"sort"
 	"testing"
+	"time"
 )
 
 // https://gist.github.com/cevaris/bc331cbe970b03816c6b
@@ -34,6 +37,110 @@ func TestCount(t *testing.T) {
 	}
 }
 
+func TestFold(t *testing.T) {
+	var numberOfPoints = 10
+	var w = NewWindow(numberOfPoints)
+	var p = NewPointPolicy(w)
+	for x := 1; x <= numberOfPoints; x = x + 1 {
+		p.Append(float64(x))
+	}
+	var sumOfSquares = Fold(0, func(acc float64, v float64) float64 {
+		return acc + v*v
+	})
+	if result := p.Reduce(sumOfSquares); !floatEquals(result, 385) {
+		t.Fatalf("fold calculated incorrectly: %f versus %f", 385.0, result)
+	}
+	var product = Fold(1, func(acc float64, v float64) float64 {
+		return acc * v
+	})
+	if result := product(NewWindow(1)); !floatEquals(result, 1) {
+		t.Fatalf("empty fold should be the initial value but got %f", result)
+	}
+}
+
+func TestSelect(t *testing.T) {
+	var large = func(count float64) bool {
+		return count > 3
+	}
+	var f = Select(Count, large, Max, Min)
+	if result := f(Window{{1, 2, 3}}); !floatEquals(result, 1) {
+		t.Fatalf("select chose incorrectly: %f versus %f", 1.0, result)
+	}
+	if result := f(Window{{1, 2, 3}, {4}}); !floatEquals(result, 4) {
+		t.Fatalf("select chose incorrectly: %f versus %f", 4.0, result)
+	}
+}
+
+func TestSpread(t *testing.T) {
+	var w = NewWindow(100)
+	var p = NewPointPolicy(w)
+	for x := 1; x <= 100; x = x + 1 {
+		p.Append(float64(x))
+	}
+	var expected = p.Reduce(Percentile(99)) - p.Reduce(Percentile(50))
+	if result := p.Reduce(Spread(50, 99)); !floatEquals(result, expected) {
+		t.Fatalf("spread calculated incorrectly: %f versus %f", expected, result)
+	}
+	if result := p.Reduce(Spread(25, 75)); !floatEquals(result, p.Reduce(IQR)) {
+		t.Fatalf("spread calculated incorrectly: %f versus %f", p.Reduce(IQR), result)
+	}
+	if result := Spread(50, 99)(Window{}); result != 0 {
+		t.Fatalf("expected zero for an empty window but got %f", result)
+	}
+}
+
+func TestDeviationPercentile(t *testing.T) {
+	var w = Window{{90, 110}, {95, 105, 100}}
+	if result := DeviationPercentile(100, 100)(w); result != 10 {
+		t.Fatalf("deviation calculated incorrectly: %f versus %f", 10.0, result)
+	}
+	if result := DeviationPercentile(100, 50)(w); result != 5 {
+		t.Fatalf("deviation calculated incorrectly: %f versus %f", 5.0, result)
+	}
+	var expected = Percentile(90)(Window{{10, 10, 5, 5, 0}})
+	if result := DeviationPercentile(100, 90)(w); !floatEquals(result, expected) {
+		t.Fatalf("deviation calculated incorrectly: %f versus %f", expected, result)
+	}
+	if result := DeviationPercentile(100, 50)(Window{}); result != 0 {
+		t.Fatalf("expected zero for an empty window but got %f", result)
+	}
+}
+
+func TestVariance(t *testing.T) {
+	var w = Window{{2, 4, 4, 4}, {5, 5, 7, 9}}
+	if result := Variance(w); !floatEquals(result, 4) {
+		t.Fatalf("variance calculated incorrectly: %f versus %f", 4.0, result)
+	}
+	if result := StdDev(w); !floatEquals(result, 2) {
+		t.Fatalf("standard deviation calculated incorrectly: %f versus %f", 2.0, result)
+	}
+	if result := Variance(Window{}); result != 0 {
+		t.Fatalf("expected zero for an empty window but got %f", result)
+	}
+}
+
+func TestOutlierCount(t *testing.T) {
+	// The mean is 5 and the standard deviation is 2.
+	var w = Window{{2, 4, 4, 4}, {5, 5, 7, 9}}
+	var tc = []struct {
+		k        float64
+		expected float64
+	}{
+		{k: 0, expected: 2},
+		{k: .5, expected: 2},
+		{k: 1, expected: 1},
+		{k: 2, expected: 0},
+	}
+	for _, c := range tc {
+		if result := OutlierCount(c.k)(w); !floatEquals(result, c.expected) {
+			t.Fatalf("outliers for k=%f counted incorrectly: %f versus %f", c.k, c.expected, result)
+		}
+	}
+	if result := OutlierCount(1)(Window{}); result != 0 {
+		t.Fatalf("expected no outliers in an empty window but got %f", result)
+	}
+}
+
 func TestCountPreallocatedWindow(t *testing.T) {
 	var numberOfPoints = 100
 	var w = NewPreallocatedWindow(numberOfPoints, 100)
@@ -79,6 +186,90 @@ func TestAvg(t *testing.T) {
 	}
 }
 
+func TestFirstLast(t *testing.T) {
+	var p = NewPointPolicy(NewWindow(3))
+	if result := p.ReduceOrdered(First); !floatEquals(result, 0) {
+		t.Fatalf("empty first should be zero but got %f", result)
+	}
+	if result := p.ReduceOrdered(Last); !floatEquals(result, 0) {
+		t.Fatalf("empty last should be zero but got %f", result)
+	}
+	for x := 1; x <= 5; x = x + 1 {
+		p.Append(float64(x))
+	}
+	if result := p.ReduceOrdered(First); !floatEquals(result, 3) {
+		t.Fatalf("first calculated incorrectly: %f versus %f", 3.0, result)
+	}
+	if result := p.ReduceOrdered(Last); !floatEquals(result, 5) {
+		t.Fatalf("last calculated incorrectly: %f versus %f", 5.0, result)
+	}
+	var w = Window{nil, {1, 2}, {3, 4}, nil}
+	if !floatEquals(First(w), 1) || !floatEquals(Last(w), 4) {
+		t.Fatalf("first and last calculated incorrectly: %f %f", First(w), Last(w))
+	}
+}
+
+func TestCountInRange(t *testing.T) {
+	var numberOfPoints = 100
+	var w = NewWindow(numberOfPoints)
+	var p = NewPointPolicy(w)
+	for x := 1; x <= numberOfPoints; x = x + 1 {
+		p.Append(float64(x))
+	}
+	if result := p.Reduce(CountInRange(10, 19)); !floatEquals(result, 10) {
+		t.Fatalf("count in range calculated incorrectly: %f versus %f", 10.0, result)
+	}
+	if result := p.Reduce(FractionInRange(0, 25)); !floatEquals(result, .25) {
+		t.Fatalf("fraction in range calculated incorrectly: %f versus %f", .25, result)
+	}
+	if result := FractionInRange(0, 25)(NewWindow(1)); !floatEquals(result, 0) {
+		t.Fatalf("empty fraction in range should be zero but got %f", result)
+	}
+}
+
+func TestProduct(t *testing.T) {
+	var w = Window{{.999, .999}, {.5}}
+	if result := Product(w); !floatEquals(result, .999*.999*.5) {
+		t.Fatalf("product calculated incorrectly: %f versus %f", .999*.999*.5, result)
+	}
+	if result := LogProduct(w); !floatEquals(result, math.Log(.999*.999*.5)) {
+		t.Fatalf("log product calculated incorrectly: %f versus %f", math.Log(.999*.999*.5), result)
+	}
+	if result := Product(NewWindow(1)); !floatEquals(result, 1) {
+		t.Fatalf("empty product should be one but got %f", result)
+	}
+	if result := LogProduct(NewWindow(1)); !floatEquals(result, 0) {
+		t.Fatalf("empty log product should be zero but got %f", result)
+	}
+	var numberOfPoints = 10000
+	var p = NewPointPolicy(NewWindow(numberOfPoints))
+	for x := 0; x < numberOfPoints; x = x + 1 {
+		p.Append(1e-100)
+	}
+	if result := p.Reduce(Product); result != 0 {
+		t.Fatalf("expected product to underflow but got %f", result)
+	}
+	if result := p.Reduce(LogProduct); !floatMostlyEquals(result, float64(numberOfPoints)*math.Log(1e-100)) {
+		t.Fatalf("log product calculated incorrectly: %f", result)
+	}
+}
+
+func TestAvgWhenEmpty(t *testing.T) {
+	var w = NewWindow(10)
+	var p = NewTimePolicy(w, time.Second)
+	var result = p.Reduce(Avg)
+	if !floatEquals(result, 0) {
+		t.Fatalf("empty avg should be zero but got %f", result)
+	}
+	if result, ok := AvgOK(w); ok || !floatEquals(result, 0) {
+		t.Fatalf("empty avg should be zero and not ok but got %f %v", result, ok)
+	}
+	w[0] = []float64{1, 2}
+	if result, ok := AvgOK(w); !ok || !floatEquals(result, 1.5) {
+		t.Fatalf("avg calculated incorrectly: %f %v", result, ok)
+	}
+}
+
 func TestMax(t *testing.T) {
 	var numberOfPoints = 100
 	var w = NewWindow(numberOfPoints)
@@ -109,6 +300,40 @@ func TestMin(t *testing.T) {
 	}
 }
 
+func TestMinMaxNegativeValues(t *testing.T) {
+	var numberOfPoints = 10
+	var w = NewWindow(numberOfPoints)
+	var p = NewPointPolicy(w)
+	for x := 1; x <= numberOfPoints; x = x + 1 {
+		p.Append(-float64(x))
+	}
+	var result = p.Reduce(Max)
+	if !floatEquals(result, -1) {
+		t.Fatalf("max calculated incorrectly: %f versus %f", -1.0, result)
+	}
+	result = p.Reduce(Min)
+	if !floatEquals(result, -10) {
+		t.Fatalf("min calculated incorrectly: %f versus %f", -10.0, result)
+	}
+}
+
+func TestMinMaxOKWhenEmpty(t *testing.T) {
+	var w = NewWindow(10)
+	if result, ok := MinOK(w); ok || !floatEquals(result, 0) {
+		t.Fatalf("empty min should be zero and not ok but got %f %v", result, ok)
+	}
+	if result, ok := MaxOK(w); ok || !floatEquals(result, 0) {
+		t.Fatalf("empty max should be zero and not ok but got %f %v", result, ok)
+	}
+	w[3] = []float64{-2, -5}
+	if result, ok := MinOK(w); !ok || !floatEquals(result, -5) {
+		t.Fatalf("min calculated incorrectly: %f %v", result, ok)
+	}
+	if result, ok := MaxOK(w); !ok || !floatEquals(result, -2) {
+		t.Fatalf("max calculated incorrectly: %f %v", result, ok)
+	}
+}
+
 func TestPercentileAggregateInterpolateWhenEmpty(t *testing.T) {
 	var numberOfPoints = 0
 	var w = NewWindow(numberOfPoints)
@@ -225,6 +450,167 @@ func TestFastPercentileAggregateUsingPSquaredDataSet(t *testing.T) {
 	}
 }
 
+func TestFastPercentileExactWhenFewValues(t *testing.T) {
+	for size := 1; size <= 5; size = size + 1 {
+		var w = NewWindow(size)
+		var p = NewPointPolicy(w)
+		for x := 1; x <= size; x = x + 1 {
+			p.Append(float64(x))
+		}
+		var result = p.Reduce(FastPercentile(50))
+		var expected = p.Reduce(Percentile(50))
+		if !floatEquals(result, expected) {
+			t.Fatalf("fast percentile of %d values calculated incorrectly: %f versus %f", size, expected, result)
+		}
+	}
+}
+
+func TestFastPercentileWithExactBelow(t *testing.T) {
+	var numberOfPoints = 20
+	var w = NewWindow(numberOfPoints)
+	var p = NewPointPolicy(w)
+	for x := 1; x <= numberOfPoints; x = x + 1 {
+		p.Append(float64(x * x))
+	}
+	var result = p.Reduce(FastPercentile(90, WithExactBelow(numberOfPoints+1)))
+	var expected = p.Reduce(Percentile(90))
+	if !floatEquals(result, expected) {
+		t.Fatalf("fast percentile calculated incorrectly: %f versus %f", expected, result)
+	}
+	result = p.Reduce(FastPercentile(90, WithExactBelow(numberOfPoints)))
+	if floatEquals(result, expected) {
+		t.Fatal("fast percentile was exact when it should have been estimated")
+	}
+}
+
+func TestFastPercentilePathologicalInputs(t *testing.T) {
+	var numberOfPoints = 1000
+	var cases = []struct {
+		name  string
+		value func(x int) float64
+		perc  float64
+		check func(float64) bool
+	}{
+		{
+			name:  "constant",
+			value: func(x int) float64 { return 7 },
+			perc:  99,
+			check: func(v float64) bool { return floatEquals(v, 7) },
+		},
+		{
+			name:  "alternating",
+			value: func(x int) float64 { return float64(x % 2) },
+			perc:  50,
+			check: func(v float64) bool { return v >= 0 && v <= 1 },
+		},
+		{
+			name:  "minimum",
+			value: func(x int) float64 { return float64(x) },
+			perc:  0,
+			check: func(v float64) bool { return v >= 1 && v <= float64(numberOfPoints) },
+		},
+		{
+			name:  "maximum",
+			value: func(x int) float64 { return float64(x) },
+			perc:  100,
+			check: func(v float64) bool { return v >= 1 && v <= float64(numberOfPoints) },
+		},
+		{
+			name:  "descending",
+			value: func(x int) float64 { return float64(numberOfPoints - x) },
+			perc:  50,
+			check: func(v float64) bool { return math.Abs(v-500) < 10 },
+		},
+	}
+	for _, c := range cases {
+		var w = NewWindow(numberOfPoints)
+		var p = NewPointPolicy(w)
+		for x := 1; x <= numberOfPoints; x = x + 1 {
+			p.Append(c.value(x))
+		}
+		var result = p.Reduce(FastPercentile(c.perc))
+		if math.IsNaN(result) || math.IsInf(result, 0) || !c.check(result) {
+			t.Fatalf("%s: %f percentile calculated incorrectly: %f", c.name, c.perc, result)
+		}
+	}
+}
+
+func TestTrimmedMean(t *testing.T) {
+	var numberOfPoints = 100
+	var w = NewWindow(numberOfPoints)
+	var p = NewPointPolicy(w)
+	for x := 1; x <= numberOfPoints; x = x + 1 {
+		p.Append(float64(x))
+	}
+	// Replace the largest value with an extreme outlier.
+	p.Append(1000000)
+	var result = p.Reduce(TrimmedMean(.1))
+	var expected = 0.0
+	for x := 12; x <= 91; x = x + 1 {
+		expected = expected + float64(x)
+	}
+	expected = expected / 80
+	if !floatEquals(result, expected) {
+		t.Fatalf("trimmed mean calculated incorrectly: %f versus %f", expected, result)
+	}
+	if result = p.Reduce(TrimmedMean(0)); !floatEquals(result, p.Reduce(Avg)) {
+		t.Fatalf("untrimmed mean should equal the average but got %f", result)
+	}
+}
+
+func TestTrimmedMeanEdgeCases(t *testing.T) {
+	if result := TrimmedMean(.1)(NewWindow(3)); !floatEquals(result, 0) {
+		t.Fatalf("empty trimmed mean should be zero but got %f", result)
+	}
+	var w = Window{{1, 2, 3}, {4, 100}}
+	if result := TrimmedMean(.5)(w); !floatEquals(result, 3) {
+		t.Fatalf("fully trimmed mean should be the median but got %f", result)
+	}
+	w = Window{{1, 2, 3, 100}}
+	if result := TrimmedMean(.9)(w); !floatEquals(result, 2.5) {
+		t.Fatalf("fully trimmed mean should be the median but got %f", result)
+	}
+}
+
+func TestIQR(t *testing.T) {
+	var numberOfPoints = 100
+	var w = NewWindow(numberOfPoints)
+	var p = NewPointPolicy(w)
+	for x := 1; x <= numberOfPoints; x = x + 1 {
+		p.Append(float64(x))
+	}
+	var result = p.Reduce(IQR)
+	var expected = p.Reduce(Percentile(75)) - p.Reduce(Percentile(25))
+	if !floatEquals(result, expected) || !floatEquals(result, 50) {
+		t.Fatalf("iqr calculated incorrectly: %f versus %f", expected, result)
+	}
+	if result = IQR(NewWindow(2)); !floatEquals(result, 0) {
+		t.Fatalf("empty iqr should be zero but got %f", result)
+	}
+}
+
+func TestEntropy(t *testing.T) {
+	var w = NewWindow(1)
+	if result := Entropy(1)(w); !floatEquals(result, 0) {
+		t.Fatalf("empty entropy should be zero but got %f", result)
+	}
+	w[0] = []float64{5, 5, 5, 5}
+	if result := Entropy(1)(w); !floatEquals(result, 0) {
+		t.Fatalf("constant entropy should be zero but got %f", result)
+	}
+	w[0] = []float64{0, 1, 2, 3, 4, 5, 6, 7}
+	if result := Entropy(0)(w); !floatEquals(result, 3) {
+		t.Fatalf("uniform entropy calculated incorrectly: %f versus %f", 3.0, result)
+	}
+	if result := Entropy(2)(w); !floatEquals(result, 2) {
+		t.Fatalf("binned entropy calculated incorrectly: %f versus %f", 2.0, result)
+	}
+	w[0] = []float64{-1.5, -0.5, 0.5, 1.5}
+	if result := Entropy(1)(w); !floatEquals(result, 2) {
+		t.Fatalf("negative binned entropy calculated incorrectly: %f versus %f", 2.0, result)
+	}
+}
+
 var aggregateResult float64
 
 type policy interface {
@@ -249,6 +635,8 @@ func BenchmarkAggregates(b *testing.B) {
 		{aggregate: Percentile(99.9), aggregateName: "p99.9"},
 		{aggregate: FastPercentile(50.0), aggregateName: "fp50"},
 		{aggregate: FastPercentile(99.9), aggregateName: "fp99.9"},
+		{aggregate: TrimmedMean(.05), aggregateName: "trimmedmean5"},
+		{aggregate: IQR, aggregateName: "iqr"},
 	}
 	var insertions = []int{1, 1000, 10000, 100000}
 	var benchCases = make([]*aggregateBench, 0, len(baseCases)*len(insertions))
@@ -279,3 +667,53 @@ func BenchmarkAggregates(b *testing.B) {
 		})
 	}
 }
+
+// referencePercentile is a direct transcription of Hyndman and Fan definition
+// 5 used to verify the Percentile implementation.
+func referencePercentile(values []float64, perc float64) float64 {
+	var sorted = append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	var n = float64(len(sorted))
+	var p = perc / 100
+	if p < .5/n {
+		return sorted[0]
+	}
+	if p >= (n-.5)/n {
+		return sorted[len(sorted)-1]
+	}
+	var j = math.Floor(n*p + .5)
+	var g = n*p + .5 - j
+	return (1-g)*sorted[int(j)-1] + g*sorted[int(j)]
+}
+
+func TestPercentileMatchesReference(t *testing.T) {
+	var values = []float64{15.92, 0.02, 38.62, 10.15, 0.74, 22.37, 3.39, 0.83, 0.15, 15.43, 34.60}
+	var percentiles = []float64{0, 0.1, 1, 5, 10, 25, 33.3, 50, 75, 90, 95, 99, 99.9, 100}
+	for size := 1; size <= len(values); size = size + 1 {
+		var w = NewWindow(size)
+		var p = NewPointPolicy(w)
+		for _, v := range values[:size] {
+			p.Append(v)
+		}
+		for _, perc := range percentiles {
+			var result = p.Reduce(Percentile(perc))
+			var expected = referencePercentile(values[:size], perc)
+			if !floatEquals(result, expected) {
+				t.Fatalf("%f percentile of %d values calculated incorrectly: %f versus %f", perc, size, expected, result)
+			}
+		}
+	}
+}
+
+func TestPercentileLowPercentileDoesNotPanic(t *testing.T) {
+	var numberOfPoints = 10
+	var w = NewWindow(numberOfPoints)
+	var p = NewPointPolicy(w)
+	for x := 1; x <= numberOfPoints; x = x + 1 {
+		p.Append(float64(x))
+	}
+	var result = p.Reduce(Percentile(0))
+	if !floatEquals(result, 1) {
+		t.Fatalf("0 percentile calculated incorrectly: %f versus %f", 1.0, result)
+	}
+}
@@ -0,0 +1,5 @@
+// Package replay drives recorded streams of timestamped values through
+// rolling windows using simulated time. This allows the configuration of a
+// circuit breaker, limiter, or alert to be evaluated offline against traffic
+// captured from production rather than waiting for it to happen again.
+package replay
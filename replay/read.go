@@ -0,0 +1,115 @@
+package replay
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Event is a single recorded value and the time it was observed.
+type Event struct {
+	Timestamp time.Time `json:"timestamp"`
+	Value     float64   `json:"value"`
+}
+
+// ReadCSV reads events from CSV rows of a timestamp followed by a value, such
+// as those written by the WriteCSV methods of the rolling package. An
+// optional header row is skipped when its first column is "timestamp".
+// Timestamps may be RFC 3339 strings or a number of seconds since the Unix
+// epoch.
+func ReadCSV(r io.Reader) ([]Event, error) {
+	var reader = csv.NewReader(r)
+	reader.FieldsPerRecord = 2
+	reader.TrimLeadingSpace = true
+	var events = make([]Event, 0)
+	for line := 1; ; line = line + 1 {
+		var record, err = reader.Read()
+		if err == io.EOF {
+			return events, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("replay: %s", err)
+		}
+		if line == 1 && strings.EqualFold(record[0], "timestamp") {
+			continue
+		}
+		var timestamp, errTime = parseTimestamp(record[0])
+		if errTime != nil {
+			return nil, fmt.Errorf("replay: line %d: %s", line, errTime)
+		}
+		var value, errValue = strconv.ParseFloat(record[1], 64)
+		if errValue != nil {
+			return nil, fmt.Errorf("replay: line %d: invalid value %q", line, record[1])
+		}
+		events = append(events, Event{Timestamp: timestamp, Value: value})
+	}
+}
+
+func parseTimestamp(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+		return t, nil
+	}
+	var seconds, err = strconv.ParseFloat(s, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid timestamp %q", s)
+	}
+	var whole, fraction = math.Modf(seconds)
+	return time.Unix(int64(whole), int64(fraction*float64(time.Second))), nil
+}
+
+// ReadJSON reads events from either a JSON array of objects or a stream of
+// newline delimited objects. Each object has a "timestamp" that is an RFC
+// 3339 string and a numeric "value".
+func ReadJSON(r io.Reader) ([]Event, error) {
+	var buffered = bufio.NewReader(r)
+	var decoder = json.NewDecoder(buffered)
+	var first, err = peekNonSpace(buffered)
+	if err == io.EOF {
+		return make([]Event, 0), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("replay: %s", err)
+	}
+	if first == '[' {
+		var events = make([]Event, 0)
+		if err = decoder.Decode(&events); err != nil {
+			return nil, fmt.Errorf("replay: %s", err)
+		}
+		return events, nil
+	}
+	var events = make([]Event, 0)
+	for {
+		var e Event
+		err = decoder.Decode(&e)
+		if err == io.EOF {
+			return events, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("replay: event %d: %s", len(events)+1, err)
+		}
+		events = append(events, e)
+	}
+}
+
+// peekNonSpace returns the first byte of the reader that is not whitespace
+// without consuming it.
+func peekNonSpace(r *bufio.Reader) (byte, error) {
+	for {
+		var b, err = r.Peek(1)
+		if err != nil {
+			return 0, err
+		}
+		switch b[0] {
+		case ' ', '\t', '\r', '\n':
+			_, _ = r.ReadByte()
+		default:
+			return b[0], nil
+		}
+	}
+}
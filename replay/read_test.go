@@ -0,0 +1,81 @@
+package replay
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/asecurityteam/rolling"
+)
+
+func TestReadCSV(t *testing.T) {
+	var events, err = ReadCSV(strings.NewReader("timestamp,value\n1970-01-01T00:00:01Z,2\n1.5, 3\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("expected two events but got %v", events)
+	}
+	if !events[0].Timestamp.Equal(time.Unix(1, 0)) || events[0].Value != 2 {
+		t.Fatalf("unexpected event %v", events[0])
+	}
+	if !events[1].Timestamp.Equal(time.Unix(1, 500000000)) || events[1].Value != 3 {
+		t.Fatalf("unexpected event %v", events[1])
+	}
+
+	for _, input := range []string{"soon,1\n", "1,many\n", "1,2,3\n"} {
+		if _, err = ReadCSV(strings.NewReader(input)); err == nil {
+			t.Fatalf("expected an error reading %q", input)
+		}
+	}
+}
+
+func TestReadCSVRoundTrip(t *testing.T) {
+	var c = &clock{now: time.Unix(0, 0)}
+	var p = rolling.NewTimePolicy(rolling.NewWindow(3), time.Second, rolling.WithClock(c))
+	p.Append(1)
+	c.now = c.now.Add(time.Second)
+	p.Append(2)
+	var buffer = &bytes.Buffer{}
+	if err := p.WriteCSV(buffer); err != nil {
+		t.Fatal(err)
+	}
+	var events, err = ReadCSV(buffer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 2 || events[0].Value != 1 || events[1].Value != 2 {
+		t.Fatalf("unexpected events %v", events)
+	}
+}
+
+func TestReadJSON(t *testing.T) {
+	var inputs = []string{
+		`[{"timestamp": "1970-01-01T00:00:01Z", "value": 2}, {"timestamp": "1970-01-01T00:00:02Z", "value": 3}]`,
+		"{\"timestamp\": \"1970-01-01T00:00:01Z\", \"value\": 2}\n{\"timestamp\": \"1970-01-01T00:00:02Z\", \"value\": 3}\n",
+	}
+	for _, input := range inputs {
+		var events, err = ReadJSON(strings.NewReader(input))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(events) != 2 || !events[1].Timestamp.Equal(time.Unix(2, 0)) || events[1].Value != 3 {
+			t.Fatalf("unexpected events %v from %s", events, input)
+		}
+	}
+	if events, err := ReadJSON(strings.NewReader("  ")); err != nil || len(events) != 0 {
+		t.Fatalf("expected no events from empty input but got %v, %v", events, err)
+	}
+	if _, err := ReadJSON(strings.NewReader(`{"timestamp": 1}`)); err == nil {
+		t.Fatal("expected an error for an invalid timestamp")
+	}
+}
+
+type clock struct {
+	now time.Time
+}
+
+func (c *clock) Now() time.Time {
+	return c.now
+}
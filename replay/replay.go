@@ -0,0 +1,92 @@
+package replay
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/asecurityteam/rolling"
+)
+
+// Replay plays a recorded set of events in timestamp order. It is also the
+// rolling.Clock of the replay and must be given to every window that the
+// events are driven through with rolling.WithClock so that the windows
+// observe the recorded time rather than the real time.
+type Replay struct {
+	events []Event
+	now    time.Time
+	lock   *sync.Mutex
+}
+
+// New creates a Replay of the given events. The events are sorted by their
+// timestamp, keeping the recorded order of events with equal timestamps, and
+// the clock begins at the time of the first event.
+func New(events []Event) *Replay {
+	var sorted = append([]Event(nil), events...)
+	sort.SliceStable(sorted, func(i int, j int) bool {
+		return sorted[i].Timestamp.Before(sorted[j].Timestamp)
+	})
+	var r = &Replay{
+		events: sorted,
+		lock:   &sync.Mutex{},
+	}
+	if len(sorted) > 0 {
+		r.now = sorted[0].Timestamp
+	}
+	return r
+}
+
+// Now returns the simulated time of the replay.
+func (r *Replay) Now() time.Time {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return r.now
+}
+
+func (r *Replay) set(now time.Time) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.now = now
+}
+
+// Run moves the clock to the time of each event, in order, and calls the
+// given function with the event.
+func (r *Replay) Run(feed func(Event)) {
+	for _, e := range r.events {
+		r.set(e.Timestamp)
+		feed(e)
+	}
+}
+
+// RunEvery is the same as Run but also calls the tick function each time the
+// given interval elapses in simulated time, starting one interval after the
+// first event. Ticks are delivered before any event that comes after them so
+// that a tick observes the windows exactly as they were at that time. This is
+// used to sample the decisions that would have been made, such as whether a
+// breaker was open, throughout the replay.
+func (r *Replay) RunEvery(interval time.Duration, feed func(Event), tick func(now time.Time)) {
+	if len(r.events) == 0 || interval <= 0 {
+		r.Run(feed)
+		return
+	}
+	var next = r.events[0].Timestamp.Add(interval)
+	for _, e := range r.events {
+		for !next.After(e.Timestamp) {
+			r.set(next)
+			tick(next)
+			next = next.Add(interval)
+		}
+		r.set(e.Timestamp)
+		feed(e)
+	}
+}
+
+// Append returns a function for Run that appends the value of each event to
+// the given window.
+func Append(p rolling.Policy) func(Event) {
+	return func(e Event) {
+		p.Append(e.Value)
+	}
+}
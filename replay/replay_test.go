@@ -0,0 +1,50 @@
+package replay
+
+import (
+	"testing"
+	"time"
+
+	"github.com/asecurityteam/rolling"
+)
+
+func TestReplay(t *testing.T) {
+	var r = New([]Event{
+		{Timestamp: time.Unix(4, 0), Value: 3},
+		{Timestamp: time.Unix(0, 0), Value: 1},
+		{Timestamp: time.Unix(1, 0), Value: 2},
+	})
+	if !r.Now().Equal(time.Unix(0, 0)) {
+		t.Fatalf("expected the clock to start at the first event but got %s", r.Now())
+	}
+	var p = rolling.NewTimePolicy(rolling.NewWindow(2), time.Second, rolling.WithClock(r))
+	var seen = make([]float64, 0)
+	r.Run(func(e Event) {
+		seen = append(seen, e.Value)
+		Append(p)(e)
+	})
+	if len(seen) != 3 || seen[0] != 1 || seen[1] != 2 || seen[2] != 3 {
+		t.Fatalf("expected events in timestamp order but got %v", seen)
+	}
+	// Only the event at four seconds remains within the two second window.
+	if result := p.Reduce(rolling.Sum); result != 3 {
+		t.Fatalf("expected the window to follow the recorded time but got %f", result)
+	}
+}
+
+func TestReplayEvery(t *testing.T) {
+	var r = New([]Event{
+		{Timestamp: time.Unix(0, 0), Value: 1},
+		{Timestamp: time.Unix(2, 0), Value: 1},
+		{Timestamp: time.Unix(5, 0), Value: 1},
+	})
+	var p = rolling.NewTimePolicy(rolling.NewWindow(10), time.Second, rolling.WithClock(r))
+	var counts = make([]float64, 0)
+	r.RunEvery(2*time.Second, Append(p), func(now time.Time) {
+		counts = append(counts, p.Reduce(rolling.Count))
+	})
+	// Ticks at two and four seconds observe the windows before the events
+	// that follow them.
+	if len(counts) != 2 || counts[0] != 1 || counts[1] != 2 {
+		t.Fatalf("unexpected counts at each tick %v", counts)
+	}
+}
@@ -0,0 +1,123 @@
+package rolling
+
+import (
+	"container/heap"
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// rescaleThreshold is the largest exponent used to weight a sample before
+// the landmark time of a ForwardDecayPolicy is moved forward. It keeps the
+// weights well within the range of a float64.
+const rescaleThreshold = 100.0
+
+type reservoirEntry struct {
+	priority float64
+	offset   int
+}
+
+// reservoirHeap is a min-heap of samples ordered by priority such that the
+// least important sample is always the next to be replaced.
+type reservoirHeap []reservoirEntry
+
+func (h reservoirHeap) Len() int            { return len(h) }
+func (h reservoirHeap) Less(i, j int) bool  { return h[i].priority < h[j].priority }
+func (h reservoirHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *reservoirHeap) Push(x interface{}) { *h = append(*h, x.(reservoirEntry)) }
+func (h *reservoirHeap) Pop() interface{} {
+	var old = *h
+	var entry = old[len(old)-1]
+	*h = old[:len(old)-1]
+	return entry
+}
+
+// ForwardDecayPolicy is a window implementation that maintains a random
+// sample of all values ever appended where newer values are exponentially
+// more likely to be retained than older values. Unlike a PointPolicy, old
+// values are not forgotten all at once but become gradually less represented
+// as time passes. This is the forward decay sampling technique described by
+// Cormode et al. in "Forward Decay: A Practical Time Decay Model for
+// Streaming Systems".
+type ForwardDecayPolicy struct {
+	window   Window
+	samples  reservoirHeap
+	alpha    float64
+	landmark time.Time
+	random   *rand.Rand
+	clock    Clock
+	lock     *sync.Mutex
+}
+
+// NewForwardDecayPolicy generates a Policy that samples, at most, a number of
+// values equal to the size of the given window. The alpha controls how
+// strongly the sample favors recent values: a value appended one second
+// later than another is e^alpha times more likely to be retained. Each bucket
+// will contain, at most, one data point and buckets are empty until they
+// receive a sample.
+func NewForwardDecayPolicy(window Window, alpha float64, options ...TimePolicyOption) *ForwardDecayPolicy {
+	var o = newTimeOptions(options)
+	for offset := range window {
+		window[offset] = make([]float64, 0, 1)
+	}
+	var now = o.clock.Now()
+	return &ForwardDecayPolicy{
+		window:   window,
+		samples:  make(reservoirHeap, 0, len(window)),
+		alpha:    alpha,
+		landmark: now,
+		// Sampling does not require a cryptographically secure source.
+		random: rand.New(rand.NewSource(now.UnixNano())), // nolint: gosec
+		clock:  o.clock,
+		lock:   &sync.Mutex{},
+	}
+}
+
+// rescale moves the landmark time forward and adjusts the priority of every
+// sample to match. The relative order of the samples is unchanged.
+func (w *ForwardDecayPolicy) rescale(now time.Time) {
+	var factor = math.Exp(-w.alpha * now.Sub(w.landmark).Seconds())
+	for offset := range w.samples {
+		w.samples[offset].priority = w.samples[offset].priority * factor
+	}
+	w.landmark = now
+}
+
+// Append a value to the window. The value may replace an existing sample or
+// be discarded depending on its randomly assigned priority.
+func (w *ForwardDecayPolicy) Append(value float64) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if len(w.window) < 1 {
+		return
+	}
+	var now = w.clock.Now()
+	if w.alpha*now.Sub(w.landmark).Seconds() > rescaleThreshold {
+		w.rescale(now)
+	}
+	var weight = math.Exp(w.alpha * now.Sub(w.landmark).Seconds())
+	var priority = weight / (1 - w.random.Float64())
+
+	if len(w.samples) < len(w.window) {
+		var offset = len(w.samples)
+		w.window[offset] = append(w.window[offset][:0], value)
+		heap.Push(&w.samples, reservoirEntry{priority: priority, offset: offset})
+		return
+	}
+	if priority <= w.samples[0].priority {
+		return
+	}
+	w.window[w.samples[0].offset][0] = value
+	w.samples[0].priority = priority
+	heap.Fix(&w.samples, 0)
+}
+
+// Reduce the window to a single value using a reduction function.
+func (w *ForwardDecayPolicy) Reduce(f func(Window) float64) float64 {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	return f(w.window)
+}
@@ -0,0 +1,59 @@
+package rolling
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestForwardDecayWindowSize(t *testing.T) {
+	var c = &testClock{now: time.Unix(0, 0)}
+	var p = NewForwardDecayPolicy(NewWindow(10), .1, WithClock(c))
+	if result := p.Reduce(Count); !floatEquals(result, 0) {
+		t.Fatalf("new window should be empty but has %f values", result)
+	}
+	for x := 0; x < 5; x = x + 1 {
+		p.Append(1)
+	}
+	if result := p.Reduce(Count); !floatEquals(result, 5) {
+		t.Fatalf("window should contain every value until full but has %f", result)
+	}
+	for x := 0; x < 1000; x = x + 1 {
+		p.Append(1)
+	}
+	if result := p.Reduce(Count); !floatEquals(result, 10) {
+		t.Fatalf("window should contain 10 samples but has %f", result)
+	}
+}
+
+func TestForwardDecayWindowFavorsRecentValues(t *testing.T) {
+	var c = &testClock{now: time.Unix(0, 0)}
+	var p = NewForwardDecayPolicy(NewWindow(10), 1, WithClock(c))
+	for x := 0; x < 100; x = x + 1 {
+		p.Append(0)
+	}
+	c.now = c.now.Add(time.Minute)
+	for x := 0; x < 100; x = x + 1 {
+		p.Append(1)
+	}
+	if result := p.Reduce(Sum); !floatEquals(result, 10) {
+		t.Fatalf("window should contain only recent values but summed to %f", result)
+	}
+}
+
+func TestForwardDecayWindowRescale(t *testing.T) {
+	var c = &testClock{now: time.Unix(0, 0)}
+	var p = NewForwardDecayPolicy(NewWindow(10), 1, WithClock(c))
+	for x := 0; x < 1000; x = x + 1 {
+		p.Append(float64(x))
+		c.now = c.now.Add(time.Second)
+	}
+	for _, s := range p.samples {
+		if math.IsInf(s.priority, 0) || math.IsNaN(s.priority) {
+			t.Fatalf("sample priority overflowed: %f", s.priority)
+		}
+	}
+	if result := p.Reduce(Min); result < 900 {
+		t.Fatalf("window should contain only recent values but has a minimum of %f", result)
+	}
+}
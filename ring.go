@@ -0,0 +1,85 @@
+package rolling
+
+import "time"
+
+// ring assigns the buckets of a window to consecutive period indexes such
+// that the window always holds the most recent periods. It only performs the
+// arithmetic of the rotation: the owner of the ring stores the buckets and
+// empties each one as the ring advances past it. Every bucketed window in this
+// package, including the TimePolicy, rotates through a ring.
+type ring struct {
+	numberOfBuckets int64
+	bucketSizeNano  int64
+	originNano      int64
+	last            int64
+	started         bool
+}
+
+// newRing creates a ring of the given number of buckets that begins at the
+// first index given to advance.
+func newRing(buckets int) ring {
+	return ring{numberOfBuckets: int64(buckets)}
+}
+
+// newTimeRing creates a ring of buckets that each cover the given duration,
+// measured from the given origin. The ring begins at the origin as though it
+// had been advancing since then.
+func newTimeRing(buckets int, bucketDuration time.Duration, originNano int64) ring {
+	return ring{
+		numberOfBuckets: int64(buckets),
+		bucketSizeNano:  bucketDuration.Nanoseconds(),
+		originNano:      originNano,
+		started:         true,
+	}
+}
+
+// index converts a time into the number of buckets since the origin of a
+// time ring.
+func (r *ring) index(timestamp time.Time) int64 {
+	return floorDiv(timestamp.UnixNano()-r.originNano, r.bucketSizeNano)
+}
+
+// start converts an index of a time ring into the time at which its bucket
+// begins.
+func (r *ring) start(index int64) time.Time {
+	return time.Unix(0, r.originNano+index*r.bucketSizeNano)
+}
+
+// advance moves the ring forward to the given index and calls clear with the
+// offset of every bucket whose period has expired, from the oldest to the
+// newest. Moving a full window or more clears each bucket once. The bucket of
+// the given index last held the period one window earlier so it is cleared as
+// well. Indexes at or before the most recent are ignored.
+func (r *ring) advance(index int64, clear func(offset int)) {
+	if !r.started {
+		r.last = index
+		r.started = true
+		return
+	}
+	if index <= r.last {
+		return
+	}
+	var end = index
+	if end-r.last > r.numberOfBuckets {
+		end = r.last + r.numberOfBuckets
+	}
+	for bucket := r.last + 1; bucket <= end; bucket = bucket + 1 {
+		clear(r.offset(bucket))
+	}
+	r.last = index
+}
+
+// contains reports whether the bucket for the given index is still within the
+// window.
+func (r *ring) contains(index int64) bool {
+	return r.started && index <= r.last && r.last-index < r.numberOfBuckets
+}
+
+// offset converts an index into the position of its bucket.
+func (r *ring) offset(index int64) int {
+	var offset = index % r.numberOfBuckets
+	if offset < 0 {
+		offset = offset + r.numberOfBuckets
+	}
+	return int(offset)
+}
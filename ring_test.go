@@ -0,0 +1,46 @@
+package rolling
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRingAdvance(t *testing.T) {
+	var r = newRing(4)
+	var cleared []int
+	var clear = func(offset int) {
+		cleared = append(cleared, offset)
+	}
+	r.advance(5, clear)
+	if len(cleared) != 0 || !r.contains(5) || r.contains(6) || r.contains(1) {
+		t.Fatalf("expected the ring to begin at the first index but cleared %v", cleared)
+	}
+	r.advance(7, clear)
+	if len(cleared) != 2 || cleared[0] != 2 || cleared[1] != 3 {
+		t.Fatalf("expected offsets 2 and 3 to be cleared but got %v", cleared)
+	}
+	r.advance(6, clear)
+	if len(cleared) != 2 {
+		t.Fatalf("expected an older index to be ignored but got %v", cleared)
+	}
+	if !r.contains(4) || r.contains(3) || r.contains(8) {
+		t.Fatal("expected the ring to contain only indexes 4 through 7")
+	}
+	cleared = cleared[:0]
+	r.advance(100, clear)
+	if len(cleared) != 4 || cleared[0] != 0 || cleared[1] != 1 || cleared[2] != 2 || cleared[3] != 3 {
+		t.Fatalf("expected every offset to be cleared once, oldest first, but got %v", cleared)
+	}
+}
+
+func TestRingTime(t *testing.T) {
+	var origin = time.Unix(0, int64(30*time.Second))
+	var r = newTimeRing(10, time.Minute, origin.UnixNano())
+	var index = r.index(origin.Add(-time.Second))
+	if index != -1 || r.offset(index) != 9 {
+		t.Fatalf("expected index -1 at offset 9 but got %d at %d", index, r.offset(index))
+	}
+	if start := r.start(2); !start.Equal(origin.Add(2 * time.Minute)) {
+		t.Fatalf("expected bucket 2 to begin at %v but got %v", origin.Add(2*time.Minute), start)
+	}
+}
@@ -0,0 +1,79 @@
+package rollingtest
+
+import (
+	"math"
+	"testing"
+
+	"github.com/asecurityteam/rolling"
+)
+
+// AssertWindow fails the test if the contents of the window managed by the
+// given policy do not match the expected buckets. Values within each bucket
+// are compared in order. Empty and nil buckets are considered equal.
+func AssertWindow(t testing.TB, p rolling.Policy, expected rolling.Window) {
+	t.Helper()
+
+	var actual rolling.Window
+	p.Reduce(func(w rolling.Window) float64 {
+		actual = make(rolling.Window, len(w))
+		for offset, bucket := range w {
+			actual[offset] = append([]float64(nil), bucket...)
+		}
+		return 0
+	})
+	if len(actual) != len(expected) {
+		t.Errorf("expected %d buckets but got %d: %v", len(expected), len(actual), actual)
+		return
+	}
+	for offset := range expected {
+		if !bucketEquals(actual[offset], expected[offset]) {
+			t.Errorf("bucket %d was %v but expected %v", offset, actual[offset], expected[offset])
+		}
+	}
+}
+
+// AssertReduce fails the test if reducing the policy with the given function
+// does not produce the expected value.
+func AssertReduce(t testing.TB, p rolling.Policy, f func(rolling.Window) float64, expected float64) {
+	t.Helper()
+
+	var actual = p.Reduce(f)
+	if actual != expected {
+		t.Errorf("reduced to %f but expected %f", actual, expected)
+	}
+}
+
+// AssertAggregate fails the test if the given aggregating function does not
+// produce the expected value from the given window.
+func AssertAggregate(t testing.TB, f func(rolling.Window) float64, w rolling.Window, expected float64) {
+	t.Helper()
+
+	var actual = f(w)
+	if actual != expected {
+		t.Errorf("aggregated to %f but expected %f", actual, expected)
+	}
+}
+
+// AssertReduceWithin fails the test if reducing the policy with the given
+// function does not produce a value within epsilon of the expected value.
+// This is useful for aggregations that accumulate floating point error.
+func AssertReduceWithin(t testing.TB, r rolling.Reducer, f func(rolling.Window) float64, expected float64, epsilon float64) {
+	t.Helper()
+
+	var actual = r.Reduce(f)
+	if math.Abs(actual-expected) > epsilon {
+		t.Errorf("reduced to %f but expected %f within %f", actual, expected, epsilon)
+	}
+}
+
+func bucketEquals(a []float64, b []float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for offset := range a {
+		if a[offset] != b[offset] {
+			return false
+		}
+	}
+	return true
+}
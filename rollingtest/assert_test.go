@@ -0,0 +1,81 @@
+package rollingtest
+
+import (
+	"testing"
+
+	"github.com/asecurityteam/rolling"
+)
+
+type recordingTB struct {
+	testing.TB
+	failed bool
+}
+
+func (r *recordingTB) Helper() {}
+
+func (r *recordingTB) Errorf(string, ...interface{}) {
+	r.failed = true
+}
+
+func TestAssertWindow(t *testing.T) {
+	var p = rolling.NewPointPolicy(rolling.NewWindow(2))
+	p.Append(1)
+	p.Append(2)
+
+	var tb = &recordingTB{}
+	AssertWindow(tb, p, rolling.Window{{1}, {2}})
+	if tb.failed {
+		t.Fatal("matching window reported as a failure")
+	}
+	AssertWindow(tb, p, rolling.Window{{2}, {1}})
+	if !tb.failed {
+		t.Fatal("mismatched window not reported as a failure")
+	}
+	tb = &recordingTB{}
+	AssertWindow(tb, p, rolling.Window{{1}})
+	if !tb.failed {
+		t.Fatal("mismatched bucket count not reported as a failure")
+	}
+}
+
+func TestAssertReduce(t *testing.T) {
+	var p = rolling.NewPointPolicy(rolling.NewWindow(2))
+	p.Append(1)
+	p.Append(2)
+
+	var tb = &recordingTB{}
+	AssertReduce(tb, p, rolling.Sum, 3)
+	if tb.failed {
+		t.Fatal("matching reduction reported as a failure")
+	}
+	AssertReduce(tb, p, rolling.Sum, 4)
+	if !tb.failed {
+		t.Fatal("mismatched reduction not reported as a failure")
+	}
+}
+
+func TestAssertAggregate(t *testing.T) {
+	var tb = &recordingTB{}
+	AssertAggregate(tb, rolling.Sum, rolling.Window{{1, 2}, {3}}, 6)
+	if tb.failed {
+		t.Fatal("matching aggregate reported as a failure")
+	}
+	AssertAggregate(tb, rolling.Sum, rolling.Window{{1, 2}, {3}}, 5)
+	if !tb.failed {
+		t.Fatal("mismatched aggregate not reported as a failure")
+	}
+}
+
+func TestAssertReduceWithin(t *testing.T) {
+	var w = NewStaticValues(0.1, 0.2)
+
+	var tb = &recordingTB{}
+	AssertReduceWithin(tb, w, rolling.Sum, 0.3, 1e-9)
+	if tb.failed {
+		t.Fatal("close reduction reported as a failure")
+	}
+	AssertReduceWithin(tb, w, rolling.Sum, 0.4, 1e-9)
+	if !tb.failed {
+		t.Fatal("distant reduction not reported as a failure")
+	}
+}
@@ -0,0 +1,46 @@
+package rollingtest
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock is a rolling.Clock implementation that only changes time when told
+// to. It is safe for concurrent use.
+type Clock struct {
+	now  time.Time
+	lock *sync.Mutex
+}
+
+// NewClock creates a Clock that reports the given time until it is advanced.
+func NewClock(start time.Time) *Clock {
+	return &Clock{
+		now:  start,
+		lock: &sync.Mutex{},
+	}
+}
+
+// Now returns the current time of the Clock.
+func (c *Clock) Now() time.Time {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.now
+}
+
+// Set the current time of the Clock.
+func (c *Clock) Set(now time.Time) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.now = now
+}
+
+// Advance the Clock by the given duration and return the new current time.
+func (c *Clock) Advance(d time.Duration) time.Time {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.now = c.now.Add(d)
+	return c.now
+}
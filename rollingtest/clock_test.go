@@ -0,0 +1,42 @@
+package rollingtest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/asecurityteam/rolling"
+)
+
+func TestClockAdvance(t *testing.T) {
+	var start = time.Unix(100, 0)
+	var c = NewClock(start)
+	if !c.Now().Equal(start) {
+		t.Fatalf("expected %v but got %v", start, c.Now())
+	}
+	var next = c.Advance(time.Second)
+	if !next.Equal(time.Unix(101, 0)) || !c.Now().Equal(next) {
+		t.Fatalf("expected %v but got %v", time.Unix(101, 0), c.Now())
+	}
+	c.Set(start)
+	if !c.Now().Equal(start) {
+		t.Fatalf("expected %v but got %v", start, c.Now())
+	}
+}
+
+func TestClockDrivesTimePolicy(t *testing.T) {
+	var c = NewClock(time.Unix(0, 0))
+	var p = rolling.NewTimePolicy(rolling.NewWindow(3), time.Second, rolling.WithClock(c))
+	p.Append(1)
+	c.Advance(time.Second)
+	p.Append(2)
+	p.Append(2)
+	AssertWindow(t, p, rolling.Window{{1}, {2, 2}, nil})
+	AssertReduce(t, p, rolling.Sum, 5)
+
+	c.Advance(2 * time.Second)
+	p.Append(3)
+	AssertWindow(t, p, rolling.Window{{3}, {2, 2}, nil})
+
+	c.Advance(time.Minute)
+	AssertReduce(t, p, rolling.Count, 0)
+}
@@ -0,0 +1,3 @@
+// Package rollingtest provides utilities for testing code that is built on
+// rolling windows without depending on the passage of real time.
+package rollingtest
@@ -0,0 +1,116 @@
+package rollingtest
+
+import (
+	"sync"
+
+	"github.com/asecurityteam/rolling"
+)
+
+// StaticWindow is a rolling.Policy that always reduces the same fixed
+// contents. Appended values do not change the window and are instead
+// recorded so that a test may inspect what the code under test appended. It
+// is safe for concurrent use.
+type StaticWindow struct {
+	window   rolling.Window
+	appended []float64
+	lock     *sync.Mutex
+}
+
+// NewStaticWindow creates a StaticWindow containing the given buckets. The
+// buckets are copied so later changes to the given window have no effect.
+func NewStaticWindow(window rolling.Window) *StaticWindow {
+	var copied = make(rolling.Window, len(window))
+	for offset, bucket := range window {
+		copied[offset] = append([]float64(nil), bucket...)
+	}
+	return &StaticWindow{
+		window: copied,
+		lock:   &sync.Mutex{},
+	}
+}
+
+// NewStaticValues creates a StaticWindow with one bucket for each of the
+// given values.
+func NewStaticValues(values ...float64) *StaticWindow {
+	var window = make(rolling.Window, len(values))
+	for offset, value := range values {
+		window[offset] = []float64{value}
+	}
+	return NewStaticWindow(window)
+}
+
+// Append records the value without changing the window.
+func (w *StaticWindow) Append(value float64) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	w.appended = append(w.appended, value)
+}
+
+// Appended returns every value given to Append in the order they were
+// appended.
+func (w *StaticWindow) Appended() []float64 {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	return append([]float64(nil), w.appended...)
+}
+
+// Reduce the fixed contents to a single value using a reduction function.
+func (w *StaticWindow) Reduce(f func(rolling.Window) float64) float64 {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	return f(w.window)
+}
+
+// StubAggregator returns a scripted sequence of values regardless of its
+// input. Once the sequence is exhausted the last value is repeated, or zero is
+// returned if the sequence is empty. Its Aggregate method may be given
+// anywhere an aggregating function is expected and the StubAggregator itself
+// may be given anywhere a rolling.Reducer is expected. It is safe for
+// concurrent use.
+type StubAggregator struct {
+	values []float64
+	calls  int
+	lock   *sync.Mutex
+}
+
+// NewStubAggregator creates a StubAggregator that returns the given values in
+// order.
+func NewStubAggregator(values ...float64) *StubAggregator {
+	return &StubAggregator{
+		values: values,
+		lock:   &sync.Mutex{},
+	}
+}
+
+// Aggregate returns the next value of the sequence.
+func (s *StubAggregator) Aggregate(rolling.Window) float64 {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	var offset = s.calls
+	s.calls = s.calls + 1
+	switch {
+	case len(s.values) == 0:
+		return 0
+	case offset >= len(s.values):
+		return s.values[len(s.values)-1]
+	}
+	return s.values[offset]
+}
+
+// Reduce returns the next value of the sequence without calling the given
+// function.
+func (s *StubAggregator) Reduce(func(rolling.Window) float64) float64 {
+	return s.Aggregate(nil)
+}
+
+// Calls returns the number of values that have been returned.
+func (s *StubAggregator) Calls() int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.calls
+}
@@ -0,0 +1,40 @@
+package rollingtest
+
+import (
+	"testing"
+
+	"github.com/asecurityteam/rolling"
+)
+
+func TestStaticWindow(t *testing.T) {
+	var seed = rolling.Window{{1, 2}, {3}}
+	var w = NewStaticWindow(seed)
+	seed[0][0] = 10
+	w.Append(4)
+	w.Append(5)
+	AssertWindow(t, w, rolling.Window{{1, 2}, {3}})
+	if appended := w.Appended(); len(appended) != 2 || appended[0] != 4 || appended[1] != 5 {
+		t.Fatalf("unexpected appended values %v", appended)
+	}
+	AssertReduce(t, NewStaticValues(1, 2, 3), rolling.Count, 3)
+}
+
+func TestStubAggregator(t *testing.T) {
+	var s = NewStubAggregator(1, 2)
+	var p rolling.Reducer = s
+	if result := p.Reduce(rolling.Sum); result != 1 {
+		t.Fatalf("expected the first value but got %f", result)
+	}
+	if result := s.Aggregate(nil); result != 2 {
+		t.Fatalf("expected the second value but got %f", result)
+	}
+	if result := s.Aggregate(nil); result != 2 {
+		t.Fatalf("expected the last value to repeat but got %f", result)
+	}
+	if s.Calls() != 3 {
+		t.Fatalf("expected three calls but got %d", s.Calls())
+	}
+	if result := NewStubAggregator().Aggregate(nil); result != 0 {
+		t.Fatalf("expected an empty sequence to return zero but got %f", result)
+	}
+}
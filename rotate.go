@@ -0,0 +1,25 @@
+package rolling
+
+import "time"
+
+// Rotate brings the window up to date with the current time, clearing any
+// buckets that have aged out of it, without appending or reading a value.
+func (w *TimePolicy) Rotate() {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	w.keepConsistent(w.ring.index(w.clock.Now()))
+}
+
+// RotateEvery calls Rotate each time the given interval elapses, on a new
+// goroutine, until the returned function is called. An interval of zero or
+// less rotates once per bucket duration. Buckets are then cleared on
+// schedule, and the OnExpire and OnEvict hooks called promptly, even while
+// the window is idle rather than on the next read or write. The returned
+// function may be called more than once.
+func (w *TimePolicy) RotateEvery(interval time.Duration) func() {
+	if interval <= 0 {
+		interval = w.BucketSize()
+	}
+	return every(interval, w.Rotate)
+}
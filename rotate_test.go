@@ -0,0 +1,72 @@
+package rolling
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+type lockedClock struct {
+	now  time.Time
+	lock *sync.Mutex
+}
+
+func (c *lockedClock) Now() time.Time {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.now
+}
+
+func (c *lockedClock) Add(d time.Duration) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.now = c.now.Add(d)
+}
+
+func TestTimePolicyRotate(t *testing.T) {
+	var c = &testClock{now: time.Unix(0, 0)}
+	var evicted = 0
+	var p = NewTimePolicy(NewWindow(3), time.Second, WithClock(c), WithHooks(Hooks{
+		OnEvict: func(start time.Time, values []float64) {
+			evicted = evicted + len(values)
+		},
+	}))
+	p.Append(1)
+	c.now = c.now.Add(time.Second)
+	p.Append(2)
+	c.now = c.now.Add(2 * time.Second)
+	p.Rotate()
+	if evicted != 1 {
+		t.Fatalf("expected the oldest bucket to be evicted but got %d", evicted)
+	}
+	// A jump of exactly one window expires everything.
+	c.now = c.now.Add(3 * time.Second)
+	if result := p.Reduce(Sum); result != 0 {
+		t.Fatalf("expected an empty window but got %f", result)
+	}
+}
+
+func TestTimePolicyRotateEvery(t *testing.T) {
+	var c = &lockedClock{now: time.Unix(0, 0), lock: &sync.Mutex{}}
+	var evicted = make(chan float64, 1)
+	var p = NewTimePolicy(NewWindow(3), time.Second, WithClock(c), WithHooks(Hooks{
+		OnEvict: func(start time.Time, values []float64) {
+			evicted <- Sum(Window{values})
+		},
+	}))
+	p.Append(5)
+	var stop = p.RotateEvery(time.Millisecond)
+	defer stop()
+	c.Add(time.Minute)
+	select {
+	case result := <-evicted:
+		if result != 5 {
+			t.Fatalf("expected the appended value to be evicted but got %f", result)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the idle window to be rotated in the background")
+	}
+	stop()
+}
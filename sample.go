@@ -0,0 +1,121 @@
+package rolling
+
+import "sync"
+
+// Sample is a single value along with caller defined metadata that describes
+// it, such as the status code of the request whose latency is the value.
+type Sample struct {
+	Value float64
+	Meta  interface{}
+}
+
+// SamplePolicy is a rolling window policy that tracks the last N samples
+// inserted regardless of insertion time. Keeping the metadata of each value
+// within the same window, rather than in a second parallel window, ensures
+// the two can never disagree about which values are present.
+type SamplePolicy struct {
+	windowSize int
+	window     Window
+	meta       []interface{}
+	filtered   Window
+	offset     int
+	count      int
+	lock       *sync.Mutex
+}
+
+// NewSamplePolicy generates a Policy that operates on a rolling set of input
+// samples. The number of samples is determined by the size of the given
+// window. Each bucket will contain, at most, one value and buckets are empty
+// until they receive a sample.
+func NewSamplePolicy(window Window) *SamplePolicy {
+	for offset := range window {
+		window[offset] = make([]float64, 0, 1)
+	}
+	return &SamplePolicy{
+		windowSize: len(window),
+		window:     window,
+		meta:       make([]interface{}, len(window)),
+		filtered:   Window{make([]float64, 0, len(window))},
+		lock:       &sync.Mutex{},
+	}
+}
+
+// Append a value, without metadata, to the window.
+func (w *SamplePolicy) Append(value float64) {
+	w.AppendSample(Sample{Value: value})
+}
+
+// AppendSample appends a value and its metadata to the window.
+func (w *SamplePolicy) AppendSample(s Sample) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	w.window[w.offset] = append(w.window[w.offset][:0], s.Value)
+	w.meta[w.offset] = s.Meta
+	w.offset = (w.offset + 1) % w.windowSize
+	if w.count < w.windowSize {
+		w.count = w.count + 1
+	}
+}
+
+// Len returns the number of samples currently within the window.
+func (w *SamplePolicy) Len() int {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	return w.count
+}
+
+// Iterate calls the given function with each sample in the window, from the
+// oldest to the newest, until the function returns false.
+func (w *SamplePolicy) Iterate(f func(Sample) bool) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	for x := 0; x < w.count; x = x + 1 {
+		var offset = (w.offset - w.count + x + w.windowSize) % w.windowSize
+		if !f(Sample{Value: w.window[offset][0], Meta: w.meta[offset]}) {
+			return
+		}
+	}
+}
+
+// IterateReverse calls the given function with each sample in the window,
+// from the newest to the oldest, until the function returns false.
+func (w *SamplePolicy) IterateReverse(f func(Sample) bool) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	for x := 1; x <= w.count; x = x + 1 {
+		var offset = (w.offset - x + w.windowSize) % w.windowSize
+		if !f(Sample{Value: w.window[offset][0], Meta: w.meta[offset]}) {
+			return
+		}
+	}
+}
+
+// Reduce the values of the window to a single value using a reduction
+// function.
+func (w *SamplePolicy) Reduce(f func(Window) float64) float64 {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	return f(w.window)
+}
+
+// ReduceWhere reduces only the values of the samples that match the given
+// function. The reduction function is given a Window with a single bucket
+// that contains every matching value.
+func (w *SamplePolicy) ReduceWhere(match func(Sample) bool, f func(Window) float64) float64 {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	var bucket = w.filtered[0][:0]
+	for offset, b := range w.window {
+		if len(b) > 0 && match(Sample{Value: b[0], Meta: w.meta[offset]}) {
+			bucket = append(bucket, b[0])
+		}
+	}
+	w.filtered[0] = bucket
+	return f(w.filtered)
+}
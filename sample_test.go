@@ -0,0 +1,67 @@
+package rolling
+
+import "testing"
+
+func TestSampleWindowIterate(t *testing.T) {
+	var p = NewSamplePolicy(NewWindow(3))
+	for x := 1; x <= 4; x = x + 1 {
+		p.AppendSample(Sample{Value: float64(x), Meta: x * 100})
+	}
+	var values []float64
+	var codes []int
+	p.Iterate(func(s Sample) bool {
+		values = append(values, s.Value)
+		codes = append(codes, s.Meta.(int))
+		return true
+	})
+	if len(values) != 3 || values[0] != 2 || values[1] != 3 || values[2] != 4 {
+		t.Fatalf("expected oldest to newest values but got %v", values)
+	}
+	if codes[0] != 200 || codes[1] != 300 || codes[2] != 400 {
+		t.Fatalf("metadata did not stay with its value: %v", codes)
+	}
+
+	var newest []float64
+	p.IterateReverse(func(s Sample) bool {
+		newest = append(newest, s.Value)
+		return true
+	})
+	if len(newest) != 3 || newest[0] != 4 || newest[2] != 2 {
+		t.Fatalf("expected newest to oldest values but got %v", newest)
+	}
+
+	var seen = 0
+	p.Iterate(func(s Sample) bool {
+		seen = seen + 1
+		return false
+	})
+	if seen != 1 {
+		t.Fatalf("iteration should stop early but saw %d samples", seen)
+	}
+}
+
+func TestSampleWindowReduceWhere(t *testing.T) {
+	var p = NewSamplePolicy(NewWindow(10))
+	p.AppendSample(Sample{Value: 10, Meta: 200})
+	p.AppendSample(Sample{Value: 250, Meta: 500})
+	p.AppendSample(Sample{Value: 30, Meta: 200})
+	p.Append(5)
+
+	if p.Len() != 4 {
+		t.Fatalf("expected 4 samples but got %d", p.Len())
+	}
+	if result := p.Reduce(Sum); !floatEquals(result, 295) {
+		t.Fatalf("sum calculated incorrectly: %f versus %f", 295.0, result)
+	}
+	var serverErrors = func(s Sample) bool {
+		var code, ok = s.Meta.(int)
+		return ok && code >= 500
+	}
+	if result := p.ReduceWhere(serverErrors, Count); !floatEquals(result, 1) {
+		t.Fatalf("count calculated incorrectly: %f versus %f", 1.0, result)
+	}
+	var ok = func(s Sample) bool { return s.Meta == 200 }
+	if result := p.ReduceWhere(ok, Avg); !floatEquals(result, 20) {
+		t.Fatalf("avg calculated incorrectly: %f versus %f", 20.0, result)
+	}
+}
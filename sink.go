@@ -0,0 +1,87 @@
+package rolling
+
+import (
+	"context"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// Sink receives evaluated metrics. Implementations may forward them to a
+// message queue, a metrics service, or any other pipeline.
+type Sink interface {
+	Write(ctx context.Context, values []NamedValue) error
+}
+
+// SinkFunc adapts a function to the Sink interface.
+type SinkFunc func(ctx context.Context, values []NamedValue) error
+
+// Write calls the function.
+func (f SinkFunc) Write(ctx context.Context, values []NamedValue) error {
+	return f(ctx, values)
+}
+
+// ChannelSink returns a Sink that sends each set of values to the given
+// channel. A write blocks until the values are received or the context is
+// done.
+func ChannelSink(ch chan<- []NamedValue) Sink {
+	return SinkFunc(func(ctx context.Context, values []NamedValue) error {
+		select {
+		case ch <- values:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	})
+}
+
+// WriterSink returns a Sink that writes each value to the given writer as a
+// line containing the name and the value separated by a space.
+func WriterSink(w io.Writer) Sink {
+	return SinkFunc(func(ctx context.Context, values []NamedValue) error {
+		var b []byte
+		for _, v := range values {
+			b = append(b, v.Name...)
+			b = append(b, ' ')
+			b = strconv.AppendFloat(b, v.Value, 'g', -1, 64)
+			b = append(b, '\n')
+		}
+		var _, err = w.Write(b)
+		return err
+	})
+}
+
+// Evaluate computes the Value of every given Metric and returns the results
+// ordered by name. Each result is named by the key of its Metric in the map,
+// rather than by the Name of the Metric, so that every Metric is reported
+// even when several are unnamed or share a Name.
+func Evaluate(metrics map[string]*Metric) []NamedValue {
+	var values = EvaluateAll(metrics, 0)
+	var result = make([]NamedValue, 0, len(values))
+	for name, value := range values {
+		result = append(result, NamedValue{Name: name, Value: value})
+	}
+	sort.Slice(result, func(i int, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+// WriteEvery evaluates the given Metrics and writes them to the Sink each
+// time the interval elapses until the returned function is called. Calling
+// the returned function also cancels the context of any write in progress.
+// Errors returned by the Sink are given to the error handler, which may be
+// nil to discard them.
+func WriteEvery(interval time.Duration, sink Sink, metrics map[string]*Metric, onError func(error)) func() {
+	var ctx, cancel = context.WithCancel(context.Background())
+	var stop = every(interval, func() {
+		if err := sink.Write(ctx, Evaluate(metrics)); err != nil && onError != nil {
+			onError(err)
+		}
+	})
+	return func() {
+		cancel()
+		stop()
+	}
+}
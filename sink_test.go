@@ -0,0 +1,97 @@
+package rolling
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func newTestMetrics() map[string]*Metric {
+	var p = NewPointPolicy(NewWindow(2))
+	p.Append(1)
+	p.Append(2)
+	return map[string]*Metric{
+		"sum": {Name: "sum", Policy: p, Aggregate: Sum},
+		"max": {Name: "max", Policy: p, Aggregate: Max},
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	var values = Evaluate(newTestMetrics())
+	if len(values) != 2 || values[0] != (NamedValue{Name: "max", Value: 2}) || values[1] != (NamedValue{Name: "sum", Value: 3}) {
+		t.Fatalf("unexpected values %v", values)
+	}
+}
+
+func TestWriterSink(t *testing.T) {
+	var b = &bytes.Buffer{}
+	if err := WriterSink(b).Write(context.Background(), Evaluate(newTestMetrics())); err != nil {
+		t.Fatal(err)
+	}
+	if b.String() != "max 2\nsum 3\n" {
+		t.Fatalf("unexpected output %q", b.String())
+	}
+}
+
+func TestChannelSink(t *testing.T) {
+	var ch = make(chan []NamedValue, 1)
+	var sink = ChannelSink(ch)
+	if err := sink.Write(context.Background(), []NamedValue{{Name: "a", Value: 1}}); err != nil {
+		t.Fatal(err)
+	}
+	if values := <-ch; len(values) != 1 || values[0].Name != "a" {
+		t.Fatalf("unexpected values %v", values)
+	}
+	var ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	ch <- nil
+	if err := sink.Write(ctx, nil); err == nil {
+		t.Fatal("expected an error writing to a full channel with a cancelled context")
+	}
+}
+
+func TestWriteEvery(t *testing.T) {
+	var ch = make(chan []NamedValue)
+	var errs = make(chan error, 1)
+	var stop = WriteEvery(time.Millisecond, ChannelSink(ch), newTestMetrics(), nil)
+	defer stop()
+	select {
+	case values := <-ch:
+		if len(values) != 2 {
+			t.Fatalf("unexpected values %v", values)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for values")
+	}
+	stop()
+
+	var stopFailing = WriteEvery(time.Millisecond, SinkFunc(func(context.Context, []NamedValue) error {
+		return errors.New("unavailable")
+	}), nil, func(err error) {
+		select {
+		case errs <- err:
+		default:
+		}
+	})
+	defer stopFailing()
+	select {
+	case <-errs:
+	case <-time.After(time.Second):
+		t.Fatal("expected the error handler to be called")
+	}
+}
+
+func TestEvaluateUnnamed(t *testing.T) {
+	var p = NewPointPolicy(NewWindow(2))
+	p.Append(1)
+	p.Append(2)
+	var values = Evaluate(map[string]*Metric{
+		"sum": From(p).Sum().Build(),
+		"max": From(p).Max().Build(),
+	})
+	if len(values) != 2 || values[0] != (NamedValue{Name: "max", Value: 2}) || values[1] != (NamedValue{Name: "sum", Value: 3}) {
+		t.Fatalf("expected every unnamed metric by its key but got %v", values)
+	}
+}
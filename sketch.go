@@ -0,0 +1,324 @@
+package rolling
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"sort"
+)
+
+// sketchVersion is the first byte of an encoded Sketch.
+const sketchVersion = 1
+
+// sketchMinValue is the smallest magnitude that a Sketch distinguishes from
+// zero.
+const sketchMinValue = 1e-9
+
+var (
+	errInvalidSketch  = errors.New("rolling: invalid sketch encoding")
+	errSketchAccuracy = errors.New("rolling: sketches have different accuracy")
+	errSketchRange    = errors.New("rolling: sketch accuracy must be between zero and one")
+)
+
+// Sketch is a compact summary of a distribution from which any percentile can
+// be computed to within a fixed relative error. It is an implementation of
+// DDSketch: each value is counted in a bucket whose bounds grow exponentially
+// so that the memory needed depends on the range of the values rather than
+// on how many there are. Sketches with the same accuracy may be merged
+// without any loss, and encoded to be shipped between processes, so that a
+// central aggregator can compute true percentiles across many services.
+type Sketch struct {
+	accuracy float64
+	gamma    float64
+	logGamma float64
+	positive map[int]uint64
+	negative map[int]uint64
+	zero     uint64
+	count    uint64
+	sum      float64
+	min      float64
+	max      float64
+}
+
+// NewSketch generates an empty Sketch whose percentiles are within the given
+// relative accuracy, such as 0.01 for 1%, of the true value. An error is
+// returned if the accuracy is not between zero and one.
+func NewSketch(accuracy float64) (*Sketch, error) {
+	if !(accuracy > 0 && accuracy < 1) {
+		return nil, errSketchRange
+	}
+	return newSketch(accuracy), nil
+}
+
+// newSketch generates an empty Sketch with an accuracy that is already known
+// to be between zero and one.
+func newSketch(accuracy float64) *Sketch {
+	var gamma = (1 + accuracy) / (1 - accuracy)
+	return &Sketch{
+		accuracy: accuracy,
+		gamma:    gamma,
+		logGamma: math.Log(gamma),
+		positive: make(map[int]uint64),
+		negative: make(map[int]uint64),
+		min:      math.Inf(1),
+		max:      math.Inf(-1),
+	}
+}
+
+// SketchOf reduces a window into a new Sketch with the given accuracy. An
+// error is returned if the accuracy is not between zero and one.
+func SketchOf(r Reducer, accuracy float64) (*Sketch, error) {
+	var s, err = NewSketch(accuracy)
+	if err != nil {
+		return nil, err
+	}
+	r.Reduce(func(w Window) float64 {
+		for _, bucket := range w {
+			for _, value := range bucket {
+				s.Add(value)
+			}
+		}
+		return 0
+	})
+	return s, nil
+}
+
+// key returns the bucket of a positive value.
+func (s *Sketch) key(value float64) int {
+	return int(math.Ceil(math.Log(value) / s.logGamma))
+}
+
+// value returns the point within a bucket that is within the accuracy of
+// every value in the bucket.
+func (s *Sketch) value(key int) float64 {
+	return 2 * math.Pow(s.gamma, float64(key)) / (s.gamma + 1)
+}
+
+// Add a value to the sketch. NaN has no place in a distribution and is
+// ignored.
+func (s *Sketch) Add(value float64) {
+	switch {
+	case math.IsNaN(value):
+		return
+	case value >= sketchMinValue:
+		s.positive[s.key(value)] = s.positive[s.key(value)] + 1
+	case value <= -sketchMinValue:
+		s.negative[s.key(-value)] = s.negative[s.key(-value)] + 1
+	default:
+		s.zero = s.zero + 1
+	}
+	s.count = s.count + 1
+	s.sum = s.sum + value
+	s.min = math.Min(s.min, value)
+	s.max = math.Max(s.max, value)
+}
+
+// Merge the values of another sketch into this one. An error is returned, and
+// the sketch is unchanged, if the sketches have different accuracy.
+func (s *Sketch) Merge(other *Sketch) error {
+	if s.accuracy != other.accuracy {
+		return errSketchAccuracy
+	}
+	for key, count := range other.positive {
+		s.positive[key] = s.positive[key] + count
+	}
+	for key, count := range other.negative {
+		s.negative[key] = s.negative[key] + count
+	}
+	s.zero = s.zero + other.zero
+	s.count = s.count + other.count
+	s.sum = s.sum + other.sum
+	s.min = math.Min(s.min, other.min)
+	s.max = math.Max(s.max, other.max)
+	return nil
+}
+
+// Accuracy returns the relative accuracy of the percentiles of the sketch.
+func (s *Sketch) Accuracy() float64 {
+	return s.accuracy
+}
+
+// Count returns the number of values added to the sketch.
+func (s *Sketch) Count() uint64 {
+	return s.count
+}
+
+// Sum returns the sum of the values added to the sketch.
+func (s *Sketch) Sum() float64 {
+	return s.sum
+}
+
+func sortedKeys(bins map[int]uint64) []int {
+	var keys = make([]int, 0, len(bins))
+	for key := range bins {
+		keys = append(keys, key)
+	}
+	sort.Ints(keys)
+	return keys
+}
+
+// Percentile returns the value at the given percentile, between 0 and 100,
+// to within the accuracy of the sketch. An empty sketch returns zero.
+func (s *Sketch) Percentile(perc float64) float64 {
+	if s.count < 1 {
+		return 0
+	}
+	var rank = perc / 100 * float64(s.count-1)
+	var seen = 0.0
+	var result = s.max
+	var negative = sortedKeys(s.negative)
+	for x := len(negative) - 1; x >= 0; x = x - 1 {
+		seen = seen + float64(s.negative[negative[x]])
+		if seen > rank {
+			return math.Max(s.min, math.Min(s.max, -s.value(negative[x])))
+		}
+	}
+	seen = seen + float64(s.zero)
+	if seen > rank {
+		return 0
+	}
+	for _, key := range sortedKeys(s.positive) {
+		seen = seen + float64(s.positive[key])
+		if seen > rank {
+			result = s.value(key)
+			break
+		}
+	}
+	return math.Max(s.min, math.Min(s.max, result))
+}
+
+// Estimate returns the value at the given percentile along with the number of
+// values it was computed from and the confidence bound on the percentile it
+// represents. The value itself is additionally within the accuracy of the
+// sketch.
+func (s *Sketch) Estimate(perc float64) Estimate {
+	return Estimate{
+		Value:      s.Percentile(perc),
+		Percentile: perc,
+		Samples:    int(s.count),
+		Epsilon:    percentileEpsilon(perc, int(s.count)),
+	}
+}
+
+func appendVarint(b []byte, v int64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	return append(b, buf[:binary.PutVarint(buf[:], v)]...)
+}
+
+func appendSketchBins(b []byte, bins map[int]uint64) []byte {
+	var keys = sortedKeys(bins)
+	b = appendUvarint(b, uint64(len(keys)))
+	for _, key := range keys {
+		b = appendVarint(b, int64(key))
+		b = appendUvarint(b, bins[key])
+	}
+	return b
+}
+
+// MarshalBinary encodes the sketch in a compact binary form: a version byte,
+// the accuracy, minimum, maximum, and sum as little endian float64s, the count
+// of zeros as a varint, and then the positive and negative buckets, each as a
+// varint count of buckets followed by a signed varint key and varint count
+// for every bucket.
+func (s *Sketch) MarshalBinary() ([]byte, error) {
+	var b = make([]byte, 0, 34+4*(len(s.positive)+len(s.negative)))
+	b = append(b, sketchVersion)
+	b = appendFixed64(b, math.Float64bits(s.accuracy))
+	b = appendFixed64(b, math.Float64bits(s.min))
+	b = appendFixed64(b, math.Float64bits(s.max))
+	b = appendFixed64(b, math.Float64bits(s.sum))
+	b = appendUvarint(b, s.zero)
+	b = appendSketchBins(b, s.positive)
+	b = appendSketchBins(b, s.negative)
+	return b, nil
+}
+
+// sketchDecoder reads the encoding produced by MarshalBinary. The first error
+// encountered is retained and every later read returns zero.
+type sketchDecoder struct {
+	b   []byte
+	err error
+}
+
+func (d *sketchDecoder) float() float64 {
+	if d.err != nil || len(d.b) < 8 {
+		d.err = errInvalidSketch
+		return 0
+	}
+	var v = math.Float64frombits(binary.LittleEndian.Uint64(d.b))
+	d.b = d.b[8:]
+	return v
+}
+
+func (d *sketchDecoder) uvarint() uint64 {
+	if d.err != nil {
+		return 0
+	}
+	var v, n = binary.Uvarint(d.b)
+	if n <= 0 {
+		d.err = errInvalidSketch
+		return 0
+	}
+	d.b = d.b[n:]
+	return v
+}
+
+func (d *sketchDecoder) varint() int64 {
+	if d.err != nil {
+		return 0
+	}
+	var v, n = binary.Varint(d.b)
+	if n <= 0 {
+		d.err = errInvalidSketch
+		return 0
+	}
+	d.b = d.b[n:]
+	return v
+}
+
+func (d *sketchDecoder) bins(s *Sketch, bins map[int]uint64) {
+	var size = d.uvarint()
+	// Every bucket takes at least two bytes so a larger size is corrupt.
+	if size > uint64(len(d.b)) {
+		d.err = errInvalidSketch
+		return
+	}
+	for x := uint64(0); x < size && d.err == nil; x = x + 1 {
+		var key = int(d.varint())
+		var count = d.uvarint()
+		bins[key] = bins[key] + count
+		s.count = s.count + count
+	}
+}
+
+// UnmarshalBinary decodes the sketch from the encoding produced by
+// MarshalBinary, replacing its contents.
+func (s *Sketch) UnmarshalBinary(b []byte) error {
+	if len(b) < 1 || b[0] != sketchVersion {
+		return errInvalidSketch
+	}
+	var d = &sketchDecoder{b: b[1:]}
+	var accuracy = d.float()
+	if d.err == nil && !(accuracy > 0 && accuracy < 1) {
+		return errInvalidSketch
+	}
+	var decoded = newSketch(accuracy)
+	decoded.min = d.float()
+	decoded.max = d.float()
+	decoded.sum = d.float()
+	if math.IsNaN(decoded.min) || math.IsNaN(decoded.max) {
+		return errInvalidSketch
+	}
+	decoded.zero = d.uvarint()
+	decoded.count = decoded.zero
+	d.bins(decoded, decoded.positive)
+	d.bins(decoded, decoded.negative)
+	if d.err != nil {
+		return d.err
+	}
+	if len(d.b) > 0 {
+		return errInvalidSketch
+	}
+	*s = *decoded
+	return nil
+}
@@ -0,0 +1,134 @@
+package rolling
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+)
+
+func TestSketch(t *testing.T) {
+	var p = NewPointPolicy(NewWindow(1000))
+	for x := 1; x <= 1000; x = x + 1 {
+		p.Append(float64(x))
+	}
+	var s, err = SketchOf(p, 0.01)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Count() != 1000 || s.Sum() != 500500 {
+		t.Fatalf("expected 1000 values summing to 500500 but got %d %f", s.Count(), s.Sum())
+	}
+	for _, perc := range []float64{25, 50, 90, 99, 99.9} {
+		var exact = p.Reduce(Percentile(perc))
+		var estimate = s.Percentile(perc)
+		if math.Abs(estimate-exact)/exact > 0.02 {
+			t.Fatalf("expected p%v to be within 2%% of %f but got %f", perc, exact, estimate)
+		}
+	}
+	if p100 := s.Percentile(100); p100 != 1000 {
+		t.Fatalf("expected p100 to be the largest value but got %f", p100)
+	}
+	var e = s.Estimate(99)
+	if e.Samples != 1000 || e.Epsilon <= 0 || e.Exact {
+		t.Fatalf("expected an estimate with an error bound but got %+v", e)
+	}
+	var empty, _ = NewSketch(0.01)
+	if v := empty.Percentile(50); v != 0 {
+		t.Fatalf("expected zero for an empty sketch but got %f", v)
+	}
+}
+
+func TestSketchNegativeAndZero(t *testing.T) {
+	var s, _ = NewSketch(0.01)
+	for _, v := range []float64{-100, -10, 0, 0, 10, 100} {
+		s.Add(v)
+	}
+	if p0 := s.Percentile(0); p0 != -100 {
+		t.Fatalf("expected p0 to be the smallest value but got %f", p0)
+	}
+	if p50 := s.Percentile(50); p50 != 0 {
+		t.Fatalf("expected a median of zero but got %f", p50)
+	}
+	if p20 := s.Percentile(20); math.Abs(p20+10) > 0.2 {
+		t.Fatalf("expected p20 to be near -10 but got %f", p20)
+	}
+}
+
+func TestSketchMerge(t *testing.T) {
+	var a, _ = NewSketch(0.01)
+	var b, _ = NewSketch(0.01)
+	for x := 1; x <= 500; x = x + 1 {
+		a.Add(float64(x))
+		b.Add(float64(x + 500))
+	}
+	if err := a.Merge(b); err != nil {
+		t.Fatal(err)
+	}
+	if a.Count() != 1000 {
+		t.Fatalf("expected the values of both sketches but got %d", a.Count())
+	}
+	if p50 := a.Percentile(50); math.Abs(p50-500)/500 > 0.02 {
+		t.Fatalf("expected a median near 500 but got %f", p50)
+	}
+	var other, _ = NewSketch(0.05)
+	if err := a.Merge(other); err == nil {
+		t.Fatal("expected an error for sketches with different accuracy")
+	}
+}
+
+func TestSketchEncoding(t *testing.T) {
+	var s, _ = NewSketch(0.02)
+	for _, v := range []float64{-3, 0, 1.5, 20, 20, 4000} {
+		s.Add(v)
+	}
+	var b, err = s.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded, _ = NewSketch(0.5)
+	if err = decoded.UnmarshalBinary(b); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Accuracy() != 0.02 || decoded.Count() != s.Count() || decoded.Sum() != s.Sum() {
+		t.Fatalf("expected the decoded sketch to match but got %+v", decoded)
+	}
+	for _, perc := range []float64{0, 25, 50, 75, 100} {
+		if decoded.Percentile(perc) != s.Percentile(perc) {
+			t.Fatalf("expected p%v of %f but got %f", perc, s.Percentile(perc), decoded.Percentile(perc))
+		}
+	}
+	if err = decoded.UnmarshalBinary(b[:len(b)-1]); err == nil {
+		t.Fatal("expected an error for a truncated encoding")
+	}
+	if err = decoded.UnmarshalBinary(append(b, 0)); err == nil {
+		t.Fatal("expected an error for trailing data")
+	}
+	if decoded.Count() != s.Count() {
+		t.Fatal("expected a failed decode to leave the sketch unchanged")
+	}
+}
+
+func TestSketchInvalid(t *testing.T) {
+	for _, accuracy := range []float64{0, -0.01, 1, 2, math.NaN()} {
+		if _, err := NewSketch(accuracy); err == nil {
+			t.Fatalf("expected an error for an accuracy of %f", accuracy)
+		}
+		if _, err := SketchOf(NewPointPolicy(NewWindow(1)), accuracy); err == nil {
+			t.Fatalf("expected an error for an accuracy of %f", accuracy)
+		}
+	}
+
+	var s, _ = NewSketch(0.01)
+	s.Add(1)
+	s.Add(math.NaN())
+	s.Add(3)
+	if s.Count() != 2 || s.Sum() != 4 || s.Percentile(0) != 1 || math.Abs(s.Percentile(100)-3) > 0.03 {
+		t.Fatalf("expected NaN to be ignored but got %d values from %f to %f", s.Count(), s.Percentile(0), s.Percentile(100))
+	}
+
+	var b, _ = s.MarshalBinary()
+	binary.LittleEndian.PutUint64(b[9:], math.Float64bits(math.NaN()))
+	if err := s.UnmarshalBinary(b); err == nil {
+		t.Fatal("expected an error for an encoding with a NaN minimum")
+	}
+}
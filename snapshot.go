@@ -0,0 +1,47 @@
+package rolling
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Snapshotter publishes immutable copies of a window for readers. Readers
+// reduce the most recently published copy without taking any lock so that
+// frequent reads, such as by several exporters, never wait on a busy writer
+// and never delay it. The trade off is that readers see the window as it was
+// when it was last published rather than as it is now.
+type Snapshotter struct {
+	source  Reducer
+	current atomic.Value
+}
+
+// NewSnapshotter generates a Snapshotter for the given window and publishes
+// its first snapshot.
+func NewSnapshotter(source Reducer) *Snapshotter {
+	var s = &Snapshotter{source: source}
+	s.Publish()
+	return s
+}
+
+// Publish replaces the snapshot seen by readers with a copy of the current
+// contents of the window.
+func (s *Snapshotter) Publish() {
+	s.current.Store(Freeze(s.source))
+}
+
+// PublishEvery publishes a new snapshot each time the given interval
+// elapses until the returned function is called.
+func (s *Snapshotter) PublishEvery(interval time.Duration) func() {
+	return every(interval, s.Publish)
+}
+
+// Snapshot returns the most recently published snapshot.
+func (s *Snapshotter) Snapshot() *Frozen {
+	return s.current.Load().(*Frozen)
+}
+
+// Reduce the most recently published snapshot to a single value using a
+// reduction function. No lock is taken.
+func (s *Snapshotter) Reduce(f func(Window) float64) float64 {
+	return s.Snapshot().Reduce(f)
+}
@@ -0,0 +1,66 @@
+package rolling
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSnapshotter(t *testing.T) {
+	var p = NewPointPolicy(NewWindow(5))
+	p.Append(1)
+	var s = NewSnapshotter(p)
+	p.Append(2)
+	if result := s.Reduce(Sum); !floatEquals(result, 1) {
+		t.Fatalf("expected the published snapshot but got %f", result)
+	}
+	s.Publish()
+	if result := s.Reduce(Sum); !floatEquals(result, 3) {
+		t.Fatalf("expected the newest snapshot but got %f", result)
+	}
+	var frozen = s.Snapshot()
+	p.Append(3)
+	s.Publish()
+	if result := frozen.Reduce(Sum); !floatEquals(result, 3) {
+		t.Fatalf("expected an earlier snapshot to be unchanged but got %f", result)
+	}
+}
+
+func TestSnapshotterPublishEvery(t *testing.T) {
+	var p = NewPointPolicy(NewWindow(5))
+	var s = NewSnapshotter(p)
+	var stop = s.PublishEvery(time.Millisecond)
+	defer stop()
+	p.Append(1)
+	var deadline = time.Now().Add(time.Second)
+	for s.Reduce(Sum) != 1 {
+		if time.Now().After(deadline) {
+			t.Fatal("expected a snapshot to be published")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	stop()
+}
+
+func TestSnapshotterDataRace(t *testing.T) {
+	var p = NewTimePolicy(NewWindow(10), time.Millisecond)
+	var s = NewSnapshotter(p)
+	var wg = &sync.WaitGroup{}
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for x := 0; x < 1000; x = x + 1 {
+			p.Append(1)
+			if x%10 == 0 {
+				s.Publish()
+			}
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for x := 0; x < 1000; x = x + 1 {
+			_ = s.Reduce(Sum)
+		}
+	}()
+	wg.Wait()
+}
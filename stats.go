@@ -0,0 +1,100 @@
+package rolling
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// lockSampleRate is the number of appends for each one whose wait for the
+// lock is measured. Sampling keeps the cost of measuring low on hot paths.
+const lockSampleRate = 64
+
+// timeCounters are the running totals kept by a TimePolicy for Stats.
+type timeCounters struct {
+	appends     uint64
+	resets      uint64
+	expired     uint64
+	dropped     uint64
+	lockWait    time.Duration
+	lockSamples uint64
+	lockSample  uint32
+	// perBucket is the number of appends made to each bucket of the window
+	// since it was last cleared, including any that were dropped.
+	perBucket []uint64
+}
+
+// Stats describes the operation of a window so that the monitoring built on
+// rolling windows can itself be monitored.
+type Stats struct {
+	// Appends is the number of values appended since the window was created,
+	// including any that were dropped.
+	Appends uint64
+	// AppendRate is the number of values appended per second, including any
+	// that were dropped, over the time the window covers or over the age of
+	// the window if it is younger. Only appends to the buckets still in the
+	// window are counted so the rate follows the traffic as it changes.
+	AppendRate float64
+	// Resets is the number of times the entire window was cleared at once
+	// because no data arrived for longer than the window.
+	Resets uint64
+	// Expired is the number of values that aged out of the window, whether
+	// one bucket at a time or by a reset.
+	Expired uint64
+	// Dropped is the number of values discarded, or removed to make room,
+	// because the window reached its point limit.
+	Dropped uint64
+	// Values is the number of values currently held by the window.
+	Values int
+	// Bytes is an estimate of the memory used by the window.
+	Bytes int
+	// LockWait is the average time spent waiting for the lock of the window
+	// across a sample of appends. It is zero until enough appends are made
+	// to take a sample.
+	LockWait time.Duration
+}
+
+// acquire takes the lock of the window and occasionally measures how long
+// that took.
+func (w *TimePolicy) acquire() {
+	if atomic.AddUint32(&w.counters.lockSample, 1)%lockSampleRate != 0 {
+		w.lock.Lock()
+		return
+	}
+	var start = time.Now()
+	w.lock.Lock()
+	w.counters.lockWait = w.counters.lockWait + time.Since(start)
+	w.counters.lockSamples = w.counters.lockSamples + 1
+}
+
+// Stats returns the operational statistics of the window.
+func (w *TimePolicy) Stats() Stats {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	w.keepConsistent(w.ring.index(w.clock.Now()))
+	var storage = statsOf(w.window)
+	var s = Stats{
+		Appends: w.counters.appends,
+		Resets:  w.counters.resets,
+		Expired: w.counters.expired,
+		Dropped: w.counters.dropped,
+		Values:  w.size,
+		Bytes:   storage.bytes,
+	}
+	var elapsed = w.clock.Now().Sub(w.created)
+	var duration = w.bucketSize * time.Duration(w.numberOfBuckets)
+	if elapsed > duration || elapsed <= 0 {
+		elapsed = duration
+	}
+	if elapsed > 0 {
+		var appends uint64
+		for _, count := range w.counters.perBucket {
+			appends = appends + count
+		}
+		s.AppendRate = float64(appends) / elapsed.Seconds()
+	}
+	if w.counters.lockSamples > 0 {
+		s.LockWait = w.counters.lockWait / time.Duration(w.counters.lockSamples)
+	}
+	return s
+}
@@ -0,0 +1,50 @@
+package rolling
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimePolicyStats(t *testing.T) {
+	var c = &testClock{now: time.Unix(0, 0)}
+	var p = NewTimePolicy(NewWindow(10), time.Second, WithClock(c), WithPointLimit(20, DropNewest))
+	for x := 0; x < 5; x = x + 1 {
+		c.now = c.now.Add(time.Second)
+		p.AppendBatch([]float64{1, 2, 3, 4, 5})
+	}
+	var s = p.Stats()
+	if s.Appends != 25 || s.Dropped != 5 || s.Values != 20 {
+		t.Fatalf("unexpected counts %+v", s)
+	}
+	if !floatEquals(s.AppendRate, 5) {
+		t.Fatalf("expected 25 appends over 5 seconds but got %f", s.AppendRate)
+	}
+	if s.Bytes <= 0 {
+		t.Fatalf("expected an estimate of memory but got %d", s.Bytes)
+	}
+
+	c.now = c.now.Add(7 * time.Second)
+	p.Append(1)
+	s = p.Stats()
+	if s.Expired != 10 || s.Resets != 0 {
+		t.Fatalf("expected two buckets to expire but got %+v", s)
+	}
+	if !floatEquals(s.AppendRate, 1.6) {
+		t.Fatalf("expected 16 appends over 10 seconds but got %f", s.AppendRate)
+	}
+	c.now = c.now.Add(time.Minute)
+	s = p.Stats()
+	if s.Expired != 21 || s.Resets != 1 || s.Values != 0 || s.AppendRate != 0 {
+		t.Fatalf("expected the window to reset but got %+v", s)
+	}
+}
+
+func TestTimePolicyStatsLockWait(t *testing.T) {
+	var p = NewTimePolicy(NewWindow(10), time.Second)
+	for x := 0; x < lockSampleRate; x = x + 1 {
+		p.Append(1)
+	}
+	if s := p.Stats(); s.LockWait <= 0 {
+		t.Fatalf("expected a sampled lock wait but got %s", s.LockWait)
+	}
+}
@@ -0,0 +1,75 @@
+package rolling
+
+import (
+	"math"
+	"sort"
+	"sync"
+)
+
+// StreamingPercentile is a rolling window of the last N values that keeps
+// its values sorted as they are appended and evicted. Each append costs a
+// binary search and a copy within the sorted values, rather than a sort of
+// the entire window on every evaluation, and any percentile of the window is
+// then available in constant time.
+type StreamingPercentile struct {
+	points *PointPolicy
+	sorted []float64
+	lock   *sync.RWMutex
+}
+
+// NewStreamingPercentile generates a Policy that operates on a rolling set of
+// input points, as with NewPointPolicy, while maintaining the sorted order of
+// those points.
+func NewStreamingPercentile(window Window) *StreamingPercentile {
+	return &StreamingPercentile{
+		points: NewNoLockPointPolicy(window),
+		sorted: make([]float64, 0, len(window)),
+		lock:   &sync.RWMutex{},
+	}
+}
+
+// Append a value to the window. NaN has no place in the sorted order and is
+// ignored.
+func (w *StreamingPercentile) Append(value float64) {
+	if math.IsNaN(value) {
+		return
+	}
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	var evicted, ok = w.points.AppendEvict(value)
+	if ok {
+		var offset = sort.SearchFloat64s(w.sorted, evicted)
+		if offset < len(w.sorted) && w.sorted[offset] == evicted {
+			w.sorted = append(w.sorted[:offset], w.sorted[offset+1:]...)
+		}
+	}
+	var offset = sort.SearchFloat64s(w.sorted, value)
+	w.sorted = append(w.sorted, 0)
+	copy(w.sorted[offset+1:], w.sorted[offset:])
+	w.sorted[offset] = value
+}
+
+// Percentile returns the given percentile of the values appended to the
+// window, as computed by Percentile, without sorting. Points that have not
+// yet received a value are not included. An empty window has a percentile
+// of zero.
+func (w *StreamingPercentile) Percentile(perc float64) float64 {
+	w.lock.RLock()
+	defer w.lock.RUnlock()
+
+	if len(w.sorted) < 1 {
+		return 0
+	}
+	return percentileOfSorted(w.sorted, perc)
+}
+
+// Reduce the window to a single value using a reduction function. The window
+// is given to the function in sorted order as a single bucket containing only
+// the values that have been appended.
+func (w *StreamingPercentile) Reduce(f func(Window) float64) float64 {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	return f(Window{w.sorted})
+}
@@ -0,0 +1,57 @@
+package rolling
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestStreamingPercentile(t *testing.T) {
+	var s = NewStreamingPercentile(NewWindow(50))
+	var p = NewPointPolicy(NewWindow(50))
+	if result := s.Percentile(50); result != 0 {
+		t.Fatalf("expected zero for an empty window but got %f", result)
+	}
+	var r = rand.New(rand.NewSource(1)) // nolint: gosec
+	for x := 0; x < 500; x = x + 1 {
+		var value = float64(r.Intn(100))
+		s.Append(value)
+		p.Append(value)
+		for _, perc := range []float64{0, 25, 50, 99, 100} {
+			var expected = p.ReduceOrdered(Percentile(perc))
+			if result := s.Percentile(perc); !floatEquals(result, expected) {
+				t.Fatalf("percentile %f after %d values calculated incorrectly: %f versus %f", perc, x+1, expected, result)
+			}
+		}
+	}
+	if result := s.Reduce(Count); !floatEquals(result, 50) {
+		t.Fatalf("expected a full window but counted %f", result)
+	}
+	if result := s.Reduce(Sum); !floatEquals(result, p.Reduce(Sum)) {
+		t.Fatalf("sum calculated incorrectly: %f versus %f", p.Reduce(Sum), result)
+	}
+}
+
+func BenchmarkStreamingPercentile(b *testing.B) {
+	var s = NewStreamingPercentile(NewWindow(1000))
+	var r = rand.New(rand.NewSource(1)) // nolint: gosec
+	b.ResetTimer()
+	for n := 0; n < b.N; n = n + 1 {
+		s.Append(r.Float64())
+		_ = s.Percentile(99)
+	}
+}
+
+func TestStreamingPercentileNaN(t *testing.T) {
+	var s = NewStreamingPercentile(NewWindow(2))
+	s.Append(math.NaN())
+	s.Append(1)
+	s.Append(2)
+	s.Append(3)
+	if result := s.Reduce(Count); result != 2 {
+		t.Fatalf("expected NaN to be ignored but got %f values", result)
+	}
+	if result := s.Percentile(0); result != 2 {
+		t.Fatalf("expected the oldest value to be evicted but got a minimum of %f", result)
+	}
+}
@@ -0,0 +1,90 @@
+package rolling
+
+import (
+	"bytes"
+	"strconv"
+)
+
+// formatFloat formats a value in the shortest form that represents it
+// exactly.
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// describe formats the configuration of a window followed by a description
+// of its current contents. The window is reduced, and so locked, once.
+func describe(name string, config string, r Reducer) string {
+	var b = &bytes.Buffer{}
+	b.WriteString(name)
+	b.WriteString("{")
+	b.WriteString(config)
+	r.Reduce(func(w Window) float64 {
+		var stats = statsOf(w)
+		b.WriteString(" values=")
+		b.WriteString(strconv.Itoa(stats.values))
+		if min, ok := MinOK(w); ok {
+			var max, _ = MaxOK(w)
+			b.WriteString(" min=")
+			b.WriteString(formatFloat(min))
+			b.WriteString(" max=")
+			b.WriteString(formatFloat(max))
+		}
+		return 0
+	})
+	b.WriteString("}")
+	return b.String()
+}
+
+// String describes the configuration and contents of the window, such as
+// "TimePolicy{buckets=60 bucketDuration=1s values=12 min=1 max=9}". It is
+// intended for logs and debugging and the format may change.
+func (w *TimePolicy) String() string {
+	w.lock.Lock()
+	var config = "buckets=" + strconv.Itoa(w.numberOfBuckets) + " bucketDuration=" + w.bucketSize.String()
+	w.lock.Unlock()
+	return describe("TimePolicy", config, w)
+}
+
+// String describes the configuration and contents of the window, such as
+// "PointPolicy{size=5 values=5 min=1 max=9}". It is intended for logs and
+// debugging and the format may change.
+func (w *PointPolicy) String() string {
+	return describe("PointPolicy", "size="+strconv.Itoa(w.Size()), w)
+}
+
+// String describes the configuration and contents of the window, such as
+// "GaugePolicy{size=5 duration=1m0s values=3 min=1 max=9}". It is intended
+// for logs and debugging and the format may change.
+func (w *GaugePolicy) String() string {
+	return describe("GaugePolicy", "size="+strconv.Itoa(w.windowSize)+" duration="+w.duration.String(), w)
+}
+
+// String describes the configuration and contents of the window, such as
+// "BoundedPolicy{size=5 maxAge=1m0s values=3 min=1 max=9}". It is intended
+// for logs and debugging and the format may change.
+func (w *BoundedPolicy) String() string {
+	return describe("BoundedPolicy", "size="+strconv.Itoa(w.windowSize)+" maxAge="+w.maxAge.String(), w)
+}
+
+// String formats the value as "name=value".
+func (v NamedValue) String() string {
+	return v.Name + "=" + formatFloat(v.Value)
+}
+
+// String formats the current value of the metric as "name=value".
+func (m *Metric) String() string {
+	return NamedValue{Name: m.Name, Value: m.Value()}.String()
+}
+
+// String formats every aggregate of the summary as a chain of "name=value"
+// pairs, such as "count=3 sum=6 avg=2 min=1 max=3 p99=3".
+func (s Summary) String() string {
+	var b = &bytes.Buffer{}
+	for offset, v := range s.Flatten("") {
+		if offset > 0 {
+			b.WriteString(" ")
+		}
+		b.WriteString(v.String())
+	}
+	return b.String()
+}
@@ -0,0 +1,68 @@
+package rolling
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestString(t *testing.T) {
+	var c = &testClock{now: time.Unix(0, 0)}
+	var tp = NewTimePolicy(NewWindow(3), time.Second, WithClock(c))
+	if result := tp.String(); result != "TimePolicy{buckets=3 bucketDuration=1s values=0}" {
+		t.Fatalf("unexpected description of an empty window %q", result)
+	}
+	tp.Append(4)
+	tp.Append(-2)
+	if result := fmt.Sprint(tp); result != "TimePolicy{buckets=3 bucketDuration=1s values=2 min=-2 max=4}" {
+		t.Fatalf("unexpected description %q", result)
+	}
+
+	var pp = NewPointPolicy(NewWindow(2))
+	pp.Append(1.5)
+	if result := pp.String(); result != "PointPolicy{size=2 values=2 min=0 max=1.5}" {
+		t.Fatalf("unexpected description %q", result)
+	}
+	var gp = NewGaugePolicy(NewWindow(2), time.Minute, WithClock(c))
+	gp.Append(3)
+	if result := gp.String(); result != "GaugePolicy{size=2 duration=1m0s values=1 min=3 max=3}" {
+		t.Fatalf("unexpected description %q", result)
+	}
+	var bp = NewBoundedPolicy(NewWindow(2), time.Minute, WithClock(c))
+	if result := bp.String(); result != "BoundedPolicy{size=2 maxAge=1m0s values=0}" {
+		t.Fatalf("unexpected description %q", result)
+	}
+
+	var m = &Metric{Name: "latency", Policy: tp, Aggregate: Sum}
+	if result := m.String(); result != "latency=2" {
+		t.Fatalf("unexpected description %q", result)
+	}
+	if result := Summarize(tp, 50).String(); result != "count=2 sum=2 avg=1 min=-2 max=4 p50=1" {
+		t.Fatalf("unexpected description %q", result)
+	}
+}
+
+func TestStringDuringResize(t *testing.T) {
+	var p = NewPointPolicy(NewWindow(5))
+	var done = make(chan struct{})
+	var wg = &sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-done:
+				return
+			default:
+				_ = p.String()
+			}
+		}
+	}()
+	var deadline = time.Now().Add(50 * time.Millisecond)
+	for x := 0; time.Now().Before(deadline); x = x + 1 {
+		p.Resize(1 + x%10)
+	}
+	close(done)
+	wg.Wait()
+}
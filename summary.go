@@ -0,0 +1,110 @@
+package rolling
+
+import (
+	"sort"
+	"strconv"
+)
+
+// Summary contains several common aggregates of the same window. Every field
+// is computed from exactly the same data.
+type Summary struct {
+	Count float64
+	Sum   float64
+	Avg   float64
+	Min   float64
+	Max   float64
+	// Percentiles contains the value of each requested percentile in the
+	// order they were requested.
+	Percentiles []float64
+
+	requested []float64
+}
+
+// NamedValue is a single aggregate along with a name that identifies it.
+type NamedValue struct {
+	Name  string
+	Value float64
+}
+
+// Flatten returns each aggregate of the Summary as a NamedValue so that every
+// aggregate may be exported as a separate metric. The names are the given
+// prefix followed by "count", "sum", "avg", "min", "max", and then the name
+// of each percentile, such as "p99.9", in the order they were requested. The
+// names match those understood by ParseAggregate. A non-empty prefix is
+// separated from the names by a period.
+func (s Summary) Flatten(prefix string) []NamedValue {
+	if prefix != "" {
+		prefix = prefix + "."
+	}
+	var result = []NamedValue{
+		{Name: prefix + "count", Value: s.Count},
+		{Name: prefix + "sum", Value: s.Sum},
+		{Name: prefix + "avg", Value: s.Avg},
+		{Name: prefix + "min", Value: s.Min},
+		{Name: prefix + "max", Value: s.Max},
+	}
+	for offset, perc := range s.requested {
+		result = append(result, NamedValue{
+			Name:  prefix + "p" + strconv.FormatFloat(perc, 'f', -1, 64),
+			Value: s.Percentiles[offset],
+		})
+	}
+	return result
+}
+
+// Summarize computes a Summary of the window, including the given
+// percentiles, while holding the lock of the window only once. The window is
+// iterated a single time and its values are sorted at most once no matter
+// how many percentiles are requested. This is considerably cheaper than
+// reducing the window once for each aggregate when exporting many aggregates
+// of the same window. The percentiles are computed as described by
+// Percentile. An empty window has a Summary of zeros.
+func Summarize(r Reducer, percentiles ...float64) Summary {
+	var s = Summary{Percentiles: make([]float64, len(percentiles)), requested: append([]float64(nil), percentiles...)}
+	var values []float64
+	r.Reduce(func(w Window) float64 {
+		var started = false
+		for _, bucket := range w {
+			for _, p := range bucket {
+				s.Count = s.Count + 1
+				s.Sum = s.Sum + p
+				if !started || p < s.Min {
+					s.Min = p
+				}
+				if !started || p > s.Max {
+					s.Max = p
+				}
+				started = true
+			}
+			if len(percentiles) > 0 {
+				values = append(values, bucket...)
+			}
+		}
+		return 0
+	})
+	if s.Count < 1 {
+		return s
+	}
+	s.Avg = s.Sum / s.Count
+	if len(percentiles) > 0 {
+		sort.Float64s(values)
+		for offset, perc := range percentiles {
+			s.Percentiles[offset] = percentileOfSorted(values, perc)
+		}
+	}
+	return s
+}
+
+// ReduceAll reduces the window with each of the given reduction functions
+// while holding the lock of the window only once. The results are returned in
+// the same order as the functions. Every function sees exactly the same data.
+func ReduceAll(r Reducer, fs ...func(Window) float64) []float64 {
+	var results = make([]float64, len(fs))
+	r.Reduce(func(w Window) float64 {
+		for offset, f := range fs {
+			results[offset] = f(w)
+		}
+		return 0
+	})
+	return results
+}
@@ -0,0 +1,72 @@
+package rolling
+
+import "testing"
+
+func TestSummarize(t *testing.T) {
+	var p = NewPointPolicy(NewWindow(10))
+	for x := 1; x <= 10; x = x + 1 {
+		p.Append(float64(x))
+	}
+	var s = Summarize(p, 50, 90, 99.9)
+	if !floatEquals(s.Count, p.Reduce(Count)) || !floatEquals(s.Sum, p.Reduce(Sum)) ||
+		!floatEquals(s.Avg, p.Reduce(Avg)) || !floatEquals(s.Min, p.Reduce(Min)) ||
+		!floatEquals(s.Max, p.Reduce(Max)) {
+		t.Fatalf("summary calculated incorrectly: %+v", s)
+	}
+	for offset, perc := range []float64{50, 90, 99.9} {
+		var expected = p.Reduce(Percentile(perc))
+		if !floatEquals(s.Percentiles[offset], expected) {
+			t.Fatalf("percentile %f calculated incorrectly: %f versus %f", perc, expected, s.Percentiles[offset])
+		}
+	}
+}
+
+func TestSummarizeEmpty(t *testing.T) {
+	var s = Summarize(NewBoundedPolicy(NewWindow(3), 0), 50)
+	if s.Count != 0 || s.Min != 0 || s.Max != 0 || s.Avg != 0 || s.Percentiles[0] != 0 {
+		t.Fatalf("expected an empty summary but got %+v", s)
+	}
+}
+
+func TestReduceAll(t *testing.T) {
+	var p = NewPointPolicy(NewWindow(4))
+	for x := 1; x <= 4; x = x + 1 {
+		p.Append(float64(x))
+	}
+	var results = ReduceAll(p, Sum, Max, Count)
+	var expected = []float64{10, 4, 4}
+	for offset, result := range results {
+		if !floatEquals(result, expected[offset]) {
+			t.Fatalf("reduction %d calculated incorrectly: %f versus %f", offset, expected[offset], result)
+		}
+	}
+}
+
+func TestSummaryFlatten(t *testing.T) {
+	var p = NewPointPolicy(NewWindow(4))
+	for x := 1; x <= 4; x = x + 1 {
+		p.Append(float64(x))
+	}
+	var s = Summarize(p, 50, 99.9)
+	var expected = []NamedValue{
+		{Name: "latency.count", Value: 4},
+		{Name: "latency.sum", Value: 10},
+		{Name: "latency.avg", Value: 2.5},
+		{Name: "latency.min", Value: 1},
+		{Name: "latency.max", Value: 4},
+		{Name: "latency.p50", Value: 2.5},
+		{Name: "latency.p99.9", Value: 4},
+	}
+	var result = s.Flatten("latency")
+	if len(result) != len(expected) {
+		t.Fatalf("expected %d values but got %d", len(expected), len(result))
+	}
+	for offset, v := range result {
+		if v.Name != expected[offset].Name || !floatEquals(v.Value, expected[offset].Value) {
+			t.Fatalf("expected %v but got %v", expected[offset], v)
+		}
+	}
+	if name := s.Flatten("")[0].Name; name != "count" {
+		t.Fatalf("expected no prefix but got %q", name)
+	}
+}
@@ -0,0 +1,172 @@
+package rolling
+
+import (
+	"hash/fnv"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Tags identify a series of data within a TaggedPolicy. For example, a
+// latency measurement might be tagged with the endpoint and method that
+// produced it.
+type Tags map[string]string
+
+func (t Tags) key() string {
+	var keys = make([]string, 0, len(t))
+	for k := range t {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var b strings.Builder
+	for _, k := range keys {
+		b.WriteString(k)
+		b.WriteByte(0)
+		b.WriteString(t[k])
+		b.WriteByte(0)
+	}
+	return b.String()
+}
+
+// matches reports whether every tag in the filter is present with the same
+// value in the receiver.
+func (t Tags) matches(filter Tags) bool {
+	for k, v := range filter {
+		if value, ok := t[k]; !ok || value != v {
+			return false
+		}
+	}
+	return true
+}
+
+type taggedSeries struct {
+	tags   Tags
+	policy Policy
+}
+
+// keyStripes is the number of independently locked maps across which the
+// series of a TaggedPolicy, or the keys of a Detector, are spread. Updates to
+// keys in different stripes never contend with each other.
+const keyStripes = 64
+
+// stripeOf selects which of the given number of stripes holds a key.
+func stripeOf(key string, stripes int) int {
+	var h = fnv.New32a()
+	_, _ = h.Write([]byte(key))
+	return int(h.Sum32() % uint32(stripes))
+}
+
+type taggedStripe struct {
+	series map[string]*taggedSeries
+	lock   *sync.RWMutex
+}
+
+// TaggedPolicy is a collection of windows where each unique set of Tags is
+// given its own window. Values are appended once, with their tags, and may be
+// reduced for a single series, for any subset of tags, or across all series.
+// The series are spread across a number of separately locked stripes so that
+// many goroutines may append to different series concurrently.
+type TaggedPolicy struct {
+	newPolicy func() Policy
+	stripes   []*taggedStripe
+}
+
+// NewTaggedPolicy generates a Policy that maintains a window per unique set
+// of tags. The given function is called each time a new set of tags is seen
+// and must return a new, unshared Policy such as one from NewPointPolicy or
+// NewTimePolicy.
+func NewTaggedPolicy(newPolicy func() Policy) *TaggedPolicy {
+	var stripes = make([]*taggedStripe, keyStripes)
+	for offset := range stripes {
+		stripes[offset] = &taggedStripe{
+			series: make(map[string]*taggedSeries),
+			lock:   &sync.RWMutex{},
+		}
+	}
+	return &TaggedPolicy{
+		newPolicy: newPolicy,
+		stripes:   stripes,
+	}
+}
+
+func (w *TaggedPolicy) stripe(key string) *taggedStripe {
+	return w.stripes[stripeOf(key, len(w.stripes))]
+}
+
+func (w *TaggedPolicy) lookup(tags Tags) Policy {
+	var key = tags.key()
+	var stripe = w.stripe(key)
+	stripe.lock.RLock()
+	var s, ok = stripe.series[key]
+	stripe.lock.RUnlock()
+	if ok {
+		return s.policy
+	}
+
+	stripe.lock.Lock()
+	defer stripe.lock.Unlock()
+	if s, ok = stripe.series[key]; ok {
+		return s.policy
+	}
+	var copied = make(Tags, len(tags))
+	for k, v := range tags {
+		copied[k] = v
+	}
+	s = &taggedSeries{tags: copied, policy: w.newPolicy()}
+	stripe.series[key] = s
+	return s.policy
+}
+
+// Append a value to the window identified by the given tags.
+func (w *TaggedPolicy) Append(value float64, tags Tags) {
+	w.lookup(tags).Append(value)
+}
+
+// Reduce the windows whose tags contain all of the given tags to a single
+// value using a reduction function. Passing the complete set of tags for a
+// series selects only that series while passing nil or empty tags selects
+// every series. When more than one series matches, the reduction function
+// receives a Window containing the buckets of all matching series.
+func (w *TaggedPolicy) Reduce(tags Tags, f func(Window) float64) float64 {
+	var matched []Policy
+	for _, stripe := range w.stripes {
+		stripe.lock.RLock()
+		for _, s := range stripe.series {
+			if s.tags.matches(tags) {
+				matched = append(matched, s.policy)
+			}
+		}
+		stripe.lock.RUnlock()
+	}
+
+	if len(matched) == 1 {
+		return matched[0].Reduce(f)
+	}
+	var combined Window
+	for _, p := range matched {
+		p.Reduce(func(window Window) float64 {
+			for _, bucket := range window {
+				combined = append(combined, append([]float64(nil), bucket...))
+			}
+			return 0
+		})
+	}
+	return f(combined)
+}
+
+// Tags returns the set of tags for every series that has received data.
+func (w *TaggedPolicy) Tags() []Tags {
+	var result = make([]Tags, 0)
+	for _, stripe := range w.stripes {
+		stripe.lock.RLock()
+		for _, s := range stripe.series {
+			var copied = make(Tags, len(s.tags))
+			for k, v := range s.tags {
+				copied[k] = v
+			}
+			result = append(result, copied)
+		}
+		stripe.lock.RUnlock()
+	}
+	return result
+}
@@ -0,0 +1,108 @@
+package rolling
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func newTestTaggedPolicy() *TaggedPolicy {
+	return NewTaggedPolicy(func() Policy {
+		return NewPointPolicy(NewWindow(10))
+	})
+}
+
+func TestTaggedPolicyReduceSeries(t *testing.T) {
+	var p = newTestTaggedPolicy()
+	p.Append(1, Tags{"endpoint": "/a", "method": "GET"})
+	p.Append(2, Tags{"endpoint": "/a", "method": "POST"})
+	p.Append(4, Tags{"endpoint": "/b", "method": "GET"})
+
+	var result = p.Reduce(Tags{"endpoint": "/a", "method": "POST"}, Sum)
+	if !floatEquals(result, 2) {
+		t.Fatalf("series sum calculated incorrectly: %f versus %f", 2.0, result)
+	}
+	result = p.Reduce(Tags{"endpoint": "/a"}, Sum)
+	if !floatEquals(result, 3) {
+		t.Fatalf("partial tag sum calculated incorrectly: %f versus %f", 3.0, result)
+	}
+	result = p.Reduce(Tags{"method": "GET"}, Sum)
+	if !floatEquals(result, 5) {
+		t.Fatalf("partial tag sum calculated incorrectly: %f versus %f", 5.0, result)
+	}
+	result = p.Reduce(nil, Sum)
+	if !floatEquals(result, 7) {
+		t.Fatalf("overall sum calculated incorrectly: %f versus %f", 7.0, result)
+	}
+	result = p.Reduce(Tags{"endpoint": "/c"}, Sum)
+	if !floatEquals(result, 0) {
+		t.Fatalf("unknown tag sum should be zero but got %f", result)
+	}
+	if len(p.Tags()) != 3 {
+		t.Fatalf("expected 3 series but got %d", len(p.Tags()))
+	}
+}
+
+func TestTaggedPolicyTagsAreCopied(t *testing.T) {
+	var p = newTestTaggedPolicy()
+	var tags = Tags{"endpoint": "/a"}
+	p.Append(1, tags)
+	tags["endpoint"] = "/b"
+	p.Append(1, tags)
+	if len(p.Tags()) != 2 {
+		t.Fatalf("expected 2 series but got %d", len(p.Tags()))
+	}
+}
+
+func TestTaggedPolicyDataRace(t *testing.T) {
+	var p = newTestTaggedPolicy()
+	var wg = &sync.WaitGroup{}
+	for x := 0; x < 4; x = x + 1 {
+		wg.Add(1)
+		go func(x int) {
+			defer wg.Done()
+			for y := 0; y < 100; y = y + 1 {
+				p.Append(1, Tags{"shard": fmt.Sprint(x % 2)})
+				_ = p.Reduce(nil, Sum)
+			}
+		}(x)
+	}
+	wg.Wait()
+}
+
+func TestTaggedPolicyManySeries(t *testing.T) {
+	var p = newTestTaggedPolicy()
+	var wg = &sync.WaitGroup{}
+	for x := 0; x < 8; x = x + 1 {
+		wg.Add(1)
+		go func(x int) {
+			defer wg.Done()
+			for y := 0; y < 1000; y = y + 1 {
+				p.Append(1, Tags{"key": fmt.Sprint(y)})
+			}
+		}(x)
+	}
+	wg.Wait()
+	if len(p.Tags()) != 1000 {
+		t.Fatalf("expected 1000 series but got %d", len(p.Tags()))
+	}
+	if result := p.Reduce(nil, Sum); !floatEquals(result, 8000) {
+		t.Fatalf("overall sum calculated incorrectly: %f versus %f", 8000.0, result)
+	}
+}
+
+func BenchmarkTaggedPolicyParallel(b *testing.B) {
+	var p = newTestTaggedPolicy()
+	var keys = make([]Tags, 10000)
+	for x := range keys {
+		keys[x] = Tags{"key": fmt.Sprint(x)}
+	}
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		var x = 0
+		for pb.Next() {
+			p.Append(1, keys[x%len(keys)])
+			x = x + 1
+		}
+	})
+}
@@ -0,0 +1,74 @@
+package rolling
+
+import "sync"
+
+type subscriber struct {
+	id     int
+	append func(float64)
+}
+
+// Tee is a Policy that copies every appended value to a set of subscribers
+// in addition to its underlying window. Streaming estimators, such as
+// StreamingPercentile and StreamingVariance, may subscribe to receive values
+// as they arrive rather than each iterating the window when it is evaluated.
+type Tee struct {
+	policy      Policy
+	subscribers []subscriber
+	next        int
+	lock        *sync.RWMutex
+}
+
+// NewTee wraps the given window so that appended values may be subscribed
+// to. The window may be nil if only the subscribers need the values.
+func NewTee(p Policy) *Tee {
+	return &Tee{policy: p, lock: &sync.RWMutex{}}
+}
+
+// Subscribe registers a function to receive every value appended after it
+// is subscribed, such as the Append method of another window. Subscribers
+// are called in the order they subscribed, synchronously within Append, and
+// must not subscribe or unsubscribe from within the call. The returned
+// function removes the subscriber and may be called more than once.
+func (t *Tee) Subscribe(f func(float64)) func() {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	var id = t.next
+	t.next = t.next + 1
+	t.subscribers = append(t.subscribers, subscriber{id: id, append: f})
+	return func() {
+		t.lock.Lock()
+		defer t.lock.Unlock()
+
+		for offset, s := range t.subscribers {
+			if s.id == id {
+				var remaining = make([]subscriber, 0, len(t.subscribers)-1)
+				remaining = append(remaining, t.subscribers[:offset]...)
+				t.subscribers = append(remaining, t.subscribers[offset+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// Append a value to the underlying window and to every subscriber.
+func (t *Tee) Append(value float64) {
+	if t.policy != nil {
+		t.policy.Append(value)
+	}
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	for _, s := range t.subscribers {
+		s.append(value)
+	}
+}
+
+// Reduce the underlying window to a single value using a reduction function.
+// A Tee without a window reduces an empty window.
+func (t *Tee) Reduce(f func(Window) float64) float64 {
+	if t.policy == nil {
+		return f(Window{})
+	}
+	return t.policy.Reduce(f)
+}
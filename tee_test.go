@@ -0,0 +1,40 @@
+package rolling
+
+import "testing"
+
+func TestTee(t *testing.T) {
+	var p = NewPointPolicy(NewWindow(4))
+	var tee = NewTee(p)
+	var percentile = NewStreamingPercentile(NewWindow(4))
+	var variance = NewStreamingVariance(NewWindow(4))
+	tee.Subscribe(percentile.Append)
+	var unsubscribe = tee.Subscribe(variance.Append)
+	for _, v := range []float64{2, 4, 4, 6} {
+		tee.Append(v)
+	}
+	if result := tee.Reduce(Sum); result != 16 {
+		t.Fatalf("expected the window to receive every value but got %f", result)
+	}
+	if result := percentile.Percentile(50); result != 4 {
+		t.Fatalf("expected a median of 4 but got %f", result)
+	}
+	if result := variance.Variance(); !floatEquals(result, 2) {
+		t.Fatalf("expected a variance of 2 but got %f", result)
+	}
+
+	unsubscribe()
+	unsubscribe()
+	tee.Append(100)
+	if result := variance.Mean(); !floatEquals(result, 4) {
+		t.Fatalf("expected no values after unsubscribing but got a mean of %f", result)
+	}
+	if result := percentile.Percentile(100); result != 100 {
+		t.Fatalf("expected the remaining subscriber to receive values but got %f", result)
+	}
+
+	var empty = NewTee(nil)
+	empty.Append(1)
+	if result := empty.Reduce(Count); result != 0 {
+		t.Fatalf("expected a tee without a window to be empty but got %f", result)
+	}
+}
@@ -0,0 +1,150 @@
+package rolling
+
+import (
+	"sync"
+	"time"
+)
+
+// Tier describes one resolution of a TieredPolicy.
+type Tier struct {
+	// BucketDuration is the amount of time covered by each bucket.
+	BucketDuration time.Duration
+	// Buckets is the number of buckets retained.
+	Buckets int
+}
+
+type tier struct {
+	bucketSizeNano int64
+	window         Window
+	ring           ring
+	bucketLimit    int
+}
+
+func (t *tier) index(timestamp time.Time) int64 {
+	return floorDiv(timestamp.UnixNano(), t.bucketSizeNano)
+}
+
+func (t *tier) clear(offset int) {
+	t.window[offset] = resetBucket(t.window[offset], 0, t.bucketLimit)
+}
+
+// TieredPolicy is a window implementation that records data at several
+// resolutions. The first tier contains the raw values appended to the window.
+// Each following tier contains one value per bucket that summarizes the
+// buckets of the previous tier for the same span of time. This retains a long
+// history at a coarse resolution with the recent history at a fine
+// resolution while using far less memory than a single fine grained window.
+type TieredPolicy struct {
+	tiers      []*tier
+	downsample func(Window) float64
+	clock      Clock
+	lock       *sync.Mutex
+}
+
+// NewTieredPolicy manages a set of windows at different resolutions. The
+// tiers must be ordered from the finest resolution to the coarsest. The bucket
+// duration of each tier must be a multiple of the previous tier's bucket
+// duration and the previous tier must cover at least one bucket of the next.
+// For example, 60 buckets of one second, 60 buckets of one minute, and 24
+// buckets of one hour.
+//
+// When a bucket of a coarse tier ends, the downsample function is used to
+// reduce the buckets of the previous tier that fall within it to a single
+// value. Reductions that can be applied to their own output, such as Sum,
+// Min, and Max, are the most accurate choices.
+func NewTieredPolicy(tiers []Tier, downsample func(Window) float64, options ...TimePolicyOption) *TieredPolicy {
+	var o = newTimeOptions(options)
+	var p = &TieredPolicy{
+		tiers:      make([]*tier, 0, len(tiers)),
+		downsample: downsample,
+		clock:      o.clock,
+		lock:       &sync.Mutex{},
+	}
+	for _, t := range tiers {
+		p.tiers = append(p.tiers, &tier{
+			bucketSizeNano: t.BucketDuration.Nanoseconds(),
+			window:         NewWindow(t.Buckets),
+			ring:           newRing(t.Buckets),
+			bucketLimit:    o.limit(0),
+		})
+	}
+	return p
+}
+
+// summarize reduces the buckets of the previous tier that fall within the
+// given bucket of a tier into a single value within that bucket.
+func (w *TieredPolicy) summarize(tierIndex int, index int64) {
+	var coarse = w.tiers[tierIndex]
+	var fine = w.tiers[tierIndex-1]
+	var start = index * coarse.bucketSizeNano / fine.bucketSizeNano
+	var end = (index + 1) * coarse.bucketSizeNano / fine.bucketSizeNano
+	var buckets = make(Window, 0, end-start)
+	var values = 0
+	for fineIndex := start; fineIndex < end; fineIndex = fineIndex + 1 {
+		if !fine.ring.contains(fineIndex) {
+			continue
+		}
+		var bucket = fine.window[fine.ring.offset(fineIndex)]
+		buckets = append(buckets, bucket)
+		values = values + len(bucket)
+	}
+	if values < 1 {
+		return
+	}
+	var offset = coarse.ring.offset(index)
+	coarse.window[offset] = append(coarse.window[offset][:0], w.downsample(buckets))
+}
+
+// advance summarizes any buckets that have ended before moving every tier
+// forward to the given time. All summaries are taken before any tier moves
+// so that no tier discards data that a coarser tier has yet to summarize.
+func (w *TieredPolicy) advance(now time.Time) {
+	for offset := 1; offset < len(w.tiers); offset = offset + 1 {
+		var t = w.tiers[offset]
+		if t.ring.started && t.index(now) > t.ring.last {
+			w.summarize(offset, t.ring.last)
+		}
+	}
+	for _, t := range w.tiers {
+		t.ring.advance(t.index(now), t.clear)
+	}
+}
+
+// AppendWithTimestamp same as Append but with timestamp as parameter. Values
+// older than the finest tier are discarded.
+func (w *TieredPolicy) AppendWithTimestamp(value float64, timestamp time.Time) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	w.advance(timestamp)
+	var t = w.tiers[0]
+	var index = t.index(timestamp)
+	if !t.ring.contains(index) {
+		return
+	}
+	var offset = t.ring.offset(index)
+	t.window[offset] = append(t.window[offset], value)
+}
+
+// Append a value to the finest tier of the window.
+func (w *TieredPolicy) Append(value float64) {
+	w.AppendWithTimestamp(value, w.clock.Now())
+}
+
+// Reduce the finest tier of the window to a single value using a reduction
+// function.
+func (w *TieredPolicy) Reduce(f func(Window) float64) float64 {
+	return w.ReduceTier(0, f)
+}
+
+// ReduceTier reduces a single tier of the window using a reduction function.
+// Tiers are numbered in the order they were given to NewTieredPolicy. Each
+// bucket of a tier other than the first contains a single summary value and
+// the bucket that is still in progress remains empty until it ends.
+func (w *TieredPolicy) ReduceTier(tierIndex int, f func(Window) float64) float64 {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	w.advance(w.clock.Now())
+	return f(w.tiers[tierIndex].window)
+}
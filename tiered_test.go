@@ -0,0 +1,70 @@
+package rolling
+
+import (
+	"testing"
+	"time"
+)
+
+func newTestTieredPolicy(c Clock) *TieredPolicy {
+	return NewTieredPolicy(
+		[]Tier{
+			{BucketDuration: time.Second, Buckets: 60},
+			{BucketDuration: time.Minute, Buckets: 60},
+			{BucketDuration: time.Hour, Buckets: 24},
+		},
+		Sum,
+		WithClock(c),
+	)
+}
+
+func TestTieredWindowDownsample(t *testing.T) {
+	var c = &testClock{now: time.Unix(0, 0)}
+	var p = newTestTieredPolicy(c)
+	// One value per second for two minutes.
+	for x := 0; x < 120; x = x + 1 {
+		p.Append(1)
+		c.now = c.now.Add(time.Second)
+	}
+	// The current second is empty and the oldest second of the last minute
+	// has expired.
+	if result := p.ReduceTier(0, Sum); !floatEquals(result, 59) {
+		t.Fatalf("finest tier should contain the last minute but summed to %f", result)
+	}
+	if result := p.ReduceTier(1, Sum); !floatEquals(result, 120) {
+		t.Fatalf("minute tier should contain both minutes but summed to %f", result)
+	}
+	if result := p.ReduceTier(1, Count); !floatEquals(result, 2) {
+		t.Fatalf("minute tier should contain two summaries but has %f", result)
+	}
+	if result := p.ReduceTier(2, Sum); !floatEquals(result, 0) {
+		t.Fatalf("hour tier should be empty until the hour ends but summed to %f", result)
+	}
+
+	c.now = time.Unix(0, 0).Add(time.Hour)
+	if result := p.ReduceTier(2, Sum); !floatEquals(result, 120) {
+		t.Fatalf("hour tier should contain the first hour but summed to %f", result)
+	}
+	if result := p.ReduceTier(0, Sum); !floatEquals(result, 0) {
+		t.Fatalf("finest tier should have expired but summed to %f", result)
+	}
+	if result := p.Reduce(Count); !floatEquals(result, 0) {
+		t.Fatalf("finest tier should have expired but has %f values", result)
+	}
+}
+
+func TestTieredWindowLongIdle(t *testing.T) {
+	var c = &testClock{now: time.Unix(0, 0)}
+	var p = newTestTieredPolicy(c)
+	p.Append(5)
+	c.now = c.now.Add(3 * time.Hour)
+	if result := p.ReduceTier(2, Sum); !floatEquals(result, 5) {
+		t.Fatalf("hour tier should retain data after idling but summed to %f", result)
+	}
+	if result := p.ReduceTier(1, Sum); !floatEquals(result, 0) {
+		t.Fatalf("minute tier should have expired but summed to %f", result)
+	}
+	c.now = c.now.Add(24 * time.Hour)
+	if result := p.ReduceTier(2, Sum); !floatEquals(result, 0) {
+		t.Fatalf("hour tier should have expired but summed to %f", result)
+	}
+}
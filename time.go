@@ -2,20 +2,89 @@ package rolling
 
 import (
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
 // TimePolicy is a window Accumulator implementation that uses some
 // duration of time to determine the content of the window.
 type TimePolicy struct {
-	bucketSize        time.Duration
-	bucketSizeNano    int64
-	numberOfBuckets   int
-	numberOfBuckets64 int64
-	window            [][]float64
-	lastWindowOffset  int
-	lastWindowTime    int64
-	lock              *sync.Mutex
+	bucketSize      time.Duration
+	numberOfBuckets int
+	window          [][]float64
+	ring            ring
+	bucketHint      int
+	bucketLimit     int
+	size            int
+	pointLimit      int
+	overflowMode    Overflow
+	hooks           Hooks
+	counters        timeCounters
+	created         time.Time
+	clock           Clock
+	lock            sync.Locker
+}
+
+type timeOptions struct {
+	clock       Clock
+	originNano  int64
+	bucketLimit int
+	pointLimit  int
+	overflow    Overflow
+	hooks       Hooks
+}
+
+func newTimeOptions(options []TimePolicyOption) *timeOptions {
+	var o = &timeOptions{clock: systemClock{}}
+	for _, option := range options {
+		option(o)
+	}
+	return o
+}
+
+// limit returns the bucket limit set by the WithBucketLimit option or, when it
+// is not set, the default limit for buckets of the hinted size.
+func (o *timeOptions) limit(hint int) int {
+	if o.bucketLimit < 1 {
+		return defaultBucketLimit(hint)
+	}
+	return o.bucketLimit
+}
+
+// TimePolicyOption is used to modify the behavior of a TimePolicy or of the
+// other policies in this package that depend on time.
+type TimePolicyOption func(*timeOptions)
+
+// WithClock sets the source of time used by a policy. The system clock is
+// used by default.
+func WithClock(clock Clock) TimePolicyOption {
+	return func(o *timeOptions) {
+		o.clock = clock
+	}
+}
+
+// WithAlignment sets the point in time from which bucket boundaries are
+// measured. Each bucket begins at the origin plus a whole multiple of the bucket
+// duration. The default origin is the Unix epoch which places buckets whose
+// duration evenly divides a minute or an hour on the natural boundaries of UTC
+// time. Using, for example, a local midnight as the origin instead aligns the
+// buckets with the civil clock of that location. Policies that do not divide
+// time into buckets ignore this option.
+func WithAlignment(origin time.Time) TimePolicyOption {
+	return func(o *timeOptions) {
+		o.originNano = origin.UnixNano()
+	}
+}
+
+// WithBucketLimit sets the capacity beyond which a bucket is reallocated at
+// its preallocated size, rather than reused, when its data expire. Buckets
+// grow to fit bursts of data and would otherwise hold on to that memory
+// indefinitely. The default is four times the preallocated bucket size or 64,
+// whichever is larger.
+func WithBucketLimit(capacity int) TimePolicyOption {
+	return func(o *timeOptions) {
+		o.bucketLimit = capacity
+	}
 }
 
 // NewTimePolicy manages a window with rolling time duratinos.
@@ -23,74 +92,286 @@ type TimePolicy struct {
 // points are received entire windows aparts then the window will only contain
 // a single data point. If one or more durations of the window are missed then
 // they are zeroed out to keep the window consistent.
-func NewTimePolicy(window Window, bucketDuration time.Duration) *TimePolicy {
+func NewTimePolicy(window Window, bucketDuration time.Duration, options ...TimePolicyOption) *TimePolicy {
+	var o = newTimeOptions(options)
+	var hint = bucketHint(window)
 	return &TimePolicy{
-		bucketSize:        bucketDuration,
-		bucketSizeNano:    bucketDuration.Nanoseconds(),
-		numberOfBuckets:   len(window),
-		numberOfBuckets64: int64(len(window)),
-		window:            window,
-		lock:              &sync.Mutex{},
+		bucketSize:      bucketDuration,
+		numberOfBuckets: len(window),
+		window:          window,
+		ring:            newTimeRing(len(window), bucketDuration, o.originNano),
+		bucketHint:      hint,
+		bucketLimit:     o.limit(hint),
+		pointLimit:      o.pointLimit,
+		overflowMode:    o.overflow,
+		hooks:           o.hooks,
+		counters:        timeCounters{perBucket: make([]uint64, len(window))},
+		created:         o.clock.Now(),
+		clock:           o.clock,
+		lock:            &sync.Mutex{},
 	}
 }
 
-func (w *TimePolicy) resetWindow() {
-	for offset := range w.window {
-		w.window[offset] = w.window[offset][:0]
-	}
+// clearBucket empties a single bucket of the window.
+func (w *TimePolicy) clearBucket(offset int) {
+	w.size = w.size - len(w.window[offset])
+	w.window[offset] = resetBucket(w.window[offset], w.bucketHint, w.bucketLimit)
+	w.counters.perBucket[offset] = 0
+}
+
+// dropBucket empties a single bucket as part of resetting the window.
+func (w *TimePolicy) dropBucket(offset int) {
+	w.evict(offset)
+	w.clearBucket(offset)
 }
 
-func (w *TimePolicy) resetBuckets(windowOffset int) {
-	var distance = windowOffset - w.lastWindowOffset
-	// If the distance between current and last is negative then we've wrapped
-	// around the ring. Recalculate the distance.
-	if distance < 0 {
-		distance = (w.numberOfBuckets - w.lastWindowOffset) + windowOffset
+// resetWindow empties every bucket when the window moves forward to a time
+// that is a full window or more after the newest bucket.
+func (w *TimePolicy) resetWindow(adjustedTime int64) {
+	var dropped = w.size
+	w.ring.advance(adjustedTime, w.dropBucket)
+	if dropped < 1 {
+		return
 	}
-	for counter := 1; counter < distance; counter = counter + 1 {
-		var offset = (counter + w.lastWindowOffset) % w.numberOfBuckets
-		w.window[offset] = w.window[offset][:0]
+	w.counters.resets = w.counters.resets + 1
+	w.counters.expired = w.counters.expired + uint64(dropped)
+	if w.hooks.OnReset != nil {
+		w.hooks.OnReset(dropped)
 	}
 }
 
-func (w *TimePolicy) keepConsistent(adjustedTime int64, windowOffset int) {
-	// If we've waiting longer than a full window for data then we need to clear
-	// the internal state completely.
-	if adjustedTime-w.lastWindowTime > w.numberOfBuckets64 {
-		w.resetWindow()
+// resetBuckets expires every bucket from the one after the newest bucket up
+// to and including the bucket of the given time. The bucket of the given
+// time last held data for the period one window earlier so it must be
+// cleared as well before it is read or written.
+func (w *TimePolicy) resetBuckets(adjustedTime int64) {
+	var wrapped = false
+	w.ring.advance(adjustedTime, func(offset int) {
+		w.expireBucket(offset)
+		if offset == 0 {
+			wrapped = true
+		}
+	})
+	if wrapped && w.hooks.OnWrap != nil {
+		w.hooks.OnWrap()
 	}
+}
 
-	// When one or more buckets are missed we need to zero them out.
-	if adjustedTime != w.lastWindowTime && adjustedTime-w.lastWindowTime < w.numberOfBuckets64 {
-		w.resetBuckets(windowOffset)
+// keepConsistent rotates the window forward to the given time. Buckets that
+// belong to periods which are no longer covered by the window are cleared so
+// that every read and write sees only current data. Times at or before the
+// newest bucket leave the window unchanged.
+func (w *TimePolicy) keepConsistent(adjustedTime int64) {
+	if adjustedTime <= w.ring.last {
+		return
+	}
+	// If we've waited a full window or longer for data then we need to clear
+	// the internal state completely.
+	if adjustedTime-w.ring.last >= w.ring.numberOfBuckets {
+		w.resetWindow(adjustedTime)
+	} else {
+		w.resetBuckets(adjustedTime)
 	}
 }
 
 func (w *TimePolicy) selectBucket(currentTime time.Time) (int64, int) {
-	var adjustedTime = currentTime.UnixNano() / w.bucketSizeNano
-	var windowOffset = int(adjustedTime % w.numberOfBuckets64)
-	return adjustedTime, windowOffset
+	var adjustedTime = w.ring.index(currentTime)
+	return adjustedTime, w.ring.offset(adjustedTime)
 }
 
-// AppendWithTimestamp same as Append but with timestamp as parameter
-func (w *TimePolicy) AppendWithTimestamp(value float64, timestamp time.Time) {
+// floorDiv divides, rounding toward negative infinity, so that times before
+// the origin of a window are bucketed the same way as times after it.
+func floorDiv(a int64, b int64) int64 {
+	var result = a / b
+	if (a%b != 0) && ((a < 0) != (b < 0)) {
+		result = result - 1
+	}
+	return result
+}
+
+// BucketSize returns the duration of time covered by each bucket.
+func (w *TimePolicy) BucketSize() time.Duration {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	return w.bucketSize
+}
+
+// BucketCount returns the number of buckets in the window.
+func (w *TimePolicy) BucketCount() int {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	return w.numberOfBuckets
+}
+
+// WindowDuration returns the total duration of time covered by the window.
+func (w *TimePolicy) WindowDuration() time.Duration {
 	w.lock.Lock()
 	defer w.lock.Unlock()
 
+	return w.bucketSize * time.Duration(w.numberOfBuckets)
+}
+
+// Len returns the number of values currently within the window.
+func (w *TimePolicy) Len() int {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	w.keepConsistent(w.ring.index(w.clock.Now()))
+	var result = 0
+	for _, bucket := range w.window {
+		result = result + len(bucket)
+	}
+	return result
+}
+
+// Oldest returns the oldest value within the window, the start time of the
+// bucket that contains it, and whether the window contains any values.
+func (w *TimePolicy) Oldest() (float64, time.Time, bool) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	w.keepConsistent(w.ring.index(w.clock.Now()))
+	for age := w.ring.numberOfBuckets - 1; age >= 0; age = age - 1 {
+		var bucketTime = w.ring.last - age
+		var bucket = w.window[w.ring.offset(bucketTime)]
+		if len(bucket) > 0 {
+			return bucket[0], w.ring.start(bucketTime), true
+		}
+	}
+	return 0, time.Time{}, false
+}
+
+// Newest returns the most recent value within the window, the start time of
+// the bucket that contains it, and whether the window contains any values.
+func (w *TimePolicy) Newest() (float64, time.Time, bool) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	w.keepConsistent(w.ring.index(w.clock.Now()))
+	for age := int64(0); age < w.ring.numberOfBuckets; age = age + 1 {
+		var bucketTime = w.ring.last - age
+		var bucket = w.window[w.ring.offset(bucketTime)]
+		if len(bucket) > 0 {
+			return bucket[len(bucket)-1], w.ring.start(bucketTime), true
+		}
+	}
+	return 0, time.Time{}, false
+}
+
+// Age returns the amount of time that has passed since the window was
+// created.
+func (w *TimePolicy) Age() time.Duration {
+	return w.clock.Now().Sub(w.created)
+}
+
+// Fill returns the fraction of the window duration, between 0 and 1, that has
+// elapsed since the window was created. A window that is not yet full has not
+// been collecting data long enough to describe the entire duration it covers.
+func (w *TimePolicy) Fill() float64 {
+	var elapsed = w.Age()
+	var duration = w.WindowDuration().Nanoseconds()
+	switch {
+	case elapsed <= 0:
+		return 0
+	case elapsed.Nanoseconds() >= duration:
+		return 1
+	}
+	return float64(elapsed.Nanoseconds()) / float64(duration)
+}
+
+// AppendWithTimestamp same as Append but with timestamp as parameter. Values
+// older than the window are discarded.
+func (w *TimePolicy) AppendWithTimestamp(value float64, timestamp time.Time) {
+	w.acquire()
+	defer w.lock.Unlock()
+
+	w.counters.appends = w.counters.appends + 1
 	var adjustedTime, windowOffset = w.selectBucket(timestamp)
-	w.keepConsistent(adjustedTime, windowOffset)
-	if w.lastWindowOffset != windowOffset {
-		w.window[windowOffset] = []float64{value}
-	} else {
-		w.window[windowOffset] = append(w.window[windowOffset], value)
+	w.keepConsistent(adjustedTime)
+	if !w.ring.contains(adjustedTime) {
+		return
+	}
+	w.counters.perBucket[windowOffset] = w.counters.perBucket[windowOffset] + 1
+	if w.pointLimit > 0 && w.size >= w.pointLimit && !w.overflow(w.ring.last) {
+		return
 	}
-	w.lastWindowTime = adjustedTime
-	w.lastWindowOffset = windowOffset
+	w.window[windowOffset] = append(w.window[windowOffset], value)
+	w.size = w.size + 1
 }
 
 // Append a value to the window using a time bucketing strategy.
 func (w *TimePolicy) Append(value float64) {
-	w.AppendWithTimestamp(value, time.Now())
+	w.AppendWithTimestamp(value, w.clock.Now())
+}
+
+// AppendBatch appends every given value to the window as if each had been
+// appended individually at the current time. The lock is taken and the bucket
+// is selected only once for the whole batch which makes this considerably
+// cheaper than calling Append in a loop when feeding bursts of data.
+func (w *TimePolicy) AppendBatch(values []float64) {
+	w.AppendBatchWithTimestamp(values, w.clock.Now())
+}
+
+// AppendBatchWithTimestamp is the same as AppendBatch but with the timestamp
+// of every value given as a parameter.
+func (w *TimePolicy) AppendBatchWithTimestamp(values []float64, timestamp time.Time) {
+	if len(values) < 1 {
+		return
+	}
+	w.acquire()
+	defer w.lock.Unlock()
+
+	w.counters.appends = w.counters.appends + uint64(len(values))
+	var adjustedTime, windowOffset = w.selectBucket(timestamp)
+	w.keepConsistent(adjustedTime)
+	if !w.ring.contains(adjustedTime) {
+		return
+	}
+	w.counters.perBucket[windowOffset] = w.counters.perBucket[windowOffset] + uint64(len(values))
+	if w.pointLimit > 0 {
+		for _, value := range values {
+			if w.size >= w.pointLimit && !w.overflow(w.ring.last) {
+				continue
+			}
+			w.window[windowOffset] = append(w.window[windowOffset], value)
+			w.size = w.size + 1
+		}
+	} else {
+		w.window[windowOffset] = append(w.window[windowOffset], values...)
+		w.size = w.size + len(values)
+	}
+}
+
+// Clone returns a deep copy of the policy and its window. The copy is made
+// while the lock of the policy is held and does not share any state with the
+// original other than its Clock and the functions of its Hooks, which are
+// called by both. The Stats counters of the copy begin with the values of the
+// original and are counted separately from then on.
+func (w *TimePolicy) Clone() *TimePolicy {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	var counters = w.counters
+	counters.lockSample = atomic.LoadUint32(&w.counters.lockSample)
+	counters.perBucket = append([]uint64(nil), w.counters.perBucket...)
+	return &TimePolicy{
+		bucketSize:      w.bucketSize,
+		numberOfBuckets: w.numberOfBuckets,
+		window:          Window(w.window).Clone(),
+		ring:            w.ring,
+		bucketHint:      w.bucketHint,
+		bucketLimit:     w.bucketLimit,
+		size:            w.size,
+		pointLimit:      w.pointLimit,
+		overflowMode:    w.overflowMode,
+		hooks:           w.hooks,
+		counters:        counters,
+		created:         w.created,
+		clock:           w.clock,
+		lock:            &sync.Mutex{},
+	}
 }
 
 // Reduce the window to a single value using a reduction function.
@@ -98,7 +379,59 @@ func (w *TimePolicy) Reduce(f func(Window) float64) float64 {
 	w.lock.Lock()
 	defer w.lock.Unlock()
 
-	var adjustedTime, windowOffset = w.selectBucket(time.Now())
-	w.keepConsistent(adjustedTime, windowOffset)
+	w.keepConsistent(w.ring.index(w.clock.Now()))
 	return f(w.window)
 }
+
+// SeriesPoint is the reduced value of a single bucket along with the time at
+// which the bucket begins.
+type SeriesPoint struct {
+	Time  time.Time
+	Value float64
+}
+
+// Series reduces each bucket of the window individually using a reduction
+// function and returns the results ordered from the oldest bucket to the
+// newest. The reduction function is given a Window containing only the one
+// bucket being reduced. This is useful for charting the recent history of a
+// window rather than a single value for the whole window.
+func (w *TimePolicy) Series(f func(Window) float64) []SeriesPoint {
+	return w.series(w.clock.Now(), f)
+}
+
+func (w *TimePolicy) series(now time.Time, f func(Window) float64) []SeriesPoint {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	var result = make([]SeriesPoint, 0, w.numberOfBuckets)
+	w.eachBucket(now, func(start time.Time, window Window) {
+		result = append(result, SeriesPoint{Time: start, Value: f(window)})
+	})
+	return result
+}
+
+// IterateBuckets calls the given function once for each bucket of the window,
+// from the oldest to the newest, with the time at which the bucket begins and
+// the values it contains. The values are owned by the window and must not be
+// modified or retained after the function returns.
+func (w *TimePolicy) IterateBuckets(f func(start time.Time, values []float64)) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	w.eachBucket(w.clock.Now(), func(start time.Time, window Window) {
+		f(start, window[0])
+	})
+}
+
+// eachBucket brings the window up to date with the given time and then calls
+// the given function with each bucket, from the oldest to the newest, as a
+// Window containing only that bucket. The lock must be held by the caller.
+func (w *TimePolicy) eachBucket(now time.Time, f func(start time.Time, window Window)) {
+	var adjustedTime = w.ring.index(now)
+	w.keepConsistent(adjustedTime)
+	for age := w.ring.numberOfBuckets - 1; age >= 0; age = age - 1 {
+		var bucketTime = adjustedTime - age
+		var offset = w.ring.offset(bucketTime)
+		f(w.ring.start(bucketTime), w.window[offset:offset+1])
+	}
+}
@@ -11,10 +11,11 @@ func TestTimeWindow(t *testing.T) {
 	var bucketSize = time.Millisecond * 100
 	var numberBuckets = 10
 	var w = NewWindow(numberBuckets)
-	var p = NewTimePolicy(w, bucketSize)
+	var c = &testClock{now: time.Unix(100, 0)}
+	var p = NewTimePolicy(w, bucketSize, WithClock(c))
 	for x := 0; x < numberBuckets; x = x + 1 {
 		p.Append(1)
-		time.Sleep(bucketSize)
+		c.now = c.now.Add(bucketSize)
 	}
 	var final = p.Reduce(func(w Window) float64 {
 		var result float64
@@ -25,13 +26,14 @@ func TestTimeWindow(t *testing.T) {
 		}
 		return result
 	})
-	if final != float64(numberBuckets) {
+	// The first value was appended a full window ago and has expired.
+	if final != float64(numberBuckets-1) {
 		t.Fatal(final)
 	}
 
 	for x := 0; x < numberBuckets; x = x + 1 {
 		p.Append(2)
-		time.Sleep(bucketSize)
+		c.now = c.now.Add(bucketSize)
 	}
 
 	final = p.Reduce(func(w Window) float64 {
@@ -43,8 +45,8 @@ func TestTimeWindow(t *testing.T) {
 		}
 		return result
 	})
-	if final != 2*float64(numberBuckets) {
-		t.Fatal("got", final, "expected", 2*float64(numberBuckets))
+	if final != 2*float64(numberBuckets-1) {
+		t.Fatal("got", final, "expected", 2*float64(numberBuckets-1))
 	}
 }
 
@@ -83,31 +85,35 @@ func TestTimeWindowConsistency(t *testing.T) {
 	for offset := range p.window {
 		p.window[offset] = append(p.window[offset], 1)
 	}
-	p.lastWindowTime = time.Now().UnixNano()
-	p.lastWindowOffset = 0
 	var target = time.Unix(1, 0)
-	var adjustedTime, bucket = p.selectBucket(target)
-	p.keepConsistent(adjustedTime, bucket)
+	var adjustedTime = p.ring.index(target)
+	p.ring.last = adjustedTime
+	p.keepConsistent(adjustedTime)
 	if len(p.window[0]) != 1 {
 		t.Fatal("data loss while adjusting internal state")
 	}
 	target = time.Unix(1, int64(50*time.Millisecond))
-	adjustedTime, bucket = p.selectBucket(target)
-	p.keepConsistent(adjustedTime, bucket)
+	adjustedTime = p.ring.index(target)
+	p.keepConsistent(adjustedTime)
 	if len(p.window[0]) != 1 {
 		t.Fatal("data loss while adjusting internal state")
 	}
 	target = time.Unix(1, int64(5*50*time.Millisecond))
-	adjustedTime, bucket = p.selectBucket(target)
-	p.keepConsistent(adjustedTime, bucket)
+	adjustedTime = p.ring.index(target)
+	p.keepConsistent(adjustedTime)
 	if len(p.window[0]) != 1 {
 		t.Fatal("data loss while adjusting internal state")
 	}
-	for x := 1; x < 5; x = x + 1 {
+	// The bucket of the new time held data from a full window ago and is
+	// cleared along with the buckets that were skipped.
+	for x := 1; x <= 5; x = x + 1 {
 		if len(p.window[x]) != 0 {
 			t.Fatal("internal state not kept consistent during time gap")
 		}
 	}
+	if len(p.window[6]) != 1 {
+		t.Fatal("data loss while adjusting internal state")
+	}
 }
 
 func TestTimeWindowDataRace(t *testing.T) {
@@ -191,3 +197,288 @@ func BenchmarkTimeWindow(b *testing.B) {
 		})
 	}
 }
+
+func TestTimeWindowSeries(t *testing.T) {
+	var bucketSize = time.Second
+	var numberBuckets = 5
+	var w = NewWindow(numberBuckets)
+	var p = NewTimePolicy(w, bucketSize)
+	var start = time.Unix(100, 0)
+	p.AppendWithTimestamp(1, start)
+	p.AppendWithTimestamp(1, start)
+	p.AppendWithTimestamp(3, start.Add(2*bucketSize))
+	p.AppendWithTimestamp(4, start.Add(3*bucketSize))
+
+	var series = p.series(start.Add(3*bucketSize), Sum)
+	if len(series) != numberBuckets {
+		t.Fatalf("expected %d points but got %d", numberBuckets, len(series))
+	}
+	var expected = []SeriesPoint{
+		{Time: time.Unix(99, 0), Value: 0},
+		{Time: time.Unix(100, 0), Value: 2},
+		{Time: time.Unix(101, 0), Value: 0},
+		{Time: time.Unix(102, 0), Value: 3},
+		{Time: time.Unix(103, 0), Value: 4},
+	}
+	for offset, point := range series {
+		if !point.Time.Equal(expected[offset].Time) || !floatEquals(point.Value, expected[offset].Value) {
+			t.Fatalf("point %d was %v but expected %v", offset, point, expected[offset])
+		}
+	}
+}
+
+func TestTimeWindowAlignment(t *testing.T) {
+	var location = time.FixedZone("UTC+0530", int((5*time.Hour + 30*time.Minute).Seconds()))
+	var origin = time.Date(2020, 1, 1, 0, 0, 0, 0, location)
+	var w = NewWindow(24)
+	var p = NewTimePolicy(w, time.Hour, WithAlignment(origin))
+
+	var _, bucket = p.selectBucket(time.Date(2020, 1, 1, 0, 59, 0, 0, location))
+	if bucket != 0 {
+		t.Fatalf("expected bucket 0 but got %d", bucket)
+	}
+	_, bucket = p.selectBucket(time.Date(2020, 1, 1, 1, 0, 0, 0, location))
+	if bucket != 1 {
+		t.Fatalf("expected bucket 1 but got %d", bucket)
+	}
+	// Times before the origin must still map to a valid bucket.
+	_, bucket = p.selectBucket(time.Date(2019, 12, 31, 23, 59, 0, 0, location))
+	if bucket != 23 {
+		t.Fatalf("expected bucket 23 but got %d", bucket)
+	}
+
+	var now = time.Date(2020, 1, 2, 10, 15, 0, 0, location)
+	p.AppendWithTimestamp(1, now)
+	var series = p.series(now, Sum)
+	var newest = series[len(series)-1]
+	var expected = time.Date(2020, 1, 2, 10, 0, 0, 0, location)
+	if !newest.Time.Equal(expected) || !floatEquals(newest.Value, 1) {
+		t.Fatalf("expected newest bucket at %v but got %v", expected, newest.Time)
+	}
+}
+
+type testClock struct {
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	return c.now
+}
+
+func TestTimeWindowFill(t *testing.T) {
+	var c = &testClock{now: time.Unix(0, 0)}
+	var p = NewTimePolicy(NewWindow(10), time.Second, WithClock(c))
+	if !floatEquals(p.Fill(), 0) {
+		t.Fatalf("expected empty window but got %f", p.Fill())
+	}
+	c.now = c.now.Add(5 * time.Second)
+	if !floatEquals(p.Fill(), .5) {
+		t.Fatalf("expected half full window but got %f", p.Fill())
+	}
+	c.now = c.now.Add(time.Hour)
+	if !floatEquals(p.Fill(), 1) {
+		t.Fatalf("expected full window but got %f", p.Fill())
+	}
+}
+
+func TestTimeWindowLen(t *testing.T) {
+	var c = &testClock{now: time.Unix(0, 0)}
+	var p = NewTimePolicy(NewWindow(3), time.Second, WithClock(c))
+	if p.Len() != 0 {
+		t.Fatalf("expected no values but got %d", p.Len())
+	}
+	p.Append(1)
+	p.Append(1)
+	c.now = c.now.Add(time.Second)
+	p.Append(1)
+	if p.Len() != 3 {
+		t.Fatalf("expected 3 values but got %d", p.Len())
+	}
+	c.now = c.now.Add(time.Minute)
+	if p.Len() != 0 {
+		t.Fatalf("expected no values but got %d", p.Len())
+	}
+}
+
+func TestTimeWindowConfiguration(t *testing.T) {
+	var p = NewTimePolicy(NewWindow(60), 500*time.Millisecond)
+	if p.BucketSize() != 500*time.Millisecond {
+		t.Fatalf("expected bucket size of 500ms but got %v", p.BucketSize())
+	}
+	if p.BucketCount() != 60 {
+		t.Fatalf("expected 60 buckets but got %d", p.BucketCount())
+	}
+	if p.WindowDuration() != 30*time.Second {
+		t.Fatalf("expected window duration of 30s but got %v", p.WindowDuration())
+	}
+}
+
+func TestTimeWindowOldestNewest(t *testing.T) {
+	var c = &testClock{now: time.Unix(100, 0)}
+	var p = NewTimePolicy(NewWindow(5), time.Second, WithClock(c))
+	if _, _, ok := p.Oldest(); ok {
+		t.Fatal("empty window should not have an oldest value")
+	}
+	p.Append(1)
+	p.Append(2)
+	c.now = c.now.Add(2 * time.Second)
+	p.Append(3)
+	p.Append(4)
+	c.now = c.now.Add(time.Second)
+
+	var v, ts, ok = p.Oldest()
+	if !ok || !floatEquals(v, 1) || !ts.Equal(time.Unix(100, 0)) {
+		t.Fatalf("expected oldest value of 1 at %v but got %f at %v", time.Unix(100, 0), v, ts)
+	}
+	v, ts, ok = p.Newest()
+	if !ok || !floatEquals(v, 4) || !ts.Equal(time.Unix(102, 0)) {
+		t.Fatalf("expected newest value of 4 at %v but got %f at %v", time.Unix(102, 0), v, ts)
+	}
+	c.now = c.now.Add(time.Minute)
+	if _, _, ok = p.Newest(); ok {
+		t.Fatal("expired window should not have a newest value")
+	}
+}
+
+func TestTimeWindowIterateBuckets(t *testing.T) {
+	var c = &testClock{now: time.Unix(100, 0)}
+	var p = NewTimePolicy(NewWindow(3), time.Second, WithClock(c))
+	p.Append(1)
+	p.Append(2)
+	c.now = c.now.Add(2 * time.Second)
+	p.Append(5)
+
+	var starts []time.Time
+	var maxima []float64
+	p.IterateBuckets(func(start time.Time, values []float64) {
+		starts = append(starts, start)
+		maxima = append(maxima, Max(Window{values}))
+	})
+	if len(starts) != 3 || !starts[0].Equal(time.Unix(100, 0)) || !starts[2].Equal(time.Unix(102, 0)) {
+		t.Fatalf("unexpected bucket start times %v", starts)
+	}
+	if maxima[0] != 2 || maxima[1] != 0 || maxima[2] != 5 {
+		t.Fatalf("unexpected bucket maxima %v", maxima)
+	}
+}
+
+func TestTimeWindowClone(t *testing.T) {
+	var c = &testClock{now: time.Unix(100, 0)}
+	var p = NewTimePolicy(NewWindow(3), time.Second, WithClock(c))
+	p.Append(1)
+	var clone = p.Clone()
+	p.Append(2)
+	clone.Append(5)
+	c.now = c.now.Add(time.Second)
+	clone.Append(5)
+	if result := p.Reduce(Sum); !floatEquals(result, 3) {
+		t.Fatalf("original sum calculated incorrectly: %f versus %f", 3.0, result)
+	}
+	if result := clone.Reduce(Sum); !floatEquals(result, 11) {
+		t.Fatalf("clone sum calculated incorrectly: %f versus %f", 11.0, result)
+	}
+}
+
+func TestTimeWindowCloneHooksAndStats(t *testing.T) {
+	var c = &testClock{now: time.Unix(100, 0)}
+	var evicted = 0
+	var p = NewTimePolicy(NewWindow(2), time.Second, WithClock(c), WithHooks(Hooks{
+		OnEvict: func(start time.Time, values []float64) {
+			evicted = evicted + len(values)
+		},
+	}))
+	p.Append(1)
+	p.Append(2)
+	var clone = p.Clone()
+	if s := clone.Stats(); s.Appends != 2 {
+		t.Fatalf("expected the clone to begin with the counters of the original but got %+v", s)
+	}
+	clone.Append(3)
+	if s, original := clone.Stats(), p.Stats(); s.Appends != 3 || original.Appends != 2 {
+		t.Fatalf("expected the counters to be counted separately but got %d and %d", s.Appends, original.Appends)
+	}
+	c.now = c.now.Add(2 * time.Second)
+	clone.Rotate()
+	if evicted != 3 {
+		t.Fatalf("expected the clone to call the hooks of the original but %d values were evicted", evicted)
+	}
+}
+
+func TestTimeWindowShrinksBuckets(t *testing.T) {
+	var c = &testClock{now: time.Unix(100, 0)}
+	var w = NewPreallocatedWindow(3, 20)
+	var p = NewTimePolicy(w, time.Second, WithClock(c))
+	for x := 0; x < 1000; x = x + 1 {
+		p.Append(1)
+	}
+	if cap(p.window[1]) < 1000 {
+		t.Fatalf("expected bucket to grow but has a capacity of %d", cap(p.window[1]))
+	}
+	c.now = c.now.Add(time.Minute)
+	if result := p.Reduce(Count); !floatEquals(result, 0) {
+		t.Fatalf("window should be empty but has %f values", result)
+	}
+	for offset, bucket := range p.window {
+		if cap(bucket) != 20 {
+			t.Fatalf("bucket %d should have shrunk to 20 but has a capacity of %d", offset, cap(bucket))
+		}
+	}
+	p.Append(1)
+	c.now = c.now.Add(time.Second)
+	p.Append(1)
+	for offset, bucket := range p.window {
+		if cap(bucket) != 20 {
+			t.Fatalf("bucket %d should keep its preallocated capacity but has %d", offset, cap(bucket))
+		}
+	}
+}
+
+func TestTimeWindowBucketLimit(t *testing.T) {
+	var c = &testClock{now: time.Unix(100, 0)}
+	var p = NewTimePolicy(NewWindow(3), time.Second, WithClock(c), WithBucketLimit(4096))
+	for x := 0; x < 1000; x = x + 1 {
+		p.Append(1)
+	}
+	var capacity = cap(p.window[1])
+	c.now = c.now.Add(time.Minute)
+	p.Reduce(Count)
+	if cap(p.window[1]) != capacity {
+		t.Fatalf("bucket within the limit should be reused but capacity changed from %d to %d", capacity, cap(p.window[1]))
+	}
+}
+
+func TestTimeWindowAppendBatch(t *testing.T) {
+	var c = &testClock{now: time.Unix(0, 0)}
+	var p = NewTimePolicy(NewWindow(3), time.Second, WithClock(c))
+	p.Append(1)
+	c.now = c.now.Add(time.Second)
+	p.AppendBatch([]float64{2, 3, 4})
+	p.AppendBatch(nil)
+	if p.Len() != 4 {
+		t.Fatalf("expected 4 values but got %d", p.Len())
+	}
+	if result := p.Reduce(Sum); !floatEquals(result, 10) {
+		t.Fatalf("batch sum calculated incorrectly: %f versus %f", 10.0, result)
+	}
+	var series = p.Series(Count)
+	if !floatEquals(series[2].Value, 3) {
+		t.Fatalf("expected the batch in the newest bucket but got %v", series)
+	}
+
+	var limited = NewTimePolicy(NewWindow(3), time.Second, WithClock(c), WithPointLimit(2, DropNewest))
+	limited.AppendBatch([]float64{1, 2, 3})
+	if limited.Len() != 2 {
+		t.Fatalf("expected the point limit to apply to batches but got %d values", limited.Len())
+	}
+}
+
+func BenchmarkTimeWindowAppendBatch(b *testing.B) {
+	var c = &testClock{now: time.Unix(0, 0)}
+	var p = NewTimePolicy(NewWindow(60), time.Second, WithClock(c))
+	var batch = make([]float64, 100)
+	b.ResetTimer()
+	for n := 0; n < b.N; n = n + 1 {
+		p.AppendBatch(batch)
+		c.now = c.now.Add(time.Second)
+	}
+}
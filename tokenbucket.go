@@ -0,0 +1,108 @@
+package rolling
+
+import (
+	"sync"
+	"time"
+)
+
+// TokenBucket is a rate limiter that holds up to a fixed number of tokens and
+// refills them at a steady rate. Each admitted event consumes a token so that
+// bursts up to the capacity are admitted immediately while the long running
+// rate is limited to the rate of refill.
+type TokenBucket struct {
+	capacity float64
+	interval time.Duration
+	tokens   float64
+	last     time.Time
+	clock    Clock
+	lock     *sync.Mutex
+}
+
+// NewTokenBucket generates a TokenBucket that begins full and holds at most
+// the given number of tokens. A single token is added each time the given
+// interval elapses. Only the WithClock option applies to a TokenBucket.
+func NewTokenBucket(capacity float64, interval time.Duration, options ...TimePolicyOption) *TokenBucket {
+	var o = newTimeOptions(options)
+	return &TokenBucket{
+		capacity: capacity,
+		interval: interval,
+		tokens:   capacity,
+		last:     o.clock.Now(),
+		clock:    o.clock,
+		lock:     &sync.Mutex{},
+	}
+}
+
+// refill adds the tokens earned since the last refill. The lock must be held
+// by the caller.
+func (b *TokenBucket) refill() {
+	var now = b.clock.Now()
+	var elapsed = now.Sub(b.last)
+	if elapsed <= 0 {
+		return
+	}
+	b.last = now
+	if b.interval <= 0 {
+		b.tokens = b.capacity
+		return
+	}
+	b.tokens = b.tokens + float64(elapsed)/float64(b.interval)
+	if b.tokens > b.capacity {
+		b.tokens = b.capacity
+	}
+}
+
+// Allow consumes a single token if one is available and reports whether the
+// event was admitted. When the event is not admitted the duration until a
+// token will be available is also returned.
+func (b *TokenBucket) Allow() (bool, time.Duration) {
+	return b.AllowN(1)
+}
+
+// AllowN consumes the given number of tokens if they are all available and
+// reports whether the event was admitted. When the event is not admitted the
+// duration until enough tokens will be available is also returned. An event
+// that needs more tokens than the capacity is never admitted and waits for
+// the time needed to fill the bucket from empty.
+func (b *TokenBucket) AllowN(n float64) (bool, time.Duration) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.refill()
+	if n <= b.tokens {
+		b.tokens = b.tokens - n
+		return true, 0
+	}
+	var missing = n - b.tokens
+	if n > b.capacity {
+		missing = b.capacity
+	}
+	return false, time.Duration(missing * float64(b.interval))
+}
+
+// Tokens returns the number of tokens currently available.
+func (b *TokenBucket) Tokens() float64 {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.refill()
+	return b.tokens
+}
+
+// Fill returns the fraction of the capacity, between 0 and 1, that is
+// currently available. A bucket with no capacity is always empty.
+func (b *TokenBucket) Fill() float64 {
+	var tokens = b.Tokens()
+	if b.capacity <= 0 {
+		return 0
+	}
+	return tokens / b.capacity
+}
+
+// Reduce the number of available tokens to a single value using a reduction
+// function. The tokens are given as a window containing a single value so
+// that the fill level of the bucket may be monitored anywhere a Reducer is
+// accepted, such as a Metric or a Snapshotter.
+func (b *TokenBucket) Reduce(f func(Window) float64) float64 {
+	return f(Window{{b.Tokens()}})
+}
@@ -0,0 +1,42 @@
+package rolling
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTokenBucket(t *testing.T) {
+	var c = &testClock{now: time.Unix(0, 0)}
+	var b = NewTokenBucket(3, time.Second, WithClock(c))
+	for x := 0; x < 3; x = x + 1 {
+		if ok, _ := b.Allow(); !ok {
+			t.Fatalf("expected event %d of the burst to be admitted", x)
+		}
+	}
+	var ok, wait = b.Allow()
+	if ok || wait != time.Second {
+		t.Fatalf("expected to wait one second for a token but got %t, %s", ok, wait)
+	}
+	c.now = c.now.Add(500 * time.Millisecond)
+	if ok, wait = b.Allow(); ok || wait != 500*time.Millisecond {
+		t.Fatalf("expected to wait for the rest of the token but got %t, %s", ok, wait)
+	}
+	if result := b.Reduce(Sum); !floatEquals(result, 0.5) {
+		t.Fatalf("expected half a token but got %f", result)
+	}
+	c.now = c.now.Add(500 * time.Millisecond)
+	if ok, _ = b.Allow(); !ok {
+		t.Fatal("expected the refilled token to be admitted")
+	}
+
+	c.now = c.now.Add(time.Hour)
+	if result := b.Fill(); result != 1 {
+		t.Fatalf("expected the bucket to be full but got %f", result)
+	}
+	if ok, wait = b.AllowN(4); ok || wait != 3*time.Second {
+		t.Fatalf("expected an event larger than the bucket to wait for a full bucket but got %t, %s", ok, wait)
+	}
+	if ok, _ = b.AllowN(2); !ok || b.Tokens() != 1 {
+		t.Fatalf("expected two tokens to be consumed but %f remain", b.Tokens())
+	}
+}
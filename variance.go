@@ -0,0 +1,97 @@
+package rolling
+
+import (
+	"math"
+	"sync"
+)
+
+// StreamingVariance is a rolling window of the last N values that maintains
+// their mean and variance as values are appended and evicted. The statistics
+// are updated using Welford's algorithm, and its inverse for evicted values,
+// so that they are available in constant time rather than by iterating the
+// window on every evaluation.
+type StreamingVariance struct {
+	points *PointPolicy
+	count  float64
+	mean   float64
+	m2     float64
+	lock   *sync.RWMutex
+}
+
+// NewStreamingVariance generates a Policy that operates on a rolling set of
+// input points, as with NewPointPolicy, while maintaining their mean and
+// variance.
+func NewStreamingVariance(window Window) *StreamingVariance {
+	return &StreamingVariance{
+		points: NewNoLockPointPolicy(window),
+		lock:   &sync.RWMutex{},
+	}
+}
+
+// Append a value to the window.
+func (w *StreamingVariance) Append(value float64) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	var evicted, ok = w.points.AppendEvict(value)
+	if ok {
+		w.remove(evicted)
+	}
+	w.count = w.count + 1
+	var delta = value - w.mean
+	w.mean = w.mean + delta/w.count
+	w.m2 = w.m2 + delta*(value-w.mean)
+}
+
+func (w *StreamingVariance) remove(value float64) {
+	w.count = w.count - 1
+	if w.count < 1 {
+		w.count, w.mean, w.m2 = 0, 0, 0
+		return
+	}
+	var delta = value - w.mean
+	w.mean = w.mean - delta/w.count
+	w.m2 = w.m2 - delta*(value-w.mean)
+	// Rounding can leave a tiny negative remainder when every remaining
+	// value is the same.
+	if w.m2 < 0 {
+		w.m2 = 0
+	}
+}
+
+// Mean returns the average of the values appended to the window. Points that
+// have not yet received a value are not included.
+func (w *StreamingVariance) Mean() float64 {
+	w.lock.RLock()
+	defer w.lock.RUnlock()
+
+	return w.mean
+}
+
+// Variance returns the population variance of the values appended to the
+// window. Points that have not yet received a value are not included.
+func (w *StreamingVariance) Variance() float64 {
+	w.lock.RLock()
+	defer w.lock.RUnlock()
+
+	if w.count < 1 {
+		return 0
+	}
+	return w.m2 / w.count
+}
+
+// StdDev returns the population standard deviation of the values appended to
+// the window.
+func (w *StreamingVariance) StdDev() float64 {
+	return math.Sqrt(w.Variance())
+}
+
+// Reduce the window to a single value using a reduction function. Points that
+// have not yet received a value contain placeholder zeros as they do in a
+// PointPolicy.
+func (w *StreamingVariance) Reduce(f func(Window) float64) float64 {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	return w.points.Reduce(f)
+}
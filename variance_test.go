@@ -0,0 +1,44 @@
+package rolling
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestStreamingVariance(t *testing.T) {
+	var s = NewStreamingVariance(NewWindow(20))
+	var p = NewPointPolicy(NewWindow(20))
+	if s.Variance() != 0 || s.Mean() != 0 {
+		t.Fatalf("expected zeros for an empty window but got %f and %f", s.Mean(), s.Variance())
+	}
+	var r = rand.New(rand.NewSource(1)) // nolint: gosec
+	for x := 0; x < 500; x = x + 1 {
+		var value = r.Float64() * 100
+		s.Append(value)
+		p.Append(value)
+		var expected = p.ReduceOrdered(Variance)
+		if result := s.Variance(); !floatMostlyEquals(result, expected) {
+			t.Fatalf("variance after %d values calculated incorrectly: %f versus %f", x+1, expected, result)
+		}
+		expected = p.ReduceOrdered(Avg)
+		if result := s.Mean(); !floatMostlyEquals(result, expected) {
+			t.Fatalf("mean after %d values calculated incorrectly: %f versus %f", x+1, expected, result)
+		}
+	}
+	if result := s.StdDev(); !floatMostlyEquals(result, p.Reduce(StdDev)) {
+		t.Fatalf("standard deviation calculated incorrectly: %f versus %f", p.Reduce(StdDev), result)
+	}
+	if result := s.Reduce(Count); !floatEquals(result, 20) {
+		t.Fatalf("expected a full window but counted %f", result)
+	}
+}
+
+func TestStreamingVarianceConstant(t *testing.T) {
+	var s = NewStreamingVariance(NewWindow(3))
+	for x := 0; x < 10; x = x + 1 {
+		s.Append(.1)
+	}
+	if result := s.Variance(); result < 0 || !floatMostlyEquals(result, 0) {
+		t.Fatalf("expected no variance but got %f", result)
+	}
+}
@@ -0,0 +1,197 @@
+package rolling
+
+import (
+	"sync"
+	"time"
+)
+
+// VectorPolicy is a rolling time window, like TimePolicy, in which each point
+// is a fixed size vector of related measurements, such as the latency, size,
+// and CPU time of a single request. The measurements of a point are stored
+// together so that they always remain aligned and expire together, without
+// maintaining a parallel window for each measurement.
+type VectorPolicy struct {
+	dimensions int
+	buckets    [][]float64
+	size       int
+	columns    [][]float64
+	windows    []Window
+	ring       ring
+	clock      Clock
+	lock       *sync.Mutex
+}
+
+// NewVectorPolicy generates a VectorPolicy of vectors with the given number of
+// dimensions over the given number of buckets, each covering the given
+// duration. A vector has at least one dimension. Only the WithClock and
+// WithAlignment options apply to a VectorPolicy.
+func NewVectorPolicy(dimensions int, buckets int, bucketDuration time.Duration, options ...TimePolicyOption) *VectorPolicy {
+	var o = newTimeOptions(options)
+	if dimensions < 1 {
+		dimensions = 1
+	}
+	var windows = make([]Window, dimensions)
+	for dimension := range windows {
+		windows[dimension] = make(Window, 0, buckets)
+	}
+	return &VectorPolicy{
+		dimensions: dimensions,
+		buckets:    make([][]float64, buckets),
+		columns:    make([][]float64, dimensions),
+		windows:    windows,
+		ring:       newTimeRing(buckets, bucketDuration, o.originNano),
+		clock:      o.clock,
+		lock:       &sync.Mutex{},
+	}
+}
+
+// Dimensions returns the number of values in each vector.
+func (w *VectorPolicy) Dimensions() int {
+	return w.dimensions
+}
+
+func (w *VectorPolicy) clear(offset int) {
+	w.size = w.size - len(w.buckets[offset])/w.dimensions
+	w.buckets[offset] = w.buckets[offset][:0]
+}
+
+// AppendWithTimestamp same as Append but with timestamp as parameter. Vectors
+// older than the window are discarded.
+func (w *VectorPolicy) AppendWithTimestamp(vector []float64, timestamp time.Time) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	var adjustedTime = w.ring.index(timestamp)
+	w.ring.advance(adjustedTime, w.clear)
+	if !w.ring.contains(adjustedTime) {
+		return
+	}
+	var offset = w.ring.offset(adjustedTime)
+	for dimension := 0; dimension < w.dimensions; dimension = dimension + 1 {
+		var value = 0.0
+		if dimension < len(vector) {
+			value = vector[dimension]
+		}
+		w.buckets[offset] = append(w.buckets[offset], value)
+	}
+	w.size = w.size + 1
+}
+
+// Append a vector to the window. Vectors with fewer values than the number of
+// dimensions are padded with zeros and any extra values are ignored.
+func (w *VectorPolicy) Append(vector ...float64) {
+	w.AppendWithTimestamp(vector, w.clock.Now())
+}
+
+// Len returns the number of vectors within the window.
+func (w *VectorPolicy) Len() int {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	w.ring.advance(w.ring.index(w.clock.Now()), w.clear)
+	return w.size
+}
+
+// split separates the vectors of the window into one window per dimension,
+// in a single pass, with buckets ordered from the oldest to the newest. The
+// windows are owned by the policy and are only valid while the lock is held.
+func (w *VectorPolicy) split() []Window {
+	var adjustedTime = w.ring.index(w.clock.Now())
+	w.ring.advance(adjustedTime, w.clear)
+	for dimension := range w.columns {
+		if cap(w.columns[dimension]) < w.size {
+			w.columns[dimension] = make([]float64, 0, w.size)
+		}
+		w.columns[dimension] = w.columns[dimension][:0]
+		w.windows[dimension] = w.windows[dimension][:0]
+	}
+	for age := w.ring.numberOfBuckets - 1; age >= 0; age = age - 1 {
+		var bucket = w.buckets[w.ring.offset(adjustedTime-age)]
+		var start = len(w.columns[0])
+		for x := 0; x < len(bucket); x = x + w.dimensions {
+			for dimension := range w.columns {
+				w.columns[dimension] = append(w.columns[dimension], bucket[x+dimension])
+			}
+		}
+		var end = len(w.columns[0])
+		for dimension := range w.windows {
+			w.windows[dimension] = append(w.windows[dimension], w.columns[dimension][start:end:end])
+		}
+	}
+	return w.windows
+}
+
+// Reduce every dimension of the window to a single value. The reduction
+// function at each position is given the values of the matching dimension
+// and a single function is applied to every dimension. The result contains
+// one value per dimension, with zero for any dimension without a function.
+func (w *VectorPolicy) Reduce(reducers ...func(Window) float64) []float64 {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	var windows = w.split()
+	var results = make([]float64, w.dimensions)
+	for dimension, window := range windows {
+		var f func(Window) float64
+		switch {
+		case len(reducers) == 1:
+			f = reducers[0]
+		case dimension < len(reducers):
+			f = reducers[dimension]
+		}
+		if f != nil {
+			results[dimension] = f(window)
+		}
+	}
+	return results
+}
+
+// Covariance returns the population covariance of two dimensions of the
+// vectors within the window and whether one could be computed. Unlike the
+// Covariance of two windows, the values are paired by vector rather than by
+// bucket so every point contributes. No covariance is available when the
+// window is empty or either dimension is out of range.
+func (w *VectorPolicy) Covariance(first int, second int) (float64, bool) {
+	if first < 0 || second < 0 || first >= w.dimensions || second >= w.dimensions {
+		return 0, false
+	}
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	w.ring.advance(w.ring.index(w.clock.Now()), w.clear)
+	if w.size < 1 {
+		return 0, false
+	}
+	// Accumulate the co-moment in a single pass as Welford does for the
+	// variance.
+	var count, xMean, yMean, comoment float64
+	for _, bucket := range w.buckets {
+		for x := 0; x < len(bucket); x = x + w.dimensions {
+			count = count + 1
+			var dx = bucket[x+first] - xMean
+			xMean = xMean + dx/count
+			yMean = yMean + (bucket[x+second]-yMean)/count
+			comoment = comoment + dx*(bucket[x+second]-yMean)
+		}
+	}
+	return comoment / count, true
+}
+
+// vectorDimension reduces a single dimension of a VectorPolicy.
+type vectorDimension struct {
+	policy    *VectorPolicy
+	dimension int
+}
+
+// Dimension returns a Reducer over a single dimension of the window so that
+// it may be used anywhere a Reducer is accepted, such as a Metric.
+func (w *VectorPolicy) Dimension(dimension int) Reducer {
+	return &vectorDimension{policy: w, dimension: dimension}
+}
+
+func (d *vectorDimension) Reduce(f func(Window) float64) float64 {
+	d.policy.lock.Lock()
+	defer d.policy.lock.Unlock()
+
+	return f(d.policy.split()[d.dimension])
+}
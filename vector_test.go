@@ -0,0 +1,70 @@
+package rolling
+
+import (
+	"testing"
+	"time"
+)
+
+func TestVectorPolicy(t *testing.T) {
+	var c = &testClock{now: time.Unix(0, 0)}
+	var p = NewVectorPolicy(3, 3, time.Second, WithClock(c))
+	p.Append(1, 10, 100)
+	p.Append(2, 20)
+	c.now = c.now.Add(time.Second)
+	p.Append(3, 30, 300, 3000)
+	if n := p.Len(); n != 3 {
+		t.Fatalf("expected 3 vectors but got %d", n)
+	}
+	var results = p.Reduce(Sum)
+	if len(results) != 3 || results[0] != 6 || results[1] != 60 || results[2] != 400 {
+		t.Fatalf("expected the sum of each dimension but got %v", results)
+	}
+	results = p.Reduce(Max, Min)
+	if results[0] != 3 || results[1] != 10 || results[2] != 0 {
+		t.Fatalf("expected a reducer per dimension but got %v", results)
+	}
+	var first = p.Reduce(func(w Window) float64 { return w[1][0] })
+	if first[0] != 1 || first[1] != 10 || first[2] != 100 {
+		t.Fatalf("expected buckets ordered from the oldest but got %v", first)
+	}
+	if count := p.Dimension(1).Reduce(Count); count != 3 {
+		t.Fatalf("expected 3 values in a single dimension but got %f", count)
+	}
+
+	c.now = c.now.Add(2 * time.Second)
+	if results = p.Reduce(Sum); results[0] != 3 || results[1] != 30 || results[2] != 300 {
+		t.Fatalf("expected the first bucket to expire but got %v", results)
+	}
+	p.AppendWithTimestamp([]float64{5, 5, 5}, time.Unix(0, 0))
+	if n := p.Len(); n != 1 {
+		t.Fatalf("expected a vector older than the window to be discarded but got %d", n)
+	}
+	c.now = c.now.Add(time.Hour)
+	if n := p.Len(); n != 0 {
+		t.Fatalf("expected an empty window but got %d", n)
+	}
+}
+
+func TestVectorPolicyCovariance(t *testing.T) {
+	var c = &testClock{now: time.Unix(0, 0)}
+	var p = NewVectorPolicy(3, 3, time.Second, WithClock(c))
+	if _, ok := p.Covariance(0, 1); ok {
+		t.Fatal("expected no covariance for an empty window")
+	}
+	for x := 1; x <= 4; x = x + 1 {
+		p.Append(float64(x), float64(-2*x), 7)
+		c.now = c.now.Add(500 * time.Millisecond)
+	}
+	if cov, ok := p.Covariance(0, 1); !ok || !floatMostlyEquals(cov, -2.5) {
+		t.Fatalf("expected a covariance of -2.5 but got %f %v", cov, ok)
+	}
+	if cov, ok := p.Covariance(0, 0); !ok || !floatMostlyEquals(cov, p.Dimension(0).Reduce(Variance)) {
+		t.Fatalf("expected the covariance of a dimension with itself to be its variance but got %f %v", cov, ok)
+	}
+	if cov, ok := p.Covariance(0, 2); !ok || cov != 0 {
+		t.Fatalf("expected no covariance with a constant dimension but got %f %v", cov, ok)
+	}
+	if _, ok := p.Covariance(0, 3); ok {
+		t.Fatal("expected no covariance for a dimension out of range")
+	}
+}
@@ -4,6 +4,20 @@ package rolling
 // with a Policy to populate it with data using some windowing policy.
 type Window [][]float64
 
+// Reducer is implemented by anything that gives reduction functions safe
+// access to a Window.
+type Reducer interface {
+	Reduce(f func(Window) float64) float64
+}
+
+// Policy is implemented by each windowing strategy in this package. A Policy
+// is responsible for populating a Window and for giving reduction functions
+// safe access to it.
+type Policy interface {
+	Reducer
+	Append(value float64)
+}
+
 // NewWindow creates a Window with the given number of buckets. The number of
 // buckets is meaningful to each Policy. The Policy implementations
 // will describe their use of buckets.
@@ -16,10 +30,71 @@ func NewWindow(buckets int) Window {
 // number of data points per-bucket can be estimated and/or when the desire is
 // to allocate a large slice so that allocations do not happen as the Window
 // is populated by a Policy.
+//
+// Every bucket is a segment of a single, contiguous allocation which reduces
+// the work of the allocator and keeps the values of neighboring buckets close
+// together in memory while they are iterated. A bucket that grows beyond its
+// preallocated size is moved to a new allocation of its own without affecting
+// its neighbors.
 func NewPreallocatedWindow(buckets int, bucketSize int) Window {
 	var w = NewWindow(buckets)
+	var slab = make([]float64, buckets*bucketSize)
 	for offset := range w {
-		w[offset] = make([]float64, 0, bucketSize)
+		var start = offset * bucketSize
+		w[offset] = slab[start : start : start+bucketSize]
 	}
 	return w
 }
+
+// minimumBucketLimit is the smallest capacity a bucket may grow to before it
+// is reallocated when cleared.
+const minimumBucketLimit = 64
+
+// bucketHint returns the capacity that the buckets of a window were
+// preallocated with.
+func bucketHint(w Window) int {
+	if len(w) < 1 {
+		return 0
+	}
+	return cap(w[0])
+}
+
+// defaultBucketLimit returns the capacity beyond which a bucket is
+// reallocated, rather than reused, when it is cleared.
+func defaultBucketLimit(hint int) int {
+	if 4*hint > minimumBucketLimit {
+		return 4 * hint
+	}
+	return minimumBucketLimit
+}
+
+// resetBucket empties a bucket. Buckets that have grown beyond the limit, such
+// as during a burst of data, are replaced with a new bucket of the hinted size
+// so that the memory used by the burst can be released.
+func resetBucket(bucket []float64, hint int, limit int) []float64 {
+	if cap(bucket) > limit {
+		return make([]float64, 0, hint)
+	}
+	return bucket[:0]
+}
+
+// Clone returns a deep copy of the window. The capacity of each bucket is not
+// preserved.
+func (w Window) Clone() Window {
+	var result = make(Window, len(w))
+	for offset, bucket := range w {
+		result[offset] = append(make([]float64, 0, len(bucket)), bucket...)
+	}
+	return result
+}
+
+// copyWindow returns a copy of the window of the given Reducer that is made
+// while its lock is held. The copy may be used after the lock is released.
+func copyWindow(r Reducer) Window {
+	var result Window
+	r.Reduce(func(w Window) float64 {
+		result = w.Clone()
+		return 0
+	})
+	return result
+}
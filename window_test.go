@@ -0,0 +1,20 @@
+package rolling
+
+import "testing"
+
+func TestPreallocatedWindowBucketsAreIndependent(t *testing.T) {
+	var w = NewPreallocatedWindow(3, 2)
+	for offset, bucket := range w {
+		if len(bucket) != 0 || cap(bucket) != 2 {
+			t.Fatalf("bucket %d has length %d and capacity %d", offset, len(bucket), cap(bucket))
+		}
+	}
+	w[1] = append(w[1], 4, 5)
+	w[0] = append(w[0], 1, 2, 3)
+	if len(w[1]) != 2 || w[1][0] != 4 || w[1][1] != 5 {
+		t.Fatalf("growing a bucket overwrote its neighbor: %v", w)
+	}
+	if len(w[0]) != 3 || w[0][2] != 3 {
+		t.Fatalf("bucket did not grow beyond its preallocated size: %v", w)
+	}
+}